@@ -18,38 +18,88 @@ package immutable
 
 import (
 	"context"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 // Backend implements accounts.Backend.
 // It is a simple backend that only supports one account.
-// The wallet is created on startup and never upated (Subscribe is noop).
+// The wallet is created on startup and only replaced when the backend is
+// reloaded, e.g. after the key held by the secret store has been rotated.
 type Backend struct {
+	store SecretStore
+
 	wallet accounts.Wallet
+	lock   sync.RWMutex
+
+	updateFeed  event.Feed
+	updateScope event.SubscriptionScope
 }
 
 // NewBackend constructs a backend instance using the secret store provided.
 func NewBackend(ctx context.Context, store SecretStore) (*Backend, error) {
-	key, err := store.GetPrivateKey(ctx)
+	wallet, err := loadWallet(ctx, store)
 	if err != nil {
 		return nil, err
 	}
 	return &Backend{
-		wallet: newWallet(key),
+		store:  store,
+		wallet: wallet,
 	}, nil
 }
 
+// loadWallet creates the wallet of the key held by the secret store.
+func loadWallet(ctx context.Context, store SecretStore) (accounts.Wallet, error) {
+	if ws, ok := store.(WalletStore); ok {
+		return ws.Wallet(ctx)
+	}
+	key, err := store.GetPrivateKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newWallet(key), nil
+}
+
 // Wallets returns the single wallet supported by this backend.
 func (b *Backend) Wallets() []accounts.Wallet {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
 	return []accounts.Wallet{b.wallet}
 }
 
-// Subscribe is noop for this backend because wallet never changes.
+// Subscribe creates an async subscription to receive notifications when the
+// wallet is replaced by a reload.
 func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
-	return event.NewSubscription(func(quit <-chan struct{}) error {
-		<-quit
-		return nil
-	})
+	return b.updateScope.Track(b.updateFeed.Subscribe(sink))
+}
+
+// Reload reads the key from the secret store again, bypassing any cache, and
+// returns the resulting wallet. If the key changed, the previous wallet is
+// dropped in favour of the new one and subscribers are notified.
+func (b *Backend) Reload(ctx context.Context) (accounts.Wallet, error) {
+	wallet, err := loadWallet(secrets.WithoutCache(ctx), b.store)
+	if err != nil {
+		return nil, err
+	}
+	if len(wallet.Accounts()) == 0 {
+		return nil, ErrNoAccount
+	}
+	b.lock.Lock()
+	previous := b.wallet
+	if previous.Accounts()[0].Address == wallet.Accounts()[0].Address {
+		b.lock.Unlock()
+		return previous, nil
+	}
+	b.wallet = wallet
+	b.lock.Unlock()
+
+	log.Info("Reloaded wallet", "previous", previous.Accounts()[0].Address, "address", wallet.Accounts()[0].Address)
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: previous, Kind: accounts.WalletDropped})
+	b.updateFeed.Send(accounts.WalletEvent{Wallet: wallet, Kind: accounts.WalletArrived})
+	return wallet, nil
 }
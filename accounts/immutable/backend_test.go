@@ -18,11 +18,13 @@ package immutable
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 func TestImmutableBackend_WithKeyStore_ValidAddress(t *testing.T) {
@@ -81,3 +83,59 @@ func TestImmutableBackend_WithKeyStore_SubscribeNoOp(t *testing.T) {
 		}
 	}
 }
+
+// memoryStore is a SecretStore holding a replaceable key in memory.
+type memoryStore struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s *memoryStore) GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	return s.key, nil
+}
+
+func TestImmutableBackend_Reload(t *testing.T) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &memoryStore{key: key}
+	b, err := NewBackend(ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	am := accounts.NewManager(&accounts.Config{}, b)
+	defer am.Close()
+
+	// Reloading an unchanged key keeps the wallet
+	previous := b.Wallets()[0]
+	wallet, err := b.Reload(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wallet != previous {
+		t.Fatal("expected wallet to be kept")
+	}
+	// Reloading a rotated key replaces the wallet in the account manager
+	if store.key, err = crypto.GenerateKey(); err != nil {
+		t.Fatal(err)
+	}
+	if wallet, err = b.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(store.key.PublicKey)
+	if wallet.Accounts()[0].Address != address {
+		t.Fatalf("expected address %s, got %s", address, wallet.Accounts()[0].Address)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		accs := am.Accounts()
+		if len(accs) == 1 && accs[0] == address {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected account manager to hold %s, got %v", address, accs)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -21,4 +21,10 @@ import "errors"
 var (
 	// ErrInvalidAccount is returned when a wallet is asked to sign some data for an account it does not contain.
 	ErrInvalidAccount = errors.New("account specified is not provided by this wallet")
+	// ErrKeyNotExportable is returned when the private key of a store is held by a remote signer.
+	ErrKeyNotExportable = errors.New("private key is held by a remote signer")
+	// ErrInvalidRemoteSignature is returned when a remote signer returns a signature that does not match the account.
+	ErrInvalidRemoteSignature = errors.New("invalid signature from remote signer")
+	// ErrNoAccount is returned when a reloaded wallet does not provide any account.
+	ErrNoAccount = errors.New("wallet provides no account")
 )
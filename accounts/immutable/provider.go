@@ -22,35 +22,32 @@ import (
 	"encoding/hex"
 	"fmt"
 
-	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
-// AWSSecretsManagerStore implements the SecretStore interface.
-// It uses an AWS Secrets Manager client to retrieve the private key intended
-// to be used by a wallet.
-type AWSSecretsManagerStore struct {
-	sm                 *aws.SecretsManager
-	validatorSecretKey string
+// ProviderStore implements the SecretStore interface.
+// It retrieves the private key intended to be used by a wallet from a secret
+// provider, such as AWS Secrets Manager or Vault.
+type ProviderStore struct {
+	provider secrets.Provider
+	secretID string
 }
 
-// NewAWSSecretsManagerStore instantiates a new AWSSecretsManagerStore.
-func NewAWSSecretsManagerStore(region, validatorSecretKey string) (*AWSSecretsManagerStore, error) {
-	sm, err := aws.NewSecretsManager(region)
-	if err != nil {
-		return nil, err
+// NewProviderStore instantiates a new ProviderStore reading the key stored
+// under the secret ID.
+func NewProviderStore(provider secrets.Provider, secretID string) *ProviderStore {
+	return &ProviderStore{
+		provider: provider,
+		secretID: secretID,
 	}
-	return &AWSSecretsManagerStore{
-		sm:                 sm,
-		validatorSecretKey: validatorSecretKey,
-	}, nil
 }
 
 // GetPrivateKey returns the wallet's private key after retrieving its hex-encoded format from
-// AWS Secrets Manager.
-func (s *AWSSecretsManagerStore) GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
-	// Get key from AWS Secrets Manager
-	privKeyHex, err := s.sm.GetSecret(ctx, s.validatorSecretKey)
+// the secret provider.
+func (s *ProviderStore) GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	// Get key from the provider
+	privKeyHex, err := s.provider.GetSecret(ctx, s.secretID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get priv key from store: %w", err)
 	}
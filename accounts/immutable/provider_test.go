@@ -0,0 +1,84 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package immutable
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestImmutableProviderStore_Reload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	provider, err := secrets.Open("file://" + dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another process, such as the rotate-key command, writes to the same
+	// backend without going through the node's cache
+	writer, err := secrets.Open("file://" + dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 2)
+	put := func(i int, provider secrets.Provider) {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = crypto.PubkeyToAddress(key.PublicKey).Hex()
+		if err := provider.PutSecretString("validator", hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(0, provider)
+	b, err := NewBackend(ctx, NewProviderStore(provider, "validator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := b.Wallets()[0].Accounts()[0].Address.Hex(); addr != keys[0] {
+		t.Fatalf("expected address %s, got %s", keys[0], addr)
+	}
+	put(1, writer)
+
+	// The node's cache still holds the previous key
+	cached, err := NewBackend(ctx, NewProviderStore(provider, "validator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := cached.Wallets()[0].Accounts()[0].Address.Hex(); addr != keys[0] {
+		t.Fatalf("expected cached address %s, got %s", keys[0], addr)
+	}
+	// Reloading bypasses the cache and swaps the wallet
+	wallet, err := b.Reload(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := wallet.Accounts()[0].Address.Hex(); addr != keys[1] {
+		t.Fatalf("expected reloaded address %s, got %s", keys[1], addr)
+	}
+	if addr := b.Wallets()[0].Accounts()[0].Address.Hex(); addr != keys[1] {
+		t.Fatalf("expected address %s after reload, got %s", keys[1], addr)
+	}
+	if _, err := NewBackend(ctx, NewProviderStore(provider, "missing")); !secrets.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
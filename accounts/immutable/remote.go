@@ -0,0 +1,207 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package immutable
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// remoteSignTimeout bounds every signing request sent to the remote signer, so
+// that an unresponsive signer cannot stall block sealing indefinitely.
+const remoteSignTimeout = 5 * time.Second
+
+// RemoteSignerStore implements the SecretStore and WalletStore interfaces on top
+// of an external signer speaking the Clef account_* protocol over IPC or HTTP.
+// The private key never leaves the signer process: GetPrivateKey always fails
+// and signing is delegated to account_signData.
+type RemoteSignerStore struct {
+	client   *rpc.Client
+	endpoint string
+	address  common.Address
+	version  string
+}
+
+// NewRemoteSignerStore connects to the external signer at the endpoint and
+// binds the store to the given account, which the signer must manage. If the
+// address is the zero address, the signer must manage exactly one account.
+func NewRemoteSignerStore(ctx context.Context, endpoint string, address common.Address) (*RemoteSignerStore, error) {
+	client, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial remote signer: %w", err)
+	}
+	var version string
+	if err := client.CallContext(ctx, &version, "account_version"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to reach remote signer: %w", err)
+	}
+	var addresses []common.Address
+	if err := client.CallContext(ctx, &addresses, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list remote signer accounts: %w", err)
+	}
+	switch {
+	case address == (common.Address{}) && len(addresses) == 1:
+		address = addresses[0]
+	case address == (common.Address{}):
+		client.Close()
+		return nil, fmt.Errorf("remote signer manages %d accounts, an address must be specified", len(addresses))
+	case !slices.Contains(addresses, address):
+		client.Close()
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccount, address)
+	}
+	return &RemoteSignerStore{
+		client:   client,
+		endpoint: endpoint,
+		address:  address,
+		version:  version,
+	}, nil
+}
+
+// GetPrivateKey always fails as the key is held by the remote signer.
+func (s *RemoteSignerStore) GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	return nil, ErrKeyNotExportable
+}
+
+// Wallet returns a wallet that signs through the remote signer.
+func (s *RemoteSignerStore) Wallet(ctx context.Context) (accounts.Wallet, error) {
+	return &remoteWallet{
+		store: s,
+		account: accounts.Account{
+			Address: s.address,
+			URL:     accounts.URL{Scheme: "extapi", Path: s.endpoint},
+		},
+	}, nil
+}
+
+// Close disconnects from the remote signer.
+func (s *RemoteSignerStore) Close() {
+	s.client.Close()
+}
+
+// remoteWallet is a single account wallet whose signatures are produced by a
+// remote signer.
+type remoteWallet struct {
+	store   *RemoteSignerStore
+	account accounts.Account
+}
+
+func (w *remoteWallet) Accounts() []accounts.Account {
+	return []accounts.Account{w.account}
+}
+
+func (w *remoteWallet) Contains(account accounts.Account) bool {
+	return w.account.Address == account.Address
+}
+
+// SignData requests a signature from the remote signer. The signature is
+// verified against the wallet's address before it is returned, and its V value
+// is normalised to 0/1.
+func (w *remoteWallet) SignData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, ErrInvalidAccount
+	}
+	return w.sign(mimeType, data, crypto.Keccak256(data))
+}
+
+func (w *remoteWallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	if !w.Contains(account) {
+		return nil, ErrInvalidAccount
+	}
+	return w.sign(accounts.MimetypeTextPlain, text, accounts.TextHash(text))
+}
+
+// sign sends data to the remote signer and checks that the returned signature
+// over hash recovers to the wallet's address.
+func (w *remoteWallet) sign(mimeType string, data []byte, hash []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteSignTimeout)
+	defer cancel()
+
+	var (
+		sig         hexutil.Bytes
+		signAddress = common.NewMixedcaseAddress(w.account.Address)
+	)
+	// Need to use the address pointer here, because of how MarshalJSON is defined
+	if err := w.store.client.CallContext(ctx, &sig, "account_signData", mimeType, &signAddress, hexutil.Encode(data)); err != nil {
+		return nil, fmt.Errorf("remote signer failed to sign: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrInvalidRemoteSignature, len(sig))
+	}
+	if sig[crypto.RecoveryIDOffset] == 27 || sig[crypto.RecoveryIDOffset] == 28 {
+		sig[crypto.RecoveryIDOffset] -= 27 // Transform V from 27/28 to 0/1
+	}
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRemoteSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != w.account.Address {
+		return nil, fmt.Errorf("%w: signed by %s", ErrInvalidRemoteSignature, signer)
+	}
+	return sig, nil
+}
+
+func (w *remoteWallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, errors.New("transaction signing not supported on remote immutable signers")
+}
+
+func (w *remoteWallet) SignTextWithPassphrase(account accounts.Account, passphrase string, text []byte) ([]byte, error) {
+	return []byte{}, errors.New("password-operations not supported on immutable signers")
+}
+
+func (w *remoteWallet) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, errors.New("password-operations not supported on immutable signers")
+}
+
+func (w *remoteWallet) SignDataWithPassphrase(account accounts.Account, passphrase, mimeType string, data []byte) ([]byte, error) {
+	return nil, errors.New("password-operations not supported on immutable signers")
+}
+
+func (w *remoteWallet) Open(passphrase string) error {
+	return errors.New("operation not supported on immutable signers")
+}
+
+func (w *remoteWallet) Close() error {
+	return errors.New("operation not supported on immutable signers")
+}
+
+func (w *remoteWallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	return accounts.Account{}, errors.New("operation not supported on immutable signers")
+}
+
+func (w *remoteWallet) SelfDerive(bases []accounts.DerivationPath, chain ethereum.ChainStateReader) {}
+
+func (w *remoteWallet) Status() (string, error) {
+	return fmt.Sprintf("ok [version=%v]", w.store.version), nil
+}
+
+func (w *remoteWallet) URL() accounts.URL {
+	return w.account.URL
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package immutable
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeSigner mimics the subset of the Clef account API used by the remote
+// signer store. It signs with signKey, which may differ from the advertised
+// account to simulate a misbehaving signer.
+type fakeSigner struct {
+	account common.Address
+	signKey *ecdsa.PrivateKey
+}
+
+func (s *fakeSigner) Version() string {
+	return "6.1.0"
+}
+
+func (s *fakeSigner) List() []common.Address {
+	return []common.Address{s.account}
+}
+
+func (s *fakeSigner) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if addr.Address() != s.account {
+		return nil, errors.New("unknown account")
+	}
+	hash := crypto.Keccak256(data)
+	if contentType == accounts.MimetypeTextPlain {
+		hash = accounts.TextHash(data)
+	}
+	sig, err := crypto.Sign(hash, s.signKey)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Clef returns V as 27/28
+	return sig, nil
+}
+
+func newFakeSignerServer(t *testing.T, account common.Address, signKey *ecdsa.PrivateKey) string {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &fakeSigner{account: account, signKey: signKey}); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func TestImmutableRemoteSigner_SignData(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	endpoint := newFakeSignerServer(t, address, key)
+
+	// Initialize store without an address, the only account is selected
+	store, err := NewRemoteSignerStore(context.Background(), endpoint, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if _, err := store.GetPrivateKey(context.Background()); !errors.Is(err, ErrKeyNotExportable) {
+		t.Fatalf("expected %v, got %v", ErrKeyNotExportable, err)
+	}
+	// Initialize backend
+	b, err := NewBackend(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallets := b.Wallets()
+	if len(wallets) != 1 || len(wallets[0].Accounts()) != 1 {
+		t.Fatalf("expected 1 wallet with 1 account")
+	}
+	account := wallets[0].Accounts()[0]
+	if account.Address != address {
+		t.Fatalf("expected address %s, got %s", address, account.Address)
+	}
+	// Sign a clique seal and check it recovers to the account
+	data := []byte("clique header rlp")
+	sig, err := wallets[0].SignData(account, accounts.MimetypeClique, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		t.Fatalf("expected V to be normalised, got %d", sig[crypto.RecoveryIDOffset])
+	}
+	pubkey, err := crypto.SigToPub(crypto.Keccak256(data), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pubkey) != address {
+		t.Fatalf("signature does not recover to %s", address)
+	}
+	// Sign text
+	if _, err := wallets[0].SignText(account, []byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	// Unknown accounts are rejected
+	if _, err := wallets[0].SignData(accounts.Account{}, accounts.MimetypeClique, data); !errors.Is(err, ErrInvalidAccount) {
+		t.Fatalf("expected %v, got %v", ErrInvalidAccount, err)
+	}
+}
+
+func TestImmutableRemoteSigner_UnknownAccount(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	endpoint := newFakeSignerServer(t, crypto.PubkeyToAddress(key.PublicKey), key)
+
+	_, err := NewRemoteSignerStore(context.Background(), endpoint, common.HexToAddress("0x01"))
+	if !errors.Is(err, ErrInvalidAccount) {
+		t.Fatalf("expected %v, got %v", ErrInvalidAccount, err)
+	}
+}
+
+func TestImmutableRemoteSigner_WrongSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	endpoint := newFakeSignerServer(t, address, other)
+
+	store, err := NewRemoteSignerStore(context.Background(), endpoint, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	b, err := NewBackend(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := b.Wallets()[0]
+	if _, err := wallet.SignData(wallet.Accounts()[0], accounts.MimetypeClique, []byte("data")); !errors.Is(err, ErrInvalidRemoteSignature) {
+		t.Fatalf("expected %v, got %v", ErrInvalidRemoteSignature, err)
+	}
+}
@@ -19,6 +19,8 @@ package immutable
 import (
 	"context"
 	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/accounts"
 )
 
 // SecretStore is an interface that allows the backend to retrieve data it needs
@@ -26,3 +28,9 @@ import (
 type SecretStore interface {
 	GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error)
 }
+
+// WalletStore is implemented by secret stores which do not expose the private
+// key and instead provide a wallet that signs on the key holder's behalf.
+type WalletStore interface {
+	Wallet(ctx context.Context) (accounts.Wallet, error)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/keys"
 	inode "github.com/ethereum/go-ethereum/cmd/geth/immutable/node"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -355,9 +356,33 @@ func setAccountManagerBackends(conf *node.Config, am *accounts.Manager, keydir s
 		am.AddBackend(backend)
 		return nil
 	}
-	// CHANGE(immutable): Add immutable backend with AWS Secrets Manager
-	awsRegion := os.Getenv("GETH_FLAG_IMMUTABLE_AWS_REGION")
-	if len(awsRegion) > 0 {
+	// CHANGE(immutable): Add immutable backend with a remote signer holding the sealing key
+	remoteSigner := os.Getenv("GETH_FLAG_IMMUTABLE_REMOTE_SIGNER")
+	if len(remoteSigner) > 0 {
+		var address common.Address
+		if addr := os.Getenv("GETH_FLAG_IMMUTABLE_REMOTE_SIGNER_ADDRESS"); len(addr) > 0 {
+			if !common.IsHexAddress(addr) {
+				return fmt.Errorf("invalid remote signer address: %s", addr)
+			}
+			address = common.HexToAddress(addr)
+		}
+		log.Info("Using immutable backend with remote signer", "url", remoteSigner, "address", address)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		store, err := immutable.NewRemoteSignerStore(ctx, remoteSigner, address)
+		if err != nil {
+			return fmt.Errorf("error connecting to remote signer: %v", err)
+		}
+		backend, err := immutable.NewBackend(ctx, store)
+		if err != nil {
+			store.Close()
+			return fmt.Errorf("error creating immutable backend: %v", err)
+		}
+		am.AddBackend(backend)
+		return nil
+	}
+	// CHANGE(immutable): Add immutable backend with a secret provider, e.g. AWS Secrets Manager or Vault
+	if providerURI := keys.SecretProviderURI(); len(providerURI) > 0 {
 		// Read pod name for validator ordinal
 		podOrdinal, err := ordinalFromPodNameEnvVar()
 		if err != nil {
@@ -372,16 +397,16 @@ func setAccountManagerBackends(conf *node.Config, am *accounts.Manager, keydir s
 		if err != nil {
 			return err
 		}
-		// Initialize AWS Secrets Manager Store
-		log.Info("Using immutable backend with AWS Secrets Manager", "region", awsRegion, "validator", validatorName)
-		store, err := immutable.NewAWSSecretsManagerStore(awsRegion, validatorSecretID)
+		// Initialize secret provider store
+		log.Info("Using immutable backend with secret provider", "provider", providerURI, "validator", validatorName)
+		provider, err := secrets.Open(providerURI)
 		if err != nil {
-			return fmt.Errorf("failed to create AWS SecretsManager Store: %v ", err)
+			return fmt.Errorf("failed to open secret provider: %v ", err)
 		}
 		// Initialize and register backend
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		backend, err := immutable.NewBackend(ctx, store)
+		backend, err := immutable.NewBackend(ctx, immutable.NewProviderStore(provider, validatorSecretID))
 		if err != nil {
 			return fmt.Errorf("error creating immutable backend: %v", err)
 		}
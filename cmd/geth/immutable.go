@@ -52,6 +52,8 @@ Local bootstrap will also run the validator node that is configured by the boots
 							utils.SyncModeFlag,
 							configFileFlag,
 							utils.GCModeFlag,
+							immutable.Artefacts,
+							immutable.ArtefactKeyFilepath,
 						}),
 					},
 					{
@@ -61,6 +63,7 @@ Local bootstrap will also run the validator node that is configured by the boots
 						Flags: flags.Merge([]cli.Flag{
 							immutable.Role,
 							immutable.Region,
+							immutable.SecretProvider,
 							utils.ImmutableNetworkFlag,
 							immutable.DataDirpath,
 						}),
@@ -72,6 +75,8 @@ Local bootstrap will also run the validator node that is configured by the boots
 						Flags: flags.Merge([]cli.Flag{
 							utils.ImmutableNetworkFlag,
 							immutable.DataDirpath,
+							immutable.Artefacts,
+							immutable.ArtefactSigners,
 						}),
 					},
 				},
@@ -88,6 +93,10 @@ Local bootstrap will also run the validator node that is configured by the boots
 							immutable.Region,
 							utils.ListenPortFlag,
 							utils.KeyStoreDirFlag,
+							immutable.RegistryFilepath,
+							immutable.RegistryPublicAddr,
+							utils.HTTPListenAddrFlag,
+							utils.HTTPPortFlag,
 						}),
 					},
 				},
@@ -100,6 +109,7 @@ Local bootstrap will also run the validator node that is configured by the boots
 					immutable.Role,
 					immutable.ConfigFilepath,
 					immutable.DataDirpath,
+					immutable.SecretProvider,
 				}),
 			},
 			{
@@ -109,6 +119,69 @@ Local bootstrap will also run the validator node that is configured by the boots
 				Action:    runRewindChainCommand,
 				Flags: flags.Merge([]cli.Flag{
 					immutable.OverrideFlag(immutable.DataDirpath, true),
+					immutable.DryRun,
+					immutable.Force,
+					immutable.Operator,
+					immutable.Reason,
+				}),
+			},
+			{
+				Name:  "artefacts",
+				Usage: "manage the signed configuration artefacts fetched by bootstrap",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "publish",
+						Usage:     "publish files as the artefacts of a network, named after the files unless given as <name>=<file>",
+						ArgsUsage: "[<name>=]<file> [[<name>=]<file>...]",
+						Action:    publishArtefactsCommand,
+						Flags: flags.Merge([]cli.Flag{
+							utils.ImmutableNetworkFlag,
+							immutable.Artefacts,
+							immutable.ArtefactKeyFilepath,
+						}),
+					},
+				},
+			},
+			{
+				Name:  "forks",
+				Usage: "inspect competing forks quarantined by the reorg invariant and the fork schedule",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "schedule",
+						Usage:  "print the fork schedule with countdowns to upcoming activations",
+						Action: runScheduleForksCommand,
+						Flags: flags.Merge([]cli.Flag{
+							utils.ImmutableNetworkFlag,
+							utils.OverrideForks,
+						}),
+					},
+					{
+						Name:   "list",
+						Usage:  "list quarantined forks",
+						Action: runListForksCommand,
+						Flags: flags.Merge([]cli.Flag{
+							immutable.OverrideFlag(immutable.DataDirpath, true),
+						}),
+					},
+					{
+						Name:      "diff",
+						Usage:     "diff the headers, signers and transactions of a quarantined fork against the canonical chain",
+						ArgsUsage: "<forkId>",
+						Action:    runDiffForkCommand,
+						Flags: flags.Merge([]cli.Flag{
+							immutable.OverrideFlag(immutable.DataDirpath, true),
+						}),
+					},
+				},
+			},
+			{
+				Name:   "verify",
+				Usage:  "verify that a datadir matches the genesis, chain config and validator set of an Immutable network",
+				Action: runVerifyCommand,
+				Flags: flags.Merge([]cli.Flag{
+					immutable.OverrideFlag(immutable.DataDirpath, true),
+					utils.ImmutableNetworkFlag,
+					immutable.OverrideFlag(immutable.ValidatorSetFilepath, false),
 				}),
 			},
 			{
@@ -119,6 +192,20 @@ Local bootstrap will also run the validator node that is configured by the boots
 					immutable.PublicKey,
 				}),
 			},
+			{
+				Name:   "rotate-key",
+				Usage:  "rotate the sealing key of a running validator through clique votes",
+				Action: rotateKeyCommand,
+				Flags: flags.Merge([]cli.Flag{
+					immutable.Region,
+					immutable.SecretProvider,
+					immutable.SecretID,
+					immutable.ValidatorURL,
+					immutable.Voters,
+					immutable.VoteTimeout,
+					immutable.VotePollInterval,
+				}),
+			},
 			{
 				Name:  "vote",
 				Usage: "vote in or out new validators by connecting to multiple validators simultaneously",
@@ -141,6 +228,18 @@ Local bootstrap will also run the validator node that is configured by the boots
 							immutable.ValidatorAddress,
 						}),
 					},
+					{
+						Name:   "apply",
+						Usage:  "vote the validator set towards the set listed in a file",
+						Action: applyValidatorSetCommand,
+						Flags: flags.Merge([]cli.Flag{
+							immutable.Voters,
+							immutable.ValidatorSetFilepath,
+							immutable.DryRun,
+							immutable.VoteTimeout,
+							immutable.VotePollInterval,
+						}),
+					},
 				},
 			},
 		},
@@ -0,0 +1,101 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package genesis
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Premine contains addresses and amounts of premined balances
+type Premine struct {
+	Address common.Address
+	Wei     *big.Int
+}
+
+// CliqueOptions contains the options for generating a clique genesis
+type CliqueOptions struct {
+	ChainID         int
+	GasLimit        uint64
+	SecondsPerBlock uint64
+
+	Validators []common.Address
+	Premines   []Premine
+}
+
+// Clique returns the genesis of a clique chain configured like an Immutable
+// zkEVM network, sealed by the given validators.
+func Clique(opts CliqueOptions) *core.Genesis {
+	// To encode the signer addresses in extradata,
+	// concatenate 32 zero bytes, all signer addresses and 65 further zero bytes.
+	// This is based on the clique spec: https://eips.ethereum.org/EIPS/eip-225
+	extraDataLength := crypto.DigestLength + common.AddressLength*len(opts.Validators) + crypto.SignatureLength
+	extraData := make([]byte, extraDataLength)
+	for i := range opts.Validators {
+		from := crypto.DigestLength + common.AddressLength*i
+		to := from + common.AddressLength
+		copy(extraData[from:to], opts.Validators[i].Bytes())
+	}
+
+	// Premine
+	alloc := types.GenesisAlloc{}
+	for i := range opts.Premines {
+		alloc[opts.Premines[i].Address] = types.Account{
+			Balance: opts.Premines[i].Wei,
+		}
+	}
+
+	return &core.Genesis{
+		Config: &params.ChainConfig{
+			ChainID:             new(big.Int).SetUint64(uint64(opts.ChainID)),
+			HomesteadBlock:      common.Big0,
+			EIP150Block:         common.Big0,
+			EIP155Block:         common.Big0,
+			EIP158Block:         common.Big0,
+			ByzantiumBlock:      common.Big0,
+			ConstantinopleBlock: common.Big0,
+			PetersburgBlock:     common.Big0,
+			IstanbulBlock:       common.Big0,
+			MuirGlacierBlock:    common.Big0,
+			BerlinBlock:         common.Big0,
+			LondonBlock:         common.Big0,
+			ArrowGlacierBlock:   common.Big0,
+			GrayGlacierBlock:    common.Big0,
+			MergeNetsplitBlock:  common.Big0,
+			Clique: &params.CliqueConfig{
+				Period: opts.SecondsPerBlock,
+				Epoch:  30000,
+			},
+			IsReorgBlocked: true,
+			// So as to reflect devnet, testnet, and mainnet, these forks should not be enabled in genesis.
+			ShanghaiTime:   nil,
+			PrevrandaoTime: nil,
+			CancunTime:     nil, // If genesis block has Cancun enabled, you must set the blob-related headers too.
+		},
+
+		Difficulty: big.NewInt(1),
+		GasLimit:   opts.GasLimit,
+		Mixhash:    common.Hash{},
+		ExtraData:  extraData,
+		Alloc:      alloc,
+	}
+}
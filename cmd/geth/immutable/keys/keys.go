@@ -25,7 +25,8 @@ import (
 	"os"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -38,11 +39,20 @@ const (
 	SecretKeyEnvVar = "KEY_PATH"
 	// RolePlaceHolder is the placeholder for the role in the secret ID template.
 	RolePlaceHolder = "{POD_ROLE}"
+	// SecretProviderEnvVar is the environment variable used to retrieve the URI of the secret provider.
+	SecretProviderEnvVar = "GETH_FLAG_IMMUTABLE_SECRETS"
+	// AWSRegionEnvVar is the environment variable used to select AWS Secrets Manager in the given
+	// region when no secret provider URI is set.
+	AWSRegionEnvVar = "GETH_FLAG_IMMUTABLE_AWS_REGION"
+	// StagedSecretSuffix is appended to the secret ID of a node to stage a new
+	// private key while its current one is still in use.
+	StagedSecretSuffix = "-staged"
 )
 
 var (
 	ErrPrivateKeyInvalid  = errors.New("private key is invalid")
 	ErrPrivateKeyNotFound = errors.New("private key is not found")
+	ErrPrivateKeyMismatch = errors.New("private key does not belong to the expected address")
 )
 
 // SecretIDTemplate retrieves the path to the secret key from the environment.
@@ -54,6 +64,18 @@ func SecretIDTemplate() (string, error) {
 	return path, nil
 }
 
+// SecretProviderURI retrieves the URI of the secret provider from the environment.
+// It is empty if neither a provider URI nor an AWS region is set.
+func SecretProviderURI() string {
+	if uri := os.Getenv(SecretProviderEnvVar); uri != "" {
+		return uri
+	}
+	if region := os.Getenv(AWSRegionEnvVar); region != "" {
+		return "aws://" + region
+	}
+	return ""
+}
+
 // SecretID is the identifier used to store a private key (decrypted keystore) in a secure remote store.
 // The secret path must contain the placeholder {POD_ROLE}, which will be populated role of the node.
 // They secret path must also be scoped to the correct environment.
@@ -65,10 +87,7 @@ func SecretID(secretIDTemplate, owner string) (string, error) {
 }
 
 // Store is used to retrieve and store private keys
-type Store interface {
-	PutSecretString(key, content string) error
-	GetSecret(ctx context.Context, id string) (string, error)
-}
+type Store = secrets.Provider
 
 // Render will retrieve or create a private key for a node.
 // The address pertaining to the key will be logged.
@@ -80,7 +99,7 @@ func Render(
 	// Try and pull existing private key
 	existingKeyHex, err := store.GetSecret(ctx, secretID)
 	if err != nil {
-		if aws.IsNotFound(err) {
+		if secrets.IsNotFound(err) {
 			return nil, errors.Join(ErrPrivateKeyNotFound, err)
 		}
 		return nil, err
@@ -130,3 +149,77 @@ func Retrieve(
 	}
 	return privKey, nil
 }
+
+// StagedSecretID returns the ID under which a new private key is staged for the
+// node whose key is stored under the given secret ID.
+func StagedSecretID(secretID string) string {
+	return secretID + StagedSecretSuffix
+}
+
+// Stage will generate a new private key for a node and store it under the
+// staged secret ID, leaving the node's key in place until the new one is
+// promoted. The key in the store must belong to the expected address, so that
+// a key is never staged for another node by mistake.
+func Stage(
+	ctx context.Context,
+	store Store,
+	secretID string,
+	expected common.Address,
+) (*ecdsa.PrivateKey, error) {
+	if _, err := retrieveExpected(ctx, store, secretID, expected); err != nil {
+		return nil, err
+	}
+	newKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate random private key: %w", err)
+	}
+	if err := store.PutSecretString(StagedSecretID(secretID), hex.EncodeToString(crypto.FromECDSA(newKey))); err != nil {
+		return nil, fmt.Errorf("failed to push staged private key to secrets manager: %w", err)
+	}
+	logPublicKey(&newKey.PublicKey)
+	return newKey, nil
+}
+
+// Promote will replace the private key of a node with its staged key, and empty
+// the staged secret. The key in the store must still belong to the expected
+// address. It returns the promoted key.
+func Promote(
+	ctx context.Context,
+	store Store,
+	secretID string,
+	expected common.Address,
+) (*ecdsa.PrivateKey, error) {
+	if _, err := retrieveExpected(ctx, store, secretID, expected); err != nil {
+		return nil, err
+	}
+	stagedID := StagedSecretID(secretID)
+	staged, err := Retrieve(ctx, store, stagedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve staged private key: %w", err)
+	}
+	if err := store.PutSecretString(secretID, hex.EncodeToString(crypto.FromECDSA(staged))); err != nil {
+		return nil, fmt.Errorf("failed to push private key to secrets manager: %w", err)
+	}
+	// The staged key is live now, so failing to empty its secret is not fatal
+	if err := store.PutSecretString(stagedID, EmptyPrivateKeySecretValue); err != nil {
+		log.Warn("Failed to empty staged private key", "secret", stagedID, "err", err)
+	}
+	return staged, nil
+}
+
+// retrieveExpected will retrieve a private key from the store, which must
+// belong to the expected address.
+func retrieveExpected(ctx context.Context, store Store, secretID string, expected common.Address) (*ecdsa.PrivateKey, error) {
+	keyHex, err := store.GetSecret(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve private key: %w", err)
+	}
+	key, err := crypto.HexToECDSA(keyHex)
+	if err != nil {
+		return nil, errors.Join(ErrPrivateKeyInvalid, err)
+	}
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != expected {
+		return nil, fmt.Errorf("%w: stored key belongs to %s, expected %s", ErrPrivateKeyMismatch, addr, expected)
+	}
+	return key, nil
+}
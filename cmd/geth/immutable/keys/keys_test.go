@@ -0,0 +1,88 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keys
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type mapStore map[string]string
+
+func (s mapStore) PutSecretString(key, content string) error {
+	s[key] = content
+	return nil
+}
+
+func (s mapStore) GetSecret(ctx context.Context, id string) (string, error) {
+	return s[id], nil
+}
+
+func TestStagePromote(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyHex := hex.EncodeToString(crypto.FromECDSA(key))
+	store := mapStore{"validator-0": keyHex}
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	// The stored key must belong to the expected address
+	if _, err := Stage(context.Background(), store, "validator-0", common.Address{0x01}); !errors.Is(err, ErrPrivateKeyMismatch) {
+		t.Fatalf("expected %v, got %v", ErrPrivateKeyMismatch, err)
+	}
+	if _, ok := store[StagedSecretID("validator-0")]; ok {
+		t.Fatal("key staged despite mismatch")
+	}
+	// The new key is staged, leaving the current one in place
+	newKey, err := Stage(context.Background(), store, "validator-0", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(newKey.PublicKey) == addr {
+		t.Fatal("expected a new key")
+	}
+	newKeyHex := hex.EncodeToString(crypto.FromECDSA(newKey))
+	if store["validator-0"] != keyHex {
+		t.Fatal("current key replaced by staging")
+	}
+	if store[StagedSecretID("validator-0")] != newKeyHex {
+		t.Fatal("new key not staged")
+	}
+	// The staged key replaces the current one once promoted
+	if _, err := Promote(context.Background(), store, "validator-0", common.Address{0x01}); !errors.Is(err, ErrPrivateKeyMismatch) {
+		t.Fatalf("expected %v, got %v", ErrPrivateKeyMismatch, err)
+	}
+	promoted, err := Promote(context.Background(), store, "validator-0", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !promoted.Equal(newKey) {
+		t.Fatal("promoted key is not the staged one")
+	}
+	if store["validator-0"] != newKeyHex {
+		t.Fatal("new key not promoted")
+	}
+	if store[StagedSecretID("validator-0")] != EmptyPrivateKeySecretValue {
+		t.Fatal("staged key not emptied")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// API is the admin RPC API of the registry, served by the boot node in the
+// admin namespace.
+type API struct {
+	registry *Registry
+}
+
+// NewAPI creates the admin RPC API of the registry.
+func NewAPI(registry *Registry) *API {
+	return &API{registry: registry}
+}
+
+// AddMember adds the node, given as an enode URL or a hex node ID, with the
+// role, or changes the role of a member.
+func (api *API) AddMember(node string, role string) (bool, error) {
+	id, err := parseNodeID(node)
+	if err != nil {
+		return false, err
+	}
+	if err := api.registry.Add(id, role); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RevokeMember removes the node, given as an enode URL or a hex node ID. It is
+// evicted from the discovery table when next revalidated.
+func (api *API) RevokeMember(node string) (bool, error) {
+	id, err := parseNodeID(node)
+	if err != nil {
+		return false, err
+	}
+	if err := api.registry.Revoke(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Registry returns the signed registry, which can be verified against the
+// enode of the boot node.
+func (api *API) Registry() (*Signed, error) {
+	return api.registry.Signed()
+}
+
+// PublicAPI is the read-only part of the registry API, served to the nodes
+// fetching the registry. The registry is signed, so it needs no protection.
+type PublicAPI struct {
+	registry *Registry
+}
+
+// NewPublicAPI creates the read-only RPC API of the registry.
+func NewPublicAPI(registry *Registry) *PublicAPI {
+	return &PublicAPI{registry: registry}
+}
+
+// Registry returns the signed registry, which can be verified against the
+// enode of the boot node.
+func (api *PublicAPI) Registry() (*Signed, error) {
+	return api.registry.Signed()
+}
+
+// parseNodeID parses an enode URL or a hex node ID.
+func parseNodeID(node string) (enode.ID, error) {
+	if strings.HasPrefix(node, "enode://") {
+		n, err := enode.ParseV4(node)
+		if err != nil {
+			return enode.ID{}, err
+		}
+		return n.ID(), nil
+	}
+	return enode.ParseID(node)
+}
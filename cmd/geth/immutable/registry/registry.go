@@ -0,0 +1,291 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package registry keeps the registry of the nodes allowed to take part in
+// discovery through an Immutable boot node, along with their roles. The
+// registry is signed by the boot node's key, so that it can be verified
+// against the boot node's enode.
+package registry
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+const version = 1
+
+var (
+	// ErrNotMember is returned when revoking a node that is not a member.
+	ErrNotMember = errors.New("node is not a registry member")
+	// ErrInvalidSignature is returned when a registry is not signed by the expected node.
+	ErrInvalidSignature = errors.New("registry not signed by the expected node")
+)
+
+// Member is a node allowed by the registry.
+type Member struct {
+	ID   enode.ID `json:"id"`
+	Role string   `json:"role"`
+}
+
+// Signed is the signed form of the registry, as persisted and served.
+type Signed struct {
+	Version   int           `json:"version"`
+	Seq       uint64        `json:"seq"`
+	Members   []Member      `json:"members"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// signingHash returns the hash covered by the signature.
+func (s *Signed) signingHash() ([]byte, error) {
+	unsigned := *s
+	unsigned.Signature = nil
+	enc, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(enc), nil
+}
+
+// Signer recovers the ID of the node that signed the registry.
+func (s *Signed) Signer() (enode.ID, error) {
+	hash, err := s.signingHash()
+	if err != nil {
+		return enode.ID{}, err
+	}
+	pub, err := crypto.SigToPub(hash, s.Signature)
+	if err != nil {
+		return enode.ID{}, fmt.Errorf("invalid registry signature: %w", err)
+	}
+	return enode.PubkeyToIDV4(pub), nil
+}
+
+// Verify checks that the registry is signed by the node.
+func (s *Signed) Verify(signer enode.ID) error {
+	id, err := s.Signer()
+	if err != nil {
+		return err
+	}
+	if id != signer {
+		return fmt.Errorf("%w: signed by %s", ErrInvalidSignature, id)
+	}
+	return nil
+}
+
+// Registry holds the members allowed by a boot node. Every change increments
+// its sequence number and is signed and persisted to its file, if any. It is
+// safe for concurrent use.
+type Registry struct {
+	key  *ecdsa.PrivateKey
+	path string // File persisting the registry, empty to keep it in memory
+
+	lock    sync.RWMutex
+	seq     uint64
+	members map[enode.ID]string
+}
+
+// Open loads the registry persisted at the path, which must be signed by the
+// key, or creates an empty one if the file does not exist. The registry is
+// kept in memory only if the path is empty.
+func Open(path string, key *ecdsa.PrivateKey) (*Registry, error) {
+	r := &Registry{
+		key:     key,
+		path:    path,
+		members: make(map[enode.ID]string),
+	}
+	if path == "" {
+		return r, nil
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	var signed Signed
+	if err := json.Unmarshal(content, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode registry: %w", err)
+	}
+	if signed.Version != version {
+		return nil, fmt.Errorf("unsupported registry version %d", signed.Version)
+	}
+	if err := signed.Verify(enode.PubkeyToIDV4(&key.PublicKey)); err != nil {
+		return nil, err
+	}
+	r.seq = signed.Seq
+	for _, member := range signed.Members {
+		r.members[member.ID] = member.Role
+	}
+	return r, nil
+}
+
+// Add adds the node with the role, or changes the role of a member.
+func (r *Registry) Add(id enode.ID, name string) error {
+	rl, err := role.NewFromString(name)
+	if err != nil {
+		return err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	prev, ok := r.members[id]
+	if ok && prev == rl.String() {
+		return nil
+	}
+	r.members[id] = rl.String()
+	if err := r.commit(); err != nil {
+		if ok {
+			r.members[id] = prev
+		} else {
+			delete(r.members, id)
+		}
+		return err
+	}
+	log.Info("Added registry member", "id", id, "role", rl, "seq", r.seq)
+	return nil
+}
+
+// Revoke removes the member.
+func (r *Registry) Revoke(id enode.ID) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	prev, ok := r.members[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotMember, id)
+	}
+	delete(r.members, id)
+	if err := r.commit(); err != nil {
+		r.members[id] = prev
+		return err
+	}
+	log.Info("Revoked registry member", "id", id, "role", prev, "seq", r.seq)
+	return nil
+}
+
+// Role returns the role of the member.
+func (r *Registry) Role(id enode.ID) (string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	name, ok := r.members[id]
+	return name, ok
+}
+
+// Accepts reports whether the node is a member. Discovery v4 only learns the
+// full ENR of a node, with its "imx-role" entry, once it is fetched when the
+// node is revalidated, so membership is all that is checked when a node is
+// first seen. A member whose record advertises another role than its
+// registered one is rejected, and evicted on the revalidation fetching it.
+func (r *Registry) Accepts(n *enode.Node) bool {
+	name, ok := r.Role(n.ID())
+	if !ok {
+		return false
+	}
+	var advertised enr.Role
+	if err := n.Load(&advertised); err != nil {
+		return enr.IsNotFound(err)
+	}
+	return string(advertised) == name
+}
+
+// Signed returns the current registry, signed.
+func (r *Registry) Signed() (*Signed, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.sign()
+}
+
+// sign signs the registry. The caller must hold the lock.
+func (r *Registry) sign() (*Signed, error) {
+	signed := &Signed{
+		Version: version,
+		Seq:     r.seq,
+		Members: make([]Member, 0, len(r.members)),
+	}
+	for id, name := range r.members {
+		signed.Members = append(signed.Members, Member{ID: id, Role: name})
+	}
+	sort.Slice(signed.Members, func(i, j int) bool {
+		return signed.Members[i].ID.String() < signed.Members[j].ID.String()
+	})
+	hash, err := signed.signingHash()
+	if err != nil {
+		return nil, err
+	}
+	if signed.Signature, err = crypto.Sign(hash, r.key); err != nil {
+		return nil, fmt.Errorf("failed to sign registry: %w", err)
+	}
+	return signed, nil
+}
+
+// commit increments the sequence number and persists the registry. The
+// caller must hold the lock.
+func (r *Registry) commit() error {
+	r.seq++
+	if r.path == "" {
+		return nil
+	}
+	signed, err := r.sign()
+	if err != nil {
+		r.seq--
+		return err
+	}
+	if err := writeFile(r.path, signed); err != nil {
+		r.seq--
+		return fmt.Errorf("failed to persist registry: %w", err)
+	}
+	return nil
+}
+
+// writeFile replaces the registry file atomically.
+func writeFile(path string, signed *Signed) error {
+	enc, err := json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(enc); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
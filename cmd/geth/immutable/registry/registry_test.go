@@ -0,0 +1,244 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testNode returns a node advertising the role in its ENR, unless empty.
+func testNode(t *testing.T, role string) *enode.Node {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r enr.Record
+	if role != "" {
+		r.Set(enr.Role(role))
+	}
+	if err := enode.SignV4(&r, key); err != nil {
+		t.Fatal(err)
+	}
+	n, err := enode.New(enode.ValidSchemes, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestRegistry(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	path := filepath.Join(t.TempDir(), "registry.json")
+	r, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		validator = testNode(t, "validator")
+		rpcNode   = testNode(t, "")
+		claimed   = testNode(t, "validator") // Registered as a partner
+		outsider  = testNode(t, "validator")
+	)
+	if err := r.Add(validator.ID(), "validator"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(rpcNode.ID(), "rpc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(claimed.ID(), "partner"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(outsider.ID(), "sequencer"); err == nil {
+		t.Fatal("expected unknown role error")
+	}
+	for n, want := range map[*enode.Node]bool{validator: true, rpcNode: true, claimed: false, outsider: false} {
+		if got := r.Accepts(n); got != want {
+			t.Errorf("node %s: expected accepted %v, got %v", n.ID().TerminalString(), want, got)
+		}
+	}
+
+	// The registry is persisted and signed by the key
+	reopened, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role, ok := reopened.Role(rpcNode.ID()); !ok || role != "rpc" {
+		t.Fatalf("expected rpc member, got %q (member %v)", role, ok)
+	}
+	signed, err := reopened.Signed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Seq != 3 || len(signed.Members) != 3 {
+		t.Fatalf("expected 3 members at seq 3, got %d at seq %d", len(signed.Members), signed.Seq)
+	}
+	other, _ := crypto.GenerateKey()
+	if _, err := Open(path, other); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature error, got %v", err)
+	}
+
+	// Revoked members are no longer accepted
+	if err := reopened.Revoke(validator.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Accepts(validator) {
+		t.Fatal("revoked member accepted")
+	}
+	if err := reopened.Revoke(validator.ID()); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected not member error, got %v", err)
+	}
+
+	// Tampering with the file is detected
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := []byte(strings.Replace(string(content), `"role": "partner"`, `"role": "validator"`, 1))
+	if string(tampered) == string(content) {
+		t.Fatal("partner member not found in registry file")
+	}
+	if err := os.WriteFile(path, tampered, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, key); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected invalid signature error, got %v", err)
+	}
+}
+
+func TestAPI(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	r, err := Open("", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("admin", NewAPI(r)); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	n := testNode(t, "")
+	var ok bool
+	if err := client.Call(&ok, "admin_addMember", n.URLv4(), "validator"); err != nil || !ok {
+		t.Fatalf("failed to add member: %v", err)
+	}
+	var signed Signed
+	if err := client.Call(&signed, "admin_registry"); err != nil {
+		t.Fatal(err)
+	}
+	if err := signed.Verify(enode.PubkeyToIDV4(&key.PublicKey)); err != nil {
+		t.Fatal(err)
+	}
+	if len(signed.Members) != 1 || signed.Members[0].ID != n.ID() || signed.Members[0].Role != "validator" {
+		t.Fatalf("unexpected members %v", signed.Members)
+	}
+	if err := client.Call(&ok, "admin_revokeMember", n.ID().String()); err != nil || !ok {
+		t.Fatalf("failed to revoke member: %v", err)
+	}
+	if err := client.Call(&ok, "admin_revokeMember", n.ID().String()); err == nil {
+		t.Fatal("expected not member error")
+	}
+}
+
+// listenDiscovery starts a discovery v4 listener on the loopback interface,
+// advertising the role in its ENR.
+func listenDiscovery(t *testing.T, key *ecdsa.PrivateKey, role string, bootnodes []*enode.Node, filter func(*enode.Node) bool) *discover.UDPv4 {
+	db, _ := enode.OpenDB("")
+	ln := enode.NewLocalNode(db, key)
+	ln.Set(enr.Role(role))
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.SetStaticIP(net.IP{127, 0, 0, 1})
+	ln.SetFallbackUDP(conn.LocalAddr().(*net.UDPAddr).Port)
+	udp, err := discover.ListenV4(conn, ln, discover.Config{
+		PrivateKey: key,
+		Bootnodes:  bootnodes,
+		Filter:     filter,
+		// Findnode only returns liveness-checked nodes once there are any,
+		// revalidate quickly so that all members are served.
+		PingInterval: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		udp.Close()
+		db.Close()
+	})
+	return udp
+}
+
+func TestRegistry_Discovery(t *testing.T) {
+	bootKey, _ := crypto.GenerateKey()
+	r, err := Open("", bootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boot := listenDiscovery(t, bootKey, "boot", nil, r.Accepts)
+	bootnodes := []*enode.Node{boot.Self()}
+
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+	}
+	if err := r.Add(enode.PubkeyToIDV4(&keys[0].PublicKey), "validator"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(enode.PubkeyToIDV4(&keys[1].PublicKey), "rpc"); err != nil {
+		t.Fatal(err)
+	}
+	validator := listenDiscovery(t, keys[0], "validator", bootnodes, nil)
+	member := listenDiscovery(t, keys[1], "rpc", bootnodes, nil)
+	outsider := listenDiscovery(t, keys[2], "rpc", bootnodes, nil)
+
+	found := func(udp *discover.UDPv4) bool {
+		for _, n := range udp.LookupPubkey(&keys[0].PublicKey) {
+			if n.ID() == validator.Self().ID() {
+				return true
+			}
+		}
+		return false
+	}
+	// Members discover each other through the boot node
+	for deadline := time.Now().Add(10 * time.Second); !found(member); {
+		if time.Now().After(deadline) {
+			t.Fatal("member did not discover the validator")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	// Nodes outside the registry are not answered
+	if found(outsider) {
+		t.Fatal("outsider discovered the validator")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewind
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// Plan describes what rewinding the chain to a target block would delete and
+// whether the rewind is safe.
+type Plan struct {
+	Head       uint64
+	Target     uint64
+	TargetHash common.Hash
+	Scheme     string // State scheme of the database (hash or path)
+
+	Blocks      uint64 // Canonical blocks deleted
+	Receipts    uint64 // Receipts deleted
+	StateLayers uint64 // State layers rolled back, always zero with the hash scheme which never deletes state
+	Frozen      uint64 // Number of blocks in the freezer
+
+	StateAvailable   bool     // State of the target block is present
+	StateRecoverable bool     // State of the target block can be restored from state history
+	Forks            []string // Forks activated after the target block, up to the head
+}
+
+// NewPlan inspects the database to plan a rewind from the head to the target
+// header. It only reads the databases, so it can plan a rewind of a database
+// opened read-only without constructing a blockchain.
+func NewPlan(db ethdb.Database, triedb *triedb.Database, config *params.ChainConfig, head, target *types.Header) (*Plan, error) {
+	if target.Number.Uint64() > head.Number.Uint64() {
+		return nil, fmt.Errorf("target block %d is above the head %d", target.Number, head.Number)
+	}
+	frozen, _ := db.Ancients()
+	_, err := state.NewDatabaseWithNodeDB(db, triedb).OpenTrie(target.Root)
+	plan := &Plan{
+		Head:           head.Number.Uint64(),
+		Target:         target.Number.Uint64(),
+		TargetHash:     target.Hash(),
+		Scheme:         triedb.Scheme(),
+		Blocks:         head.Number.Uint64() - target.Number.Uint64(),
+		Frozen:         frozen,
+		StateAvailable: err == nil,
+		Forks:          crossedForks(config, target, head),
+	}
+	if !plan.StateAvailable && plan.Scheme == rawdb.PathScheme {
+		plan.StateRecoverable, _ = triedb.Recoverable(target.Root)
+	}
+	// Count the receipts and, with the path scheme, the state transitions which
+	// would be rolled back. Blocks which don't change the state have no layer.
+	parent := target.Root
+	for number := plan.Target + 1; number <= plan.Head; number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		header := rawdb.ReadHeader(db, hash, number)
+		if header == nil {
+			return nil, fmt.Errorf("canonical header %d not found", number)
+		}
+		plan.Receipts += uint64(len(rawdb.ReadRawReceipts(db, hash, number)))
+		if plan.Scheme == rawdb.PathScheme && header.Root != parent {
+			plan.StateLayers++
+		}
+		parent = header.Root
+	}
+	return plan, nil
+}
+
+// BelowFreezer returns whether the rewind deletes blocks from the freezer.
+func (p *Plan) BelowFreezer() bool {
+	return p.Frozen > p.Target+1
+}
+
+// Unsafe returns the reasons why the rewind is unsafe, if any.
+func (p *Plan) Unsafe() []string {
+	var reasons []string
+	if p.BelowFreezer() {
+		reasons = append(reasons, fmt.Sprintf("target is below the freezer boundary (%d blocks frozen), frozen blocks will be deleted", p.Frozen))
+	}
+	if !p.StateAvailable && !p.StateRecoverable {
+		reasons = append(reasons, "state of the target block is missing and not recoverable, the chain will be rewound further")
+	}
+	for _, fork := range p.Forks {
+		reasons = append(reasons, fmt.Sprintf("rewind crosses the %s activation", fork))
+	}
+	return reasons
+}
+
+// String implements fmt.Stringer, describing the rewind for operators.
+func (p *Plan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rewind from block %d to block %d (%s)\n", p.Head, p.Target, p.TargetHash.Hex())
+	fmt.Fprintf(&b, "  blocks deleted:       %d\n", p.Blocks)
+	fmt.Fprintf(&b, "  receipts deleted:     %d\n", p.Receipts)
+	fmt.Fprintf(&b, "  state layers deleted: %d (%s scheme)\n", p.StateLayers, p.Scheme)
+	switch {
+	case p.StateAvailable:
+		fmt.Fprintf(&b, "  target state:         available\n")
+	case p.StateRecoverable:
+		fmt.Fprintf(&b, "  target state:         recoverable from state history\n")
+	default:
+		fmt.Fprintf(&b, "  target state:         missing\n")
+	}
+	for _, reason := range p.Unsafe() {
+		fmt.Fprintf(&b, "  UNSAFE: %s\n", reason)
+	}
+	return b.String()
+}
+
+// crossedForks returns the forks which activate after the target block and at
+// or before the head.
+func crossedForks(config *params.ChainConfig, target, head *types.Header) []string {
+	type fork struct {
+		name      string
+		block     *big.Int
+		timestamp *uint64
+	}
+	var crossed []string
+	for _, f := range []fork{
+		{name: "homesteadBlock", block: config.HomesteadBlock},
+		{name: "daoForkBlock", block: config.DAOForkBlock},
+		{name: "eip150Block", block: config.EIP150Block},
+		{name: "eip155Block", block: config.EIP155Block},
+		{name: "eip158Block", block: config.EIP158Block},
+		{name: "byzantiumBlock", block: config.ByzantiumBlock},
+		{name: "constantinopleBlock", block: config.ConstantinopleBlock},
+		{name: "petersburgBlock", block: config.PetersburgBlock},
+		{name: "istanbulBlock", block: config.IstanbulBlock},
+		{name: "muirGlacierBlock", block: config.MuirGlacierBlock},
+		{name: "berlinBlock", block: config.BerlinBlock},
+		{name: "londonBlock", block: config.LondonBlock},
+		{name: "arrowGlacierBlock", block: config.ArrowGlacierBlock},
+		{name: "grayGlacierBlock", block: config.GrayGlacierBlock},
+		{name: "mergeNetsplitBlock", block: config.MergeNetsplitBlock},
+		{name: "prevrandaoTime", timestamp: config.PrevrandaoTime},
+		{name: "shanghaiTime", timestamp: config.ShanghaiTime},
+		{name: "cancunTime", timestamp: config.CancunTime},
+		{name: "pragueTime", timestamp: config.PragueTime},
+		{name: "verkleTime", timestamp: config.VerkleTime},
+	} {
+		switch {
+		case f.block != nil && target.Number.Cmp(f.block) < 0 && head.Number.Cmp(f.block) >= 0:
+			crossed = append(crossed, fmt.Sprintf("%s (%v)", f.name, f.block))
+		case f.timestamp != nil && target.Time < *f.timestamp && head.Time >= *f.timestamp:
+			crossed = append(crossed, fmt.Sprintf("%s (%d)", f.name, *f.timestamp))
+		}
+	}
+	return crossed
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rewind
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlanTestChain(t *testing.T, scheme string) (*core.BlockChain, ethdb.Database) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := *params.AllEthashProtocolChanges
+	config.GrayGlacierBlock = big.NewInt(6)
+	gspec := &core.Genesis{
+		Config: &config,
+		Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	signer := types.LatestSigner(&config)
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 8, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    gen.TxNonce(addr),
+			To:       &common.Address{0x01},
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: gen.BaseFee(),
+		})
+		require.NoError(t, err)
+		gen.AddTx(tx)
+	})
+	db := rawdb.NewMemoryDatabase()
+	chain, err := core.NewBlockChain(db, core.DefaultCacheConfigWithScheme(scheme), gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err)
+	_, err = chain.InsertChain(blocks)
+	require.NoError(t, err)
+	return chain, db
+}
+
+func TestPlan(t *testing.T) {
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		chain, db := newPlanTestChain(t, scheme)
+		defer chain.Stop()
+
+		// Rewinding past the gray glacier activation is unsafe
+		plan, err := NewPlan(db, chain.TrieDB(), chain.Config(), chain.CurrentHeader(), chain.GetHeaderByNumber(4))
+		require.NoError(t, err)
+		require.Equal(t, uint64(8), plan.Head)
+		require.Equal(t, uint64(4), plan.Blocks)
+		require.Equal(t, uint64(4), plan.Receipts)
+		require.True(t, plan.StateAvailable)
+		require.Len(t, plan.Forks, 1)
+		require.Len(t, plan.Unsafe(), 1)
+		if scheme == rawdb.PathScheme {
+			require.Equal(t, uint64(4), plan.StateLayers)
+		} else {
+			require.Zero(t, plan.StateLayers)
+		}
+
+		// Rewinding after it is safe
+		plan, err = NewPlan(db, chain.TrieDB(), chain.Config(), chain.CurrentHeader(), chain.GetHeaderByNumber(6))
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), plan.Blocks)
+		require.Empty(t, plan.Unsafe())
+
+		// Rewinding to a block without state is unsafe
+		header := types.CopyHeader(chain.GetHeaderByNumber(6))
+		header.Root = common.Hash{0x01}
+		plan, err = NewPlan(db, chain.TrieDB(), chain.Config(), chain.CurrentHeader(), header)
+		require.NoError(t, err)
+		require.False(t, plan.StateAvailable)
+		require.False(t, plan.StateRecoverable)
+		require.Len(t, plan.Unsafe(), 1)
+
+		// Rewinding above the head is rejected
+		_, err = NewPlan(db, chain.TrieDB(), chain.Config(), chain.CurrentHeader(), &types.Header{Number: big.NewInt(9)})
+		require.Error(t, err)
+	}
+}
+
+// Tests that plans only read the databases, so that dry runs can plan rewinds
+// of read-only databases without constructing a chain.
+func TestPlan_ReadOnly(t *testing.T) {
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		chain, db := newPlanTestChain(t, scheme)
+		config, target := chain.Config(), chain.GetHeaderByNumber(7)
+		chain.Stop() // Persists the state of the recent blocks
+
+		readonly := triedb.NewDatabase(db, &triedb.Config{HashDB: hashdb.Defaults})
+		if scheme == rawdb.PathScheme {
+			readonly = triedb.NewDatabase(db, &triedb.Config{PathDB: pathdb.ReadOnly})
+		}
+		plan, err := NewPlan(db, readonly, config, rawdb.ReadHeadHeader(db), target)
+		require.NoError(t, err)
+		require.Equal(t, uint64(8), plan.Head)
+		require.Equal(t, uint64(1), plan.Blocks)
+		require.Equal(t, uint64(1), plan.Receipts)
+		require.True(t, plan.StateAvailable)
+		require.Empty(t, plan.Unsafe())
+		readonly.Close()
+	}
+}
+
+func TestPlan_BelowFreezer(t *testing.T) {
+	plan := &Plan{Head: 100, Target: 10, Frozen: 11, StateAvailable: true}
+	require.False(t, plan.BelowFreezer())
+	require.Empty(t, plan.Unsafe())
+
+	plan.Frozen = 50
+	require.True(t, plan.BelowFreezer())
+	require.Len(t, plan.Unsafe(), 1)
+}
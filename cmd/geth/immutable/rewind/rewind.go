@@ -33,6 +33,14 @@ type Record struct {
 	BlockNumber uint64
 	BlockHash   common.Hash
 	Timestamp   time.Time
+
+	// Operator who ran the rewind and the reason given for it
+	Operator string `yaml:",omitempty"`
+	Reason   string `yaml:",omitempty"`
+
+	// Head of the chain before the rewind
+	PreviousHeadNumber uint64      `yaml:",omitempty"`
+	PreviousHeadHash   common.Hash `yaml:",omitempty"`
 }
 
 // History is a list of all records in the rewind history.
@@ -57,3 +57,34 @@ func TestRewind_WriteRecord_HistoryNotEmptyAndRecordExists(t *testing.T) {
 	require.True(t, history.Contains(secondHash))
 	require.False(t, history.Contains(common.HexToHash("0x789")))
 }
+
+func TestRewind_WriteRecord_OperatorReasonAndPreviousHead(t *testing.T) {
+	filepath := filepath.Join(t.TempDir(), "test.yaml")
+	record := Record{
+		BlockNumber:        2,
+		BlockHash:          common.HexToHash("0x456"),
+		Timestamp:          time.Now(),
+		Operator:           "alice",
+		Reason:             "bad block",
+		PreviousHeadNumber: 5,
+		PreviousHeadHash:   common.HexToHash("0xabc"),
+	}
+	// Records written before the operator, reason and previous head were
+	// recorded are read back without them
+	history := History{{BlockNumber: 1, BlockHash: common.HexToHash("0x123"), Timestamp: time.Now()}, record}
+	require.NoError(t, WriteRewindHistory(history, filepath))
+
+	history, err := ReadRewindHistory(filepath)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	require.Empty(t, history[0].Operator)
+	require.Empty(t, history[0].Reason)
+	require.Zero(t, history[0].PreviousHeadNumber)
+	require.Equal(t, common.Hash{}, history[0].PreviousHeadHash)
+
+	require.Equal(t, record.Operator, history[1].Operator)
+	require.Equal(t, record.Reason, history[1].Reason)
+	require.Equal(t, record.PreviousHeadNumber, history[1].PreviousHeadNumber)
+	require.Equal(t, record.PreviousHeadHash, history[1].PreviousHeadHash)
+}
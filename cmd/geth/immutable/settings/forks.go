@@ -17,8 +17,11 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"gopkg.in/yaml.v2"
 )
 
 // Fork represents a human-readable date/time for a specific network fork.
@@ -29,27 +32,129 @@ type Fork struct {
 }
 
 func newFork(timestamp string) Fork {
+	fork, err := parseFork(timestamp)
+	if err != nil {
+		panic(err)
+	}
+	return fork
+}
+
+// parseFork parses a fork time in the UnixDate layout and the UTC location,
+// rejecting times which don't format back to the same string.
+func parseFork(timestamp string) (Fork, error) {
 	const (
 		forkTimestampLocation = "UTC"
 		forkTimestampLayout   = time.UnixDate
 	)
 	loc, err := time.LoadLocation(forkTimestampLocation)
 	if err != nil {
-		panic(err)
+		return Fork{}, err
 	}
 	t, err := time.ParseInLocation(forkTimestampLayout, timestamp, loc)
 	if err != nil {
-		panic(err)
+		return Fork{}, err
 	}
 
 	if t.UTC().Format(forkTimestampLayout) != timestamp {
-		panic(fmt.Sprintf("fork time %s does not match supplied %s", t.UTC().Format(forkTimestampLayout), timestamp))
+		return Fork{}, fmt.Errorf("fork time %s does not match supplied %s", t.UTC().Format(forkTimestampLayout), timestamp)
 	}
 
-	return Fork{t}
+	return Fork{t}, nil
 }
 
 // IsEnabledAt returns true if the given unix timestamp is past the fork timestamp
 func (fork Fork) IsEnabledAt(time int64) bool {
 	return time >= fork.Unix()
 }
+
+// UnmarshalYAML implements yaml.Unmarshaler, parsing the fork time with the
+// same checks as newFork.
+func (fork *Fork) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var timestamp string
+	if err := unmarshal(&timestamp); err != nil {
+		return err
+	}
+	parsed, err := parseFork(timestamp)
+	if err != nil {
+		return err
+	}
+	*fork = parsed
+	return nil
+}
+
+// ScheduledFork is a named fork of a fork schedule. The name is the one of
+// the chain config field without the Time suffix, e.g. "cancun".
+type ScheduledFork struct {
+	Name string `yaml:"name"`
+	Time Fork   `yaml:"time"`
+}
+
+// ForkSchedule is the list of forks of a network in activation order.
+type ForkSchedule []ScheduledFork
+
+// ParseForkSchedule parses and validates a YAML fork schedule.
+func ParseForkSchedule(data []byte) (ForkSchedule, error) {
+	var file struct {
+		Forks ForkSchedule `yaml:"forks"`
+	}
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid fork schedule: %w", err)
+	}
+	if err := file.Forks.Validate(); err != nil {
+		return nil, err
+	}
+	return file.Forks, nil
+}
+
+// Validate checks that the forks are named, unique and ordered by time.
+func (s ForkSchedule) Validate() error {
+	seen := make(map[string]bool)
+	for i, fork := range s {
+		if fork.Name == "" {
+			return errors.New("fork schedule contains an unnamed fork")
+		}
+		if seen[fork.Name] {
+			return fmt.Errorf("fork %s is scheduled more than once", fork.Name)
+		}
+		seen[fork.Name] = true
+		if i > 0 && fork.Time.Before(s[i-1].Time.Time) {
+			return fmt.Errorf("fork %s at %v is scheduled before the preceding fork %s at %v", fork.Name, fork.Time, s[i-1].Name, s[i-1].Time)
+		}
+	}
+	return nil
+}
+
+// Fork returns the time of the named fork.
+func (s ForkSchedule) Fork(name string) (Fork, bool) {
+	for _, fork := range s {
+		if fork.Name == name {
+			return fork.Time, true
+		}
+	}
+	return Fork{}, false
+}
+
+// Times returns the unix activation times of the forks by name.
+func (s ForkSchedule) Times() map[string]uint64 {
+	times := make(map[string]uint64, len(s))
+	for _, fork := range s {
+		times[fork.Name] = uint64(fork.Time.Unix())
+	}
+	return times
+}
+
+func mustParseForkSchedule(data []byte) ForkSchedule {
+	schedule, err := ParseForkSchedule(data)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+func (s ForkSchedule) mustFork(name string) Fork {
+	fork, ok := s.Fork(name)
+	if !ok {
+		panic(fmt.Sprintf("fork %s is not scheduled", name))
+	}
+	return fork
+}
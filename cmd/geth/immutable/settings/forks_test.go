@@ -60,3 +60,49 @@ func TestForks_IsPastTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func TestForks_ParseForkSchedule(t *testing.T) {
+	var tests = []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{"ordered", "forks:\n  - name: shanghai\n    time: Tue Mar 12 22:00:00 UTC 2024\n  - name: cancun\n    time: Mon Sep 23 22:00:00 UTC 2024\n", true},
+		{"same time", "forks:\n  - name: shanghai\n    time: Tue Mar 12 22:00:00 UTC 2024\n  - name: prevrandao\n    time: Tue Mar 12 22:00:00 UTC 2024\n", true},
+		{"unordered", "forks:\n  - name: cancun\n    time: Mon Sep 23 22:00:00 UTC 2024\n  - name: shanghai\n    time: Tue Mar 12 22:00:00 UTC 2024\n", false},
+		{"duplicate", "forks:\n  - name: cancun\n    time: Mon Sep 23 22:00:00 UTC 2024\n  - name: cancun\n    time: Mon Sep 30 22:00:00 UTC 2024\n", false},
+		{"unnamed", "forks:\n  - time: Mon Sep 23 22:00:00 UTC 2024\n", false},
+		{"wrong weekday", "forks:\n  - name: cancun\n    time: Tue Sep 23 22:00:00 UTC 2024\n", false},
+		{"unknown field", "forks:\n  - name: cancun\n    block: 1\n", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := ParseForkSchedule([]byte(test.yaml))
+			if test.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatalf("expected error, got schedule %v", schedule)
+			}
+		})
+	}
+}
+
+func TestForks_NetworkSchedules(t *testing.T) {
+	for _, name := range []string{"devnet", "testnet", "mainnet"} {
+		network, err := NewNetwork(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		times := network.Forks().Times()
+		if len(times) != 3 {
+			t.Fatalf("%s: expected 3 scheduled forks, got %d", name, len(times))
+		}
+		if times["cancun"] != uint64(network.Cancun().Unix()) {
+			t.Errorf("%s: cancun time mismatch", name)
+		}
+	}
+	if MainnetCancunFork.UTC().Format(time.UnixDate) != "Mon Oct  7 22:00:00 UTC 2024" {
+		t.Errorf("unexpected mainnet cancun fork %v", MainnetCancunFork)
+	}
+}
@@ -29,6 +29,13 @@ var (
 	immutableGenesisTestnetJSON string
 	//go:embed genesis/devnet.json
 	immutableGenesisDevnetJSON string
+
+	//go:embed forks/mainnet.yaml
+	immutableForksMainnetYAML string
+	//go:embed forks/testnet.yaml
+	immutableForksTestnetYAML string
+	//go:embed forks/devnet.yaml
+	immutableForksDevnetYAML string
 )
 
 // Network is the name of an Immutable zkEVM network
@@ -36,7 +43,7 @@ type Network struct {
 	string
 	genesisJSON string
 	id          int
-	cancun      Fork
+	forks       ForkSchedule
 }
 
 // String returns the string representation of the network
@@ -54,9 +61,24 @@ func (n Network) ID() int {
 	return n.id
 }
 
+// Forks returns the fork schedule of the network.
+func (n Network) Forks() ForkSchedule {
+	return n.forks
+}
+
+// Shanghai returns the Shanghai fork for the network.
+func (n Network) Shanghai() Fork {
+	return n.forks.mustFork("shanghai")
+}
+
+// Prevrandao returns the Prevrandao fork for the network.
+func (n Network) Prevrandao() Fork {
+	return n.forks.mustFork("prevrandao")
+}
+
 // Cancun returns the Cancun fork for the network.
 func (n Network) Cancun() Fork {
-	return n.cancun
+	return n.forks.mustFork("cancun")
 }
 
 // RPC returns the RPC endpoint for the network.
@@ -82,21 +104,21 @@ func NewNetwork(name string) (*Network, error) {
 			string:      name,
 			genesisJSON: immutableGenesisDevnetJSON,
 			id:          DevnetNetworkID,
-			cancun:      DevnetCancunFork,
+			forks:       devnetForks,
 		}, nil
 	case "testnet":
 		return &Network{
 			string:      name,
 			genesisJSON: immutableGenesisTestnetJSON,
 			id:          TestnetNetworkID,
-			cancun:      TestnetCancunFork,
+			forks:       testnetForks,
 		}, nil
 	case "mainnet":
 		return &Network{
 			string:      name,
 			genesisJSON: immutableGenesisMainnetJSON,
 			id:          MainnetNetworkID,
-			cancun:      MainnetCancunFork,
+			forks:       mainnetForks,
 		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported network: %s", name)
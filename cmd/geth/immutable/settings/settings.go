@@ -42,30 +42,32 @@ const (
 	DevnetRPC = "https://rpc.dev.immutable.com"
 )
 
+// Fork times of the networks, read from their fork schedules in the forks
+// directory. New forks only need to be added to the schedules.
 var (
+	devnetForks  = mustParseForkSchedule([]byte(immutableForksDevnetYAML))
+	testnetForks = mustParseForkSchedule([]byte(immutableForksTestnetYAML))
+	mainnetForks = mustParseForkSchedule([]byte(immutableForksMainnetYAML))
+
 	// DevnetShanghaiFork is the timestamp of the Shanghai devnet fork.
-	DevnetShanghaiFork = newFork("Tue Feb 27 21:00:00 UTC 2024")
+	DevnetShanghaiFork = devnetForks.mustFork("shanghai")
 	// TestnetShanghaiFork is the timestamp of the Shanghai testnet fork.
-	TestnetShanghaiFork = newFork("Tue Mar 12 22:00:00 UTC 2024")
+	TestnetShanghaiFork = testnetForks.mustFork("shanghai")
 	// MainnetShanghaiFork is the timestamp of the Shanghai mainnet fork.
-	MainnetShanghaiFork = newFork("Tue Mar 26 22:00:00 UTC 2024")
+	MainnetShanghaiFork = mainnetForks.mustFork("shanghai")
 
 	// DevnetPrevrandaoFork is the timestamp of the Prevrandao devnet fork.
-	DevnetPrevrandaoFork = DevnetShanghaiFork
+	DevnetPrevrandaoFork = devnetForks.mustFork("prevrandao")
 	// TestnetPrevrandaoFork is the timestamp of the Prevrandao testnet fork.
-	TestnetPrevrandaoFork = TestnetShanghaiFork
+	TestnetPrevrandaoFork = testnetForks.mustFork("prevrandao")
 	// MainnetPrevrandaoFork is the timestamp of the Prevrandao mainnet fork.
 	// Only mainnet has a Prevrandao fork separate from the Shanghai fork.
-	// Block 0x41DDE4.
-	// TZ=UTC gdate --date @1710899402
-	MainnetPrevrandaoFork = newFork("Wed Mar 20 01:50:02 UTC 2024")
+	MainnetPrevrandaoFork = mainnetForks.mustFork("prevrandao")
 
 	// DevnetCancunFork is the timestamp of the Cancun devnet fork.
-	DevnetCancunFork = newFork("Tue Aug 27 22:00:00 UTC 2024")
+	DevnetCancunFork = devnetForks.mustFork("cancun")
 	// TestnetCancunFork is the timestamp of the Cancun testnet fork.
-	TestnetCancunFork = newFork("Mon Sep 23 22:00:00 UTC 2024")
+	TestnetCancunFork = testnetForks.mustFork("cancun")
 	// MainnetCancunFork is the timestamp of the Cancun mainnet fork.
-	// Unixdate format expects an extra whitespace if the day is a single digit.
-	// e.g. Mon Jan _2 15:04:05 MST 2006
-	MainnetCancunFork = newFork("Mon Oct  7 22:00:00 UTC 2024")
+	MainnetCancunFork = mainnetForks.mustFork("cancun")
 )
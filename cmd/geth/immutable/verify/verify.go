@@ -0,0 +1,212 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Sources of the expected values of a mismatch.
+const (
+	SourceGenesis  = "genesis"  // Embedded genesis JSON of the network
+	SourceSettings = "settings" // Fork times and consensus settings of the network
+	SourceSigners  = "signers"  // Expected validator set
+)
+
+// unset is reported for values missing from a configuration.
+const unset = "<unset>"
+
+// Mismatch is a difference between the configuration expected for an
+// Immutable network and the one held by a node's database.
+type Mismatch struct {
+	Source   string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// String implements fmt.Stringer.
+func (m Mismatch) String() string {
+	return fmt.Sprintf("[%s] %s: expected %s, have %s", m.Source, m.Field, m.Expected, m.Actual)
+}
+
+// Genesis compares the stored genesis hash and chain config against the
+// network's embedded genesis, its fork times and the conditions of
+// params.ChainConfig.IsValidImmutableZKEVM, returning every mismatch.
+func Genesis(network *settings.Network, hash common.Hash, config *params.ChainConfig) ([]Mismatch, error) {
+	genesis := core.ImmutableGenesisBlock(network.String())
+
+	var mismatches []Mismatch
+	if expected := genesis.ToBlock().Hash(); hash != expected {
+		mismatches = append(mismatches, Mismatch{SourceGenesis, "hash", expected.Hex(), hash.Hex()})
+	}
+	if config == nil {
+		return append(mismatches, Mismatch{SourceGenesis, "config", "stored chain config", unset}), nil
+	}
+	diff, err := diffConfigs(genesis.Config, config)
+	if err != nil {
+		return nil, err
+	}
+	mismatches = append(mismatches, diff...)
+
+	// The embedded genesis may itself have drifted from the settings, check
+	// every fork of the network's schedule
+	for _, fork := range network.Forks() {
+		time, err := config.ForkTime(fork.Name)
+		if err != nil {
+			return nil, fmt.Errorf("fork schedule of %s: %w", network, err)
+		}
+		if actual := formatTime(time); actual != fmt.Sprint(fork.Time.Unix()) {
+			mismatches = append(mismatches, Mismatch{SourceSettings, fork.Name + "Time", fmt.Sprint(fork.Time.Unix()), actual})
+		}
+	}
+	if config.ChainID == nil || config.ChainID.Int64() != int64(network.ID()) {
+		mismatches = append(mismatches, Mismatch{SourceSettings, "chainId", fmt.Sprint(network.ID()), fmt.Sprint(config.ChainID)})
+	}
+	if config.Clique == nil {
+		mismatches = append(mismatches, Mismatch{SourceSettings, "clique.period", fmt.Sprint(settings.SecondsPerBlock), unset})
+	} else if config.Clique.Period != settings.SecondsPerBlock {
+		mismatches = append(mismatches, Mismatch{SourceSettings, "clique.period", fmt.Sprint(settings.SecondsPerBlock), fmt.Sprint(config.Clique.Period)})
+	}
+	if !config.IsReorgBlocked {
+		mismatches = append(mismatches, Mismatch{SourceSettings, "isReorgBlocked", "true", "false"})
+	}
+	return mismatches, nil
+}
+
+// Signers compares the signers of a clique snapshot against the expected
+// validator set, reporting missing and unexpected signers.
+func Signers(expected, actual []common.Address) []Mismatch {
+	var mismatches []Mismatch
+	for _, signer := range sortedAddresses(expected) {
+		if !slices.Contains(actual, signer) {
+			mismatches = append(mismatches, Mismatch{SourceSigners, "signer", signer.Hex(), unset})
+		}
+	}
+	for _, signer := range sortedAddresses(actual) {
+		if !slices.Contains(expected, signer) {
+			mismatches = append(mismatches, Mismatch{SourceSigners, "signer", unset, signer.Hex()})
+		}
+	}
+	return mismatches
+}
+
+// LatestSnapshot returns the clique snapshot stored at the most recent
+// checkpoint at or below the head of the database.
+func LatestSnapshot(db ethdb.Database, config *params.ChainConfig) (*clique.Snapshot, error) {
+	if config == nil || config.Clique == nil {
+		return nil, errors.New("datadir holds no clique chain config")
+	}
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return nil, errors.New("datadir holds no head header")
+	}
+	return clique.LoadLatestSnapshot(config.Clique, db, head.Number.Uint64())
+}
+
+// diffConfigs compares two chain configs field by field through their JSON
+// encoding, so that every configured fork and consensus parameter is covered.
+func diffConfigs(expected, actual *params.ChainConfig) ([]Mismatch, error) {
+	want, err := flattenConfig(expected)
+	if err != nil {
+		return nil, err
+	}
+	have, err := flattenConfig(actual)
+	if err != nil {
+		return nil, err
+	}
+	fields := maps.Keys(want)
+	for field := range have {
+		if _, ok := want[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var mismatches []Mismatch
+	for _, field := range fields {
+		w, ok := want[field]
+		if !ok {
+			w = unset
+		}
+		h, ok := have[field]
+		if !ok {
+			h = unset
+		}
+		if w != h {
+			mismatches = append(mismatches, Mismatch{SourceGenesis, field, w, h})
+		}
+	}
+	return mismatches, nil
+}
+
+// flattenConfig encodes a chain config into a map of dotted JSON field paths
+// to their encoded values.
+func flattenConfig(config *params.ChainConfig) (map[string]string, error) {
+	blob, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	// Decode numbers verbatim, big integers would lose precision as floats
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(blob))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	flat := make(map[string]string)
+	var flatten func(prefix string, fields map[string]interface{})
+	flatten = func(prefix string, fields map[string]interface{}) {
+		for name, value := range fields {
+			if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+				flatten(prefix+name+".", nested)
+				continue
+			}
+			encoded, _ := json.Marshal(value)
+			flat[prefix+name] = strings.Trim(string(encoded), `"`)
+		}
+	}
+	flatten("", fields)
+	return flat, nil
+}
+
+func formatTime(time *uint64) string {
+	if time == nil {
+		return unset
+	}
+	return fmt.Sprint(*time)
+}
+
+func sortedAddresses(addrs []common.Address) []common.Address {
+	sorted := slices.Clone(addrs)
+	slices.SortFunc(sorted, func(a, b common.Address) int { return a.Cmp(b) })
+	return sorted
+}
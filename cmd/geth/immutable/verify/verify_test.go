@@ -0,0 +1,76 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package verify
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_Genesis(t *testing.T) {
+	for _, name := range []string{"devnet", "testnet", "mainnet"} {
+		t.Run(name, func(t *testing.T) {
+			network, err := settings.NewNetwork(name)
+			require.NoError(t, err)
+			genesis := core.ImmutableGenesisBlock(name)
+			hash := genesis.ToBlock().Hash()
+
+			// The embedded genesis matches the settings
+			mismatches, err := Genesis(network, hash, genesis.Config)
+			require.NoError(t, err)
+			require.Empty(t, mismatches)
+
+			// Drifted fork times and consensus settings are reported
+			config := *genesis.Config
+			cancun := *config.CancunTime + 1
+			config.CancunTime = &cancun
+			config.Clique = &params.CliqueConfig{Period: 5, Epoch: config.Clique.Epoch}
+			config.IsReorgBlocked = false
+			mismatches, err = Genesis(network, common.Hash{0x01}, &config)
+			require.NoError(t, err)
+			require.ElementsMatch(t, []Mismatch{
+				{SourceGenesis, "hash", hash.Hex(), common.Hash{0x01}.Hex()},
+				{SourceGenesis, "cancunTime", formatTime(genesis.Config.CancunTime), formatTime(&cancun)},
+				{SourceGenesis, "clique.period", "2", "5"},
+				{SourceGenesis, "isReorgBlocked", "true", unset},
+				{SourceSettings, "cancunTime", formatTime(genesis.Config.CancunTime), formatTime(&cancun)},
+				{SourceSettings, "clique.period", "2", "5"},
+				{SourceSettings, "isReorgBlocked", "true", "false"},
+			}, mismatches)
+
+			// A missing chain config is reported
+			mismatches, err = Genesis(network, hash, nil)
+			require.NoError(t, err)
+			require.Equal(t, []Mismatch{{SourceGenesis, "config", "stored chain config", unset}}, mismatches)
+		})
+	}
+}
+
+func TestVerify_Signers(t *testing.T) {
+	a, b, c := common.Address{0x0a}, common.Address{0x0b}, common.Address{0x0c}
+
+	require.Empty(t, Signers([]common.Address{a, b}, []common.Address{b, a}))
+	require.Equal(t, []Mismatch{
+		{SourceSigners, "signer", a.Hex(), unset},
+		{SourceSigners, "signer", unset, c.Hex()},
+	}, Signers([]common.Address{b, a}, []common.Address{c, b}))
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vote
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrInsufficientQuorum is returned when the available voters cannot push a
+// step of the plan through.
+var ErrInsufficientQuorum = errors.New("insufficient quorum")
+
+// ValidatorSet is the desired validator set file format.
+type ValidatorSet struct {
+	Validators []string `yaml:"validators"`
+}
+
+// ReadValidatorSet reads the desired validator set from a YAML file.
+func ReadValidatorSet(filepath string) ([]common.Address, error) {
+	fileData, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading validator set file: %v", err)
+	}
+	var set ValidatorSet
+	if err := yaml.Unmarshal(fileData, &set); err != nil {
+		return nil, fmt.Errorf("error unmarshalling validator set YAML: %v", err)
+	}
+	if len(set.Validators) == 0 {
+		return nil, errors.New("validator set is empty")
+	}
+	validators := make([]common.Address, 0, len(set.Validators))
+	for _, v := range set.Validators {
+		if !common.IsHexAddress(v) {
+			return nil, fmt.Errorf("address %s is not valid", v)
+		}
+		addr := common.HexToAddress(v)
+		if slices.Contains(validators, addr) {
+			return nil, fmt.Errorf("validator %s is listed more than once", addr)
+		}
+		validators = append(validators, addr)
+	}
+	return validators, nil
+}
+
+// Step is a single validator set change.
+type Step struct {
+	Validator common.Address   // Validator to vote in or out
+	Add       bool             // Whether the validator is voted in
+	Before    []common.Address // Signers before the change
+	After     []common.Address // Signers once the change is applied
+	Voters    []common.Address // Available signers casting the vote
+	Quorum    int              // Number of votes required for the change to pass
+}
+
+// Plan is an ordered list of validator set changes.
+type Plan []Step
+
+// ComputePlan computes the changes required to turn the current signer set
+// into the desired one, voting validators in before voting any out so that the
+// set never shrinks below its target. Each step is checked against the voters
+// that will be able to vote at that point.
+func ComputePlan(current, desired, voters []common.Address) (Plan, error) {
+	var adds, removes []common.Address
+	for _, addr := range desired {
+		if !slices.Contains(current, addr) {
+			adds = append(adds, addr)
+		}
+	}
+	for _, addr := range current {
+		if !slices.Contains(desired, addr) {
+			removes = append(removes, addr)
+		}
+	}
+	slices.SortFunc(adds, common.Address.Cmp)
+	slices.SortFunc(removes, common.Address.Cmp)
+
+	var (
+		plan    Plan
+		signers = sortedCopy(current)
+	)
+	appendStep := func(validator common.Address, add bool) error {
+		after := slices.Clone(signers)
+		if add {
+			after = append(after, validator)
+			slices.SortFunc(after, common.Address.Cmp)
+		} else {
+			after = slices.DeleteFunc(after, func(a common.Address) bool { return a == validator })
+		}
+		step := Step{
+			Validator: validator,
+			Add:       add,
+			Before:    signers,
+			After:     after,
+			Quorum:    len(signers)/2 + 1,
+		}
+		for _, voter := range voters {
+			if slices.Contains(signers, voter) && !slices.Contains(step.Voters, voter) {
+				step.Voters = append(step.Voters, voter)
+			}
+		}
+		if len(step.Voters) < step.Quorum {
+			return fmt.Errorf("%w: %s needs %d votes but only %d signers are available", ErrInsufficientQuorum, step.describe(), step.Quorum, len(step.Voters))
+		}
+		plan = append(plan, step)
+		signers = after
+		return nil
+	}
+	for _, addr := range adds {
+		if err := appendStep(addr, true); err != nil {
+			return plan, err
+		}
+	}
+	for _, addr := range removes {
+		if err := appendStep(addr, false); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// describe returns a short human readable description of the step.
+func (s Step) describe() string {
+	if s.Add {
+		return "adding " + s.Validator.Hex()
+	}
+	return "removing " + s.Validator.Hex()
+}
+
+// String renders the plan for a dry run.
+func (p Plan) String() string {
+	if len(p) == 0 {
+		return "Validator set already matches, nothing to do\n"
+	}
+	var b strings.Builder
+	for i, step := range p {
+		fmt.Fprintf(&b, "Step %d: %s\n", i+1, step.describe())
+		fmt.Fprintf(&b, "  signers: %d -> %d\n", len(step.Before), len(step.After))
+		fmt.Fprintf(&b, "  quorum:  %d of %d available voters\n", step.Quorum, len(step.Voters))
+		for _, voter := range step.Voters {
+			fmt.Fprintf(&b, "    %s\n", voter.Hex())
+		}
+	}
+	return b.String()
+}
+
+// Applied reports whether the signer set reflects the step.
+func (s Step) Applied(signers []common.Address) bool {
+	return slices.Contains(signers, s.Validator) == s.Add
+}
+
+func sortedCopy(addrs []common.Address) []common.Address {
+	sorted := slices.Clone(addrs)
+	slices.SortFunc(sorted, common.Address.Cmp)
+	return sorted
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addrA = common.HexToAddress("0x01")
+	addrB = common.HexToAddress("0x02")
+	addrC = common.HexToAddress("0x03")
+	addrD = common.HexToAddress("0x04")
+)
+
+func TestVote_ReadValidatorSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validators.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("validators:\n  - "+addrA.Hex()+"\n  - "+addrB.Hex()+"\n"), 0644))
+
+	validators, err := ReadValidatorSet(path)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{addrA, addrB}, validators)
+
+	require.NoError(t, os.WriteFile(path, []byte("validators:\n  - "+addrA.Hex()+"\n  - "+addrA.Hex()+"\n"), 0644))
+	_, err = ReadValidatorSet(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("validators:\n  - nope\n"), 0644))
+	_, err = ReadValidatorSet(path)
+	require.Error(t, err)
+}
+
+func TestVote_ComputePlan_AddsBeforeRemoves(t *testing.T) {
+	plan, err := ComputePlan(
+		[]common.Address{addrA, addrB, addrC},
+		[]common.Address{addrA, addrB, addrD},
+		[]common.Address{addrA, addrB, addrC, addrD},
+	)
+	require.NoError(t, err)
+	require.Len(t, plan, 2)
+
+	require.Equal(t, addrD, plan[0].Validator)
+	require.True(t, plan[0].Add)
+	require.Equal(t, 2, plan[0].Quorum)
+	require.Equal(t, []common.Address{addrA, addrB, addrC}, plan[0].Voters)
+	require.Equal(t, []common.Address{addrA, addrB, addrC, addrD}, plan[0].After)
+
+	require.Equal(t, addrC, plan[1].Validator)
+	require.False(t, plan[1].Add)
+	require.Equal(t, 3, plan[1].Quorum)
+	require.Equal(t, []common.Address{addrA, addrB, addrD}, plan[1].After)
+
+	require.True(t, plan[0].Applied([]common.Address{addrA, addrD}))
+	require.False(t, plan[1].Applied([]common.Address{addrC}))
+}
+
+func TestVote_ComputePlan_NothingToDo(t *testing.T) {
+	plan, err := ComputePlan([]common.Address{addrA, addrB}, []common.Address{addrB, addrA}, []common.Address{addrA})
+	require.NoError(t, err)
+	require.Empty(t, plan)
+	require.Contains(t, plan.String(), "nothing to do")
+}
+
+func TestVote_ComputePlan_InsufficientQuorum(t *testing.T) {
+	// Two of three signers are required but only one is reachable
+	plan, err := ComputePlan(
+		[]common.Address{addrA, addrB, addrC},
+		[]common.Address{addrA, addrB, addrC, addrD},
+		[]common.Address{addrA},
+	)
+	require.ErrorIs(t, err, ErrInsufficientQuorum)
+	require.Empty(t, plan)
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/node"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/cmd/immutable"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/artefacts"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/urfave/cli/v2"
+)
+
+// localArtefactNetwork is the network name under which the artefacts of new
+// local networks are published. It differs from the known networks, whose
+// nodes run with the embedded genesis.
+const localArtefactNetwork = "local"
+
+// openArtefactStore opens the artefact store configured by the flags.
+// It returns nil if none is configured.
+func openArtefactStore(c *cli.Context) (artefacts.Store, error) {
+	uri := c.String(immutable.Artefacts.Name)
+	if uri == "" {
+		return nil, nil
+	}
+	return artefacts.Open(uri)
+}
+
+// loadArtefactKey loads the key signing published artefact manifests.
+func loadArtefactKey(c *cli.Context) (*ecdsa.PrivateKey, error) {
+	path := c.String(immutable.ArtefactKeyFilepath.Name)
+	if path == "" {
+		return nil, fmt.Errorf("publishing artefacts requires --%s", immutable.ArtefactKeyFilepath.Name)
+	}
+	key, err := crypto.LoadECDSA(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load artefact key: %w", err)
+	}
+	return key, nil
+}
+
+// artefactSigners parses the addresses trusted to sign fetched artefact manifests.
+func artefactSigners(c *cli.Context) ([]common.Address, error) {
+	var signers []common.Address
+	for _, signer := range c.StringSlice(immutable.ArtefactSigners.Name) {
+		signer = strings.TrimSpace(signer)
+		if !common.IsHexAddress(signer) {
+			return nil, fmt.Errorf("invalid artefact signer %q", signer)
+		}
+		signers = append(signers, common.HexToAddress(signer))
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("fetching artefacts requires --%s", immutable.ArtefactSigners.Name)
+	}
+	return signers, nil
+}
+
+// localArtefacts collects the genesis, the config TOML and the boot node enode
+// list produced by a local bootstrap.
+func localArtefacts(gen *Genesis, configFilepath string, boots []node.Node) (map[string][]byte, error) {
+	config, err := os.ReadFile(configFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var enodes strings.Builder
+	for i := range boots {
+		en, err := enodeFromNodeKey(boots[i])
+		if err != nil {
+			return nil, err
+		}
+		enodes.WriteString(en.URLv4() + "\n")
+	}
+	return map[string][]byte{
+		artefacts.GenesisName: gen.JSON,
+		artefacts.ConfigName:  config,
+		artefacts.EnodesName:  []byte(enodes.String()),
+	}, nil
+}
+
+// fetchBootstrapArtefacts fetches and verifies the artefacts published for the
+// network, writes them to the directory and returns the genesis among them.
+// The genesis of a known network must match the embedded one, since its nodes
+// run with the latter. Other networks run from the written genesis, config and
+// boot nodes, with the flags logged once the artefacts are written.
+func fetchBootstrapArtefacts(
+	ctx context.Context,
+	store artefacts.Store,
+	network string,
+	signers []common.Address,
+	dirpath string,
+) (*core.Genesis, error) {
+	if dirpath == "" {
+		return nil, fmt.Errorf("fetching artefacts requires --%s", immutable.DataDirpath.Name)
+	}
+	_, files, err := artefacts.Fetch(ctx, store, network, signers)
+	if err != nil {
+		return nil, err
+	}
+	content, ok := files[artefacts.GenesisName]
+	if !ok {
+		return nil, fmt.Errorf("artefacts of %s have no %s", network, artefacts.GenesisName)
+	}
+	genesis := new(core.Genesis)
+	if err := json.Unmarshal(content, genesis); err != nil {
+		return nil, fmt.Errorf("failed to decode genesis artefact: %w", err)
+	}
+	if genesis.Config == nil || !genesis.Config.IsValidImmutableZKEVM() {
+		return nil, fmt.Errorf("invalid genesis config in artefacts of %s", network)
+	}
+	// Nodes of known networks run with the embedded genesis, which the fetched
+	// one must not diverge from
+	if _, err := settings.NewNetwork(network); err == nil {
+		embedded, fetched := core.ImmutableGenesisBlock(network).ToBlock().Hash(), genesis.ToBlock().Hash()
+		if fetched != embedded {
+			return nil, fmt.Errorf("genesis artefact of %s has hash %s, expected the embedded %s", network, fetched, embedded)
+		}
+	}
+	// The config and boot nodes are passed to the node at runtime, refuse
+	// any it would fail to load
+	var bootnodes []string
+	if content, ok := files[artefacts.EnodesName]; ok {
+		for _, line := range strings.Split(string(content), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			if _, err := enode.Parse(enode.ValidSchemes, line); err != nil {
+				return nil, fmt.Errorf("invalid enode in artefacts of %s: %w", network, err)
+			}
+			bootnodes = append(bootnodes, line)
+		}
+	}
+	if content, ok := files[artefacts.ConfigName]; ok {
+		if err := tomlSettings.NewDecoder(bytes.NewReader(content)).Decode(new(gethConfig)); err != nil {
+			return nil, fmt.Errorf("invalid config in artefacts of %s: %w", network, err)
+		}
+	}
+	if err := os.MkdirAll(dirpath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create datadir: %w", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dirpath, name), content, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write artefact %s: %w", name, err)
+		}
+	}
+	args := []string{"--datadir", dirpath}
+	if _, ok := files[artefacts.ConfigName]; ok {
+		args = append(args, "--config", filepath.Join(dirpath, artefacts.ConfigName))
+	}
+	if len(bootnodes) > 0 {
+		args = append(args, "--bootnodes", strings.Join(bootnodes, ","))
+	}
+	log.Info("Fetched bootstrap artefacts, run the node with them", "network", network, "flags", strings.Join(args, " "))
+	return genesis, nil
+}
+
+// publishArtefactsCommand publishes files, such as the genesis and config TOML
+// of an existing network, as the artefacts of the network. Each argument is a
+// file path, optionally prefixed by the artefact name, e.g. genesis.json=testnet.json.
+func publishArtefactsCommand(c *cli.Context) error {
+	network := c.String(utils.ImmutableNetworkFlag.Name)
+	if network == "" {
+		return fmt.Errorf("--%s is required", utils.ImmutableNetworkFlag.Name)
+	}
+	if c.NArg() == 0 {
+		return errors.New("no artefact files given")
+	}
+	store, err := openArtefactStore(c)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("--%s is required", immutable.Artefacts.Name)
+	}
+	defer store.Close()
+	key, err := loadArtefactKey(c)
+	if err != nil {
+		return err
+	}
+	// Artefacts are named after the files unless named explicitly
+	files := make(map[string][]byte, c.NArg())
+	for _, arg := range c.Args().Slice() {
+		name, path, ok := strings.Cut(arg, "=")
+		if !ok {
+			name, path = filepath.Base(arg), arg
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read artefact: %w", err)
+		}
+		files[name] = content
+	}
+	manifest, err := artefacts.Publish(c.Context, store, network, files, key)
+	if err != nil {
+		return err
+	}
+	for _, artefact := range manifest.Artefacts {
+		fmt.Printf("%s\t%s\t%d bytes\n", artefact.Name, artefact.Digest, artefact.Size)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/genesis"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/artefacts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestImmutableBootstrap_RPCFromArtefacts(t *testing.T) {
+	ctx := context.Background()
+	store, err := artefacts.Open("file://" + filepath.Join(t.TempDir(), "artefacts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+
+	// Publish the genesis of a network unknown to geth along with its config
+	// and boot nodes
+	gen := genesis.Clique(genesis.CliqueOptions{
+		ChainID:         settings.DevnetNetworkID,
+		GasLimit:        30_000_000,
+		SecondsPerBlock: settings.SecondsPerBlock,
+		Validators:      []common.Address{{0x01}},
+	})
+	genJSON, err := json.Marshal(gen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enodes := enode.NewV4(&key.PublicKey, net.IP{127, 0, 0, 1}, 30303, 30303).URLv4() + "\n"
+	files := map[string][]byte{
+		artefacts.GenesisName: genJSON,
+		artefacts.ConfigName:  []byte("[Eth]\n"),
+		artefacts.EnodesName:  []byte(enodes),
+	}
+	if _, err := artefacts.Publish(ctx, store, localArtefactNetwork, files, key); err != nil {
+		t.Fatal(err)
+	}
+
+	// Untrusted manifests are refused before anything is written
+	dataDirpath := filepath.Join(t.TempDir(), "rpc")
+	if _, err := fetchBootstrapArtefacts(ctx, store, localArtefactNetwork, []common.Address{{0x02}}, dataDirpath); !errors.Is(err, artefacts.ErrUntrustedSigner) {
+		t.Fatalf("expected untrusted signer error, got %v", err)
+	}
+	if _, err := os.Stat(dataDirpath); !os.IsNotExist(err) {
+		t.Fatalf("expected no datadir, got %v", err)
+	}
+
+	// The fetched genesis bootstraps the node
+	fetched, err := fetchBootstrapArtefacts(ctx, store, localArtefactNetwork, signers, dataDirpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.ToBlock().Hash() != gen.ToBlock().Hash() {
+		t.Fatalf("expected genesis %s, got %s", gen.ToBlock().Hash(), fetched.ToBlock().Hash())
+	}
+	for name, content := range files {
+		written, err := os.ReadFile(filepath.Join(dataDirpath, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(written) != string(content) {
+			t.Fatalf("unexpected content of %s", name)
+		}
+	}
+	b, err := bootstrapFactory(role.RPC, nil, "", dataDirpath, "", fetched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Bootstrap(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDirpath, "geth", "chaindata")); err != nil {
+		t.Fatalf("expected chain state: %v", err)
+	}
+
+	// Known networks only accept their embedded genesis
+	if _, err := artefacts.Publish(ctx, store, "devnet", files, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchBootstrapArtefacts(ctx, store, "devnet", signers, t.TempDir()); err == nil {
+		t.Fatal("expected genesis mismatch error")
+	}
+	embeddedJSON, err := json.Marshal(core.ImmutableGenesisBlock("devnet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artefacts.Publish(ctx, store, "devnet", map[string][]byte{artefacts.GenesisName: embeddedJSON}, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchBootstrapArtefacts(ctx, store, "devnet", signers, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Config and boot nodes the node would fail to load are refused
+	for name, content := range map[string]string{
+		artefacts.ConfigName: "[Eth\n",
+		artefacts.EnodesName: "enode://invalid\n",
+	} {
+		invalid := map[string][]byte{artefacts.GenesisName: genJSON, name: []byte(content)}
+		if _, err := artefacts.Publish(ctx, store, localArtefactNetwork, invalid, key); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fetchBootstrapArtefacts(ctx, store, localArtefactNetwork, signers, t.TempDir()); err == nil {
+			t.Fatalf("expected invalid %s error", name)
+		}
+	}
+
+	// Genesis that are not for an Immutable zkEVM network are refused
+	gen.Config.ChainID.SetInt64(1)
+	if genJSON, err = json.Marshal(gen); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artefacts.Publish(ctx, store, localArtefactNetwork, map[string][]byte{artefacts.GenesisName: genJSON}, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fetchBootstrapArtefacts(ctx, store, localArtefactNetwork, signers, t.TempDir()); err == nil {
+		t.Fatal("expected invalid genesis error")
+	}
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/node"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
 	"github.com/ethereum/go-ethereum/cmd/immutable"
-	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/urfave/cli/v2"
@@ -50,6 +50,16 @@ func getSecretID(role role.Role) (string, error) {
 	return secretID, nil
 }
 
+// openSecretProvider opens the secret provider configured by the flags,
+// falling back to AWS Secrets Manager in the configured region.
+func openSecretProvider(c *cli.Context) (secrets.Provider, error) {
+	uri := c.String(immutable.SecretProvider.Name)
+	if uri == "" {
+		uri = "aws://" + c.String(immutable.Region.Name)
+	}
+	return secrets.Open(uri)
+}
+
 func bootstrapK8sNodeCommand(c *cli.Context) error {
 	// Role
 	r, err := role.NewFromString(c.String(immutable.Role.Name))
@@ -58,7 +68,7 @@ func bootstrapK8sNodeCommand(c *cli.Context) error {
 	}
 
 	// Setup store
-	store, err := aws.NewSecretsManager(c.String(immutable.Region.Name))
+	store, err := openSecretProvider(c)
 	if err != nil {
 		return err
 	}
@@ -91,14 +101,37 @@ func bootstrapK8sNodeCommand(c *cli.Context) error {
 }
 
 func bootstrapExternalNodeCommand(c *cli.Context) error {
+	network := c.String(utils.ImmutableNetworkFlag.Name)
+	dataDirpath := c.String(immutable.DataDirpath.Name)
+
+	// Fetch the genesis from the artefact store if configured, otherwise
+	// use the genesis embedded for the network
+	store, err := openArtefactStore(c)
+	if err != nil {
+		return err
+	}
+	var genesis *core.Genesis
+	if store != nil {
+		defer store.Close()
+		signers, err := artefactSigners(c)
+		if err != nil {
+			return err
+		}
+		if genesis, err = fetchBootstrapArtefacts(c.Context, store, network, signers, dataDirpath); err != nil {
+			return err
+		}
+	} else {
+		genesis = core.ImmutableGenesisBlock(network)
+	}
+
 	// Get bootstrapper and run it
 	b, err := bootstrapFactory(
 		role.RPC,
 		nil,
 		"",
-		c.String(immutable.DataDirpath.Name),
+		dataDirpath,
 		"",
-		core.ImmutableGenesisBlock(c.String(utils.ImmutableNetworkFlag.Name)),
+		genesis,
 	)
 	if err != nil {
 		return err
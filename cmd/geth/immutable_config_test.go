@@ -0,0 +1,93 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+)
+
+func TestImmutableLoadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+[Eth]
+FetcherCatchUp = "auto"
+PeerRegistry = "http://boot-0:8550"
+BlockAccessPolicy = "reject"
+BlockAccessPolicyFrom = 100
+
+[Eth.Gossip]
+Validators = ["0x01", "0x02"]
+Partners = ["0x03"]
+
+[Eth.Gossip.Policies]
+partner = "none"
+inbound = "announce"
+
+[Eth.RPCProxy]
+Enabled = true
+Retries = 1
+
+[[Eth.RPCProxy.Upstreams]]
+Name = "sequencer"
+URL = "http://sequencer:8545"
+Weight = 2
+`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := gethConfig{Eth: ethconfig.Defaults}
+	if err := loadConfig(file, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := ethconfig.GossipConfig{
+		Validators: []string{"0x01", "0x02"},
+		Partners:   []string{"0x03"},
+		Policies:   map[string]string{"partner": "none", "inbound": "announce"},
+	}
+	if !reflect.DeepEqual(cfg.Eth.Gossip, want) {
+		t.Fatalf("gossip config mismatch:\nhave %+v\nwant %+v", cfg.Eth.Gossip, want)
+	}
+	if cfg.Eth.FetcherCatchUp != "auto" || cfg.Eth.PeerRegistry != "http://boot-0:8550" || cfg.Eth.BlockAccessPolicy != "reject" {
+		t.Fatalf("unexpected settings: %q %q %q", cfg.Eth.FetcherCatchUp, cfg.Eth.PeerRegistry, cfg.Eth.BlockAccessPolicy)
+	}
+	if cfg.Eth.BlockAccessPolicyFrom == nil || *cfg.Eth.BlockAccessPolicyFrom != 100 {
+		t.Fatalf("unexpected block access policy start: %v", cfg.Eth.BlockAccessPolicyFrom)
+	}
+	if !cfg.Eth.RPCProxy.Enabled || len(cfg.Eth.RPCProxy.Upstreams) != 1 || cfg.Eth.RPCProxy.Upstreams[0].Weight != 2 || cfg.Eth.RPCProxy.Retries != 1 {
+		t.Fatalf("unexpected proxy config: %+v", cfg.Eth.RPCProxy)
+	}
+	// The dumped configuration loads back into the same settings
+	out, err := tomlSettings.Marshal(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, out, 0644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := gethConfig{Eth: ethconfig.Defaults}
+	if err := loadConfig(file, &reloaded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reloaded.Eth.Gossip, want) || !reflect.DeepEqual(reloaded.Eth.RPCProxy, cfg.Eth.RPCProxy) {
+		t.Fatalf("dumped config mismatch: %+v", reloaded.Eth)
+	}
+}
@@ -73,7 +73,32 @@ func configureNodeInPodCommand(c *cli.Context) error {
 	if pod == "" {
 		return fmt.Errorf("POD_NAME env var must be set")
 	}
-	return configureNodeInPod(r, pod, filepaths)
+	if err := configureNodeInPod(r, pod, filepaths); err != nil {
+		return err
+	}
+	// Check that the node's key can be retrieved from the secret provider
+	if c.IsSet(immutable.SecretProvider.Name) && (r == role.Boot || r == role.Validator) {
+		return checkNodeSecret(c, r)
+	}
+	return nil
+}
+
+// checkNodeSecret verifies that the node's secret exists in the configured
+// secret provider, without decoding it.
+func checkNodeSecret(c *cli.Context, r role.Role) error {
+	secretID, err := getSecretID(r)
+	if err != nil {
+		return err
+	}
+	provider, err := openSecretProvider(c)
+	if err != nil {
+		return err
+	}
+	if _, err := provider.GetSecret(c.Context, secretID); err != nil {
+		return fmt.Errorf("failed to retrieve %s secret: %w", r.String(), err)
+	}
+	log.Info("validated secret", "role", r.String(), "secret", secretID)
+	return nil
 }
 
 // configureNodeInPod will configure a geth node in a k8s (statefulset) pod based on its ordinal
@@ -17,12 +17,13 @@
 package main
 
 import (
+	"fmt"
 	"math/big"
+	"os"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
 	"github.com/ethereum/go-ethereum/cmd/utils"
-	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
@@ -52,15 +53,28 @@ func immutableOverrides(ctx *cli.Context, cfg *gethConfig) {
 		utils.ImmutableNetworkFlag,
 		utils.OverrideCancun,
 	)
+	utils.CheckExclusive(
+		ctx,
+		utils.ImmutableNetworkFlag,
+		utils.OverrideForks,
+	)
 	// Set overrides based on network flag.
 	if ctx.IsSet(utils.ImmutableNetworkFlag.Name) {
-		genesis := core.ImmutableGenesisBlock(ctx.String(utils.ImmutableNetworkFlag.Name))
-		cfg.Eth.OverrideShanghai = genesis.Config.ShanghaiTime
-		cfg.Eth.OverridePrevrandao = genesis.Config.PrevrandaoTime
-		cfg.Eth.OverrideCancun = genesis.Config.CancunTime
-		// All overrides are handled in the genesis block, so we can terminate here
+		network, err := settings.NewNetwork(ctx.String(utils.ImmutableNetworkFlag.Name))
+		if err != nil {
+			utils.Fatalf("%v", err)
+		}
+		// All forks are defined by the fork schedule of the network, so we can terminate here
+		cfg.Eth.OverrideForks = network.Forks().Times()
 		return
 	}
+	if ctx.IsSet(utils.OverrideForks.Name) {
+		schedule, err := readForkSchedule(ctx.String(utils.OverrideForks.Name))
+		if err != nil {
+			utils.Fatalf("%v", err)
+		}
+		cfg.Eth.OverrideForks = schedule.Times()
+	}
 	// zkEVM flag was not set so lets check individual override flags for testing
 	if ctx.IsSet(utils.OverridePrevrandao.Name) {
 		val := ctx.Uint64(utils.OverridePrevrandao.Name)
@@ -76,6 +90,15 @@ func immutableOverrides(ctx *cli.Context, cfg *gethConfig) {
 	}
 }
 
+// readForkSchedule reads and validates a fork schedule file.
+func readForkSchedule(path string) (settings.ForkSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fork schedule: %w", err)
+	}
+	return settings.ParseForkSchedule(data)
+}
+
 // ImmutableEthConfig is the default content of config.toml that
 // all zkEVM geth nodes should be configured to use.
 func ImmutableEthConfig(chainID int) ethconfig.Config {
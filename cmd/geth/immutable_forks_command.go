@@ -0,0 +1,194 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/urfave/cli/v2"
+)
+
+// runListForksCommand prints the forks quarantined by the reorg invariant.
+func runListForksCommand(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	forks := rawdb.ReadAllQuarantinedForks(db)
+	if len(forks) == 0 {
+		fmt.Println("No quarantined forks")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tQUARANTINED\tCOMMON\tCANONICAL\tSIDE\tPEERS")
+	for _, fork := range forks {
+		peers := make(map[string]struct{})
+		for _, origin := range fork.Origins {
+			peers[origin.Source+":"+origin.Peer] = struct{}{}
+		}
+		ancestor := strconv.FormatUint(fork.CommonNumber, 10)
+		if fork.Truncated {
+			ancestor = "truncated"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n", fork.ID().Hex(), time.Unix(int64(fork.Time), 0).UTC().Format(time.RFC3339),
+			ancestor, len(fork.Canonical), len(fork.Side), len(peers))
+	}
+	return w.Flush()
+}
+
+// runDiffForkCommand prints the header, signer and transaction differences
+// between the canonical chain and a quarantined fork.
+func runDiffForkCommand(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("This command requires an argument (quarantined fork id).")
+	}
+	arg := ctx.Args().First()
+	if !hashish(arg) {
+		return fmt.Errorf("invalid fork id %q", arg)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+
+	fork := rawdb.ReadQuarantinedFork(db, common.HexToHash(arg))
+	if fork == nil {
+		return fmt.Errorf("quarantined fork %s not found", arg)
+	}
+	printForkDiff(os.Stdout, core.DiffQuarantinedFork(fork, chain.Engine().Author))
+	return nil
+}
+
+// printForkDiff renders a fork diff in a human readable form.
+func printForkDiff(out io.Writer, diff *core.ForkDiff) {
+	fmt.Fprintf(out, "Fork:          %s\n", diff.ID.Hex())
+	fmt.Fprintf(out, "Quarantined:   %s\n", time.Unix(int64(diff.Time), 0).UTC().Format(time.RFC3339))
+	if diff.Truncated {
+		fmt.Fprintln(out, "Common block:  unknown (fork truncated to the most recent blocks)")
+	} else {
+		fmt.Fprintf(out, "Common block:  %d (%s)\n", diff.CommonNumber, diff.CommonHash.Hex())
+	}
+	for _, origin := range diff.Origins {
+		fmt.Fprintf(out, "Origin:        %s from %s peer %s\n", origin.Hash.Hex(), origin.Source, origin.Peer)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NUMBER\tBRANCH\tHASH\tSIGNER\tDIFFICULTY\tTXS\t")
+	for _, h := range diff.Heights {
+		for _, branch := range []struct {
+			name  string
+			block *core.ForkBlock
+		}{{"canonical", h.Canonical}, {"side", h.Side}} {
+			if branch.block == nil {
+				fmt.Fprintf(w, "%d\t%s\t-\t-\t-\t-\t\n", h.Number, branch.name)
+				continue
+			}
+			signer := "unknown"
+			if branch.block.Signer != nil {
+				signer = branch.block.Signer.Hex()
+			}
+			marker := ""
+			if h.Equivocation {
+				marker = "EQUIVOCATION"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%v\t%d\t%s\n", h.Number, branch.name, branch.block.Hash.Hex(), signer,
+				branch.block.Difficulty.ToInt(), len(branch.block.Transactions), marker)
+		}
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+
+	if len(diff.DoubleSigners) > 0 {
+		fmt.Fprintln(out, "Signers which sealed both branches:")
+		for _, signer := range diff.DoubleSigners {
+			fmt.Fprintf(out, "  %s\n", signer.Hex())
+		}
+	} else {
+		fmt.Fprintln(out, "No signer sealed both branches at the same height")
+	}
+	printTxs := func(title string, txs []common.Hash) {
+		fmt.Fprintf(out, "%s (%d):\n", title, len(txs))
+		for _, tx := range txs {
+			fmt.Fprintf(out, "  %s\n", tx.Hex())
+		}
+	}
+	printTxs("Transactions only on the canonical branch", diff.CanonicalOnlyTxs)
+	printTxs("Transactions only on the side branch", diff.SideOnlyTxs)
+	printTxs("Transactions on both branches", diff.SharedTxs)
+}
+
+// runScheduleForksCommand prints the fork schedule of an Immutable network or
+// of a schedule file, counting down to the upcoming activations.
+func runScheduleForksCommand(ctx *cli.Context) error {
+	utils.CheckExclusive(ctx, utils.ImmutableNetworkFlag, utils.OverrideForks)
+
+	var schedule settings.ForkSchedule
+	switch {
+	case ctx.IsSet(utils.OverrideForks.Name):
+		var err error
+		if schedule, err = readForkSchedule(ctx.String(utils.OverrideForks.Name)); err != nil {
+			return err
+		}
+	case ctx.IsSet(utils.ImmutableNetworkFlag.Name):
+		network, err := settings.NewNetwork(ctx.String(utils.ImmutableNetworkFlag.Name))
+		if err != nil {
+			return err
+		}
+		schedule = network.Forks()
+	default:
+		return fmt.Errorf("either --%s or --%s is required", utils.ImmutableNetworkFlag.Name, utils.OverrideForks.Name)
+	}
+	return printForkSchedule(os.Stdout, schedule, time.Now())
+}
+
+// printForkSchedule prints the forks of a schedule with their activation
+// times and the time left until the upcoming ones activate.
+func printForkSchedule(out io.Writer, schedule settings.ForkSchedule, now time.Time) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FORK\tACTIVATION\tTIMESTAMP\tSTATUS")
+	for _, fork := range schedule {
+		status := "active"
+		if !fork.Time.IsEnabledAt(now.Unix()) {
+			status = "in " + formatCountdown(fork.Time.Sub(now))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", fork.Name, fork.Time.UTC().Format(time.UnixDate), fork.Time.Unix(), status)
+	}
+	return w.Flush()
+}
+
+// formatCountdown formats a duration in days, hours, minutes and seconds.
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	if days > 0 {
+		return fmt.Sprintf("%dd%s", days, d)
+	}
+	return d.String()
+}
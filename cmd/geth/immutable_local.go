@@ -17,6 +17,7 @@
 package main
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"os"
 	"time"
@@ -84,16 +85,34 @@ func bootstrapLocalCommand(c *cli.Context) error {
 		return fmt.Errorf("cannot have more than 9 nodes")
 	}
 
+	// Artefacts are only published for new networks
+	artefactStore, err := openArtefactStore(c)
+	if err != nil {
+		return err
+	}
+	var artefactKey *ecdsa.PrivateKey
+	if artefactStore != nil {
+		defer artefactStore.Close()
+		if remoteNetwork != "" {
+			return fmt.Errorf("cannot publish artefacts of %s", remoteNetwork)
+		}
+		if artefactKey, err = loadArtefactKey(c); err != nil {
+			return err
+		}
+	}
+
 	// Construct the bootstrapper and run the nodes
 	opts := bootstrapOptions{
-		rootDirpath:             rootDirpath,
-		validatorCount:          validatorCount,
-		bootCount:               bootCount,
-		rpcCount:                rpcCount,
-		gasLimit:                gasLimit,
-		blockListFilepath:       blockListFilepath,
-		remoteNetwork:           remoteNetwork,
-		remoteConfigFilepath:    externalConfigFilepath,
+		rootDirpath:          rootDirpath,
+		validatorCount:       validatorCount,
+		bootCount:            bootCount,
+		rpcCount:             rpcCount,
+		gasLimit:             gasLimit,
+		blockListFilepath:    blockListFilepath,
+		remoteNetwork:        remoteNetwork,
+		remoteConfigFilepath: externalConfigFilepath,
+		artefacts:            artefactStore,
+		artefactKey:          artefactKey,
 	}
 	bootstrapper, err := NewLocalBootstrapper(&opts)
 	if err != nil {
@@ -17,42 +17,36 @@
 package main
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/env"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/genesis"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/node"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/artefacts"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	ethnode "github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p/enode"
-	"github.com/ethereum/go-ethereum/params"
 	"github.com/urfave/cli/v2"
 )
 
-// Premine contains addresses and amounts of premined balances
-// in the genesis block
-type Premine struct {
-	Address common.Address
-	Wei     *big.Int
-}
-
 // ChainOptions contains all the options for generating a genesis
 type ChainOptions struct {
 	GasLimit        uint64
 	SecondsPerBlock uint64
 
 	Validators []common.Address
-	Premines   []Premine
+	Premines   []genesis.Premine
 
 	ChainID int
 	Dirpath string
@@ -89,6 +83,10 @@ type bootstrapOptions struct {
 	remoteNetwork        string
 	remoteConfigFilepath string
 
+	// Set these to publish the genesis, config and enodes of a new network.
+	artefacts   artefacts.Store
+	artefactKey *ecdsa.PrivateKey
+
 	// NOTE: This type should not be used here (compartmentalization bad).
 	// We have not refactored geth to improve the bootstrapper in this regard.
 	ctx *cli.Context
@@ -209,6 +207,15 @@ func NewLocalBootstrapper(opts *bootstrapOptions) (*LocalBootstrapper, error) {
 		if err := renderLocalConfig(boots, configFilepath, network.ID()); err != nil {
 			return nil, err
 		}
+		if opts.artefacts != nil {
+			files, err := localArtefacts(gen, configFilepath, boots)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := artefacts.Publish(context.Background(), opts.artefacts, localArtefactNetwork, files, opts.artefactKey); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	return &LocalBootstrapper{
@@ -254,60 +261,13 @@ func Clique(opts ChainOptions) (*Genesis, error) {
 }
 
 func clique(opts ChainOptions) (*Genesis, error) {
-	// To encode the signer addresses in extradata,
-	// concatenate 32 zero bytes, all signer addresses and 65 further zero bytes.
-	// This is based on the clique spec: https://eips.ethereum.org/EIPS/eip-225
-	extraDataLength := crypto.DigestLength + common.AddressLength*len(opts.Validators) + crypto.SignatureLength
-	extraData := make([]byte, extraDataLength)
-	for i := range opts.Validators {
-		from := crypto.DigestLength + common.AddressLength*i
-		to := from + common.AddressLength
-		copy(extraData[from:to], opts.Validators[i].Bytes())
-	}
-
-	// Premine
-	alloc := types.GenesisAlloc{}
-	for i := range opts.Premines {
-		alloc[opts.Premines[i].Address] = types.Account{
-			Balance: opts.Premines[i].Wei,
-		}
-	}
-
-	// Marshal the genesis
-	gen := core.Genesis{
-		Config: &params.ChainConfig{
-			ChainID:             new(big.Int).SetUint64(uint64(opts.ChainID)),
-			HomesteadBlock:      common.Big0,
-			EIP150Block:         common.Big0,
-			EIP155Block:         common.Big0,
-			EIP158Block:         common.Big0,
-			ByzantiumBlock:      common.Big0,
-			ConstantinopleBlock: common.Big0,
-			PetersburgBlock:     common.Big0,
-			IstanbulBlock:       common.Big0,
-			MuirGlacierBlock:    common.Big0,
-			BerlinBlock:         common.Big0,
-			LondonBlock:         common.Big0,
-			ArrowGlacierBlock:   common.Big0,
-			GrayGlacierBlock:    common.Big0,
-			MergeNetsplitBlock:  common.Big0,
-			Clique: &params.CliqueConfig{
-				Period: opts.SecondsPerBlock,
-				Epoch:  30000,
-			},
-			IsReorgBlocked: true,
-			// So as to reflect devnet, testnet, and mainnet, these forks should not be enabled in genesis.
-			ShanghaiTime:   nil,
-			PrevrandaoTime: nil,
-			CancunTime:     nil, // If genesis block has Cancun enabled, you must set the blob-related headers too.
-		},
-
-		Difficulty: big.NewInt(1),
-		GasLimit:   opts.GasLimit,
-		Mixhash:    common.Hash{},
-		ExtraData:  extraData,
-		Alloc:      alloc,
-	}
+	gen := genesis.Clique(genesis.CliqueOptions{
+		ChainID:         opts.ChainID,
+		GasLimit:        opts.GasLimit,
+		SecondsPerBlock: opts.SecondsPerBlock,
+		Validators:      opts.Validators,
+		Premines:        opts.Premines,
+	})
 	json, err := json.MarshalIndent(gen, "", "    ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal genesis: %w", err)
@@ -316,7 +276,7 @@ func clique(opts ChainOptions) (*Genesis, error) {
 	// Write genesis file
 	path := filepath.Join(opts.Dirpath, "genesis.json")
 	return &Genesis{
-		Genesis:  &gen,
+		Genesis:  gen,
 		Filepath: path,
 		JSON:     json,
 	}, nil
@@ -20,6 +20,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/env"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/genesis"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -66,9 +67,9 @@ var (
 	}
 )
 
-func immutablePremines(envr env.Environment, bridgeEOA common.Address) []Premine {
+func immutablePremines(envr env.Environment, bridgeEOA common.Address) []genesis.Premine {
 	// Always premine bridge EOA
-	premines := []Premine{
+	premines := []genesis.Premine{
 		{
 			Address: bridgeEOA,
 			Wei:     totalSupplyWei,
@@ -79,7 +80,7 @@ func immutablePremines(envr env.Environment, bridgeEOA common.Address) []Premine
 		return premines
 	}
 	for _, address := range immutableDevPremineAddresses {
-		premines = append(premines, Premine{
+		premines = append(premines, genesis.Premine{
 			Address: common.HexToAddress(address),
 			Wei:     totalSupplyWei,
 		})
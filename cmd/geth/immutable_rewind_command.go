@@ -17,12 +17,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/rewind"
+	"github.com/ethereum/go-ethereum/cmd/immutable"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -47,6 +51,33 @@ func runRewindChainCommand(ctx *cli.Context) error {
 	// Set up the node handle.
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
+
+	// A dry run only reads the database, it is opened read-only so that no
+	// blockchain repairs the head or writes to the datadir.
+	if ctx.Bool(immutable.DryRun.Name) {
+		db := utils.MakeChainDatabase(ctx, stack, true)
+		defer db.Close()
+		header, err := getRewindHeader(ctx, db)
+		if err != nil {
+			return err
+		}
+		head := rawdb.ReadHeadHeader(db)
+		if head == nil {
+			return errors.New("head block not found")
+		}
+		config := rawdb.ReadChainConfig(db, rawdb.ReadCanonicalHash(db, 0))
+		if config == nil {
+			return errors.New("chain config not found")
+		}
+		triedb := utils.MakeTrieDatabase(ctx, db, false, true, false)
+		defer triedb.Close()
+		plan, err := rewind.NewPlan(db, triedb, config, head, header)
+		if err != nil {
+			return err
+		}
+		fmt.Print(plan)
+		return nil
+	}
 	chain, db := utils.MakeChain(ctx, stack, false)
 	defer db.Close()
 
@@ -68,17 +99,39 @@ func runRewindChainCommand(ctx *cli.Context) error {
 		return nil
 	}
 
+	// Report what the rewind would delete and refuse unsafe rewinds unless forced.
+	head := chain.CurrentBlock()
+	plan, err := rewind.NewPlan(db, chain.TrieDB(), chain.Config(), head, header)
+	if err != nil {
+		return err
+	}
+	fmt.Print(plan)
+	if unsafe := plan.Unsafe(); len(unsafe) > 0 {
+		if !ctx.Bool(immutable.Force.Name) {
+			return fmt.Errorf("refusing unsafe rewind (use --%s to override): %s", immutable.Force.Name, strings.Join(unsafe, "; "))
+		}
+		log.Warn("Forcing unsafe rewind", "reasons", unsafe)
+	}
+	operator := ctx.String(immutable.Operator.Name)
+	if operator == "" {
+		operator = os.Getenv("USER")
+	}
+
 	// Perform the rewind.
-	log.Info("Rewinding chain to block", "block", header.Number)
+	log.Info("Rewinding chain to block", "block", header.Number, "operator", operator, "reason", ctx.String(immutable.Reason.Name))
 	if err := chain.SetHead(header.Number.Uint64()); err != nil {
 		return err
 	}
 
 	// Record the fact that the chain has been rewound.
 	history = append(history, rewind.Record{
-		BlockNumber: header.Number.Uint64(),
-		BlockHash:   header.Hash(),
-		Timestamp:   time.Now(),
+		BlockNumber:        header.Number.Uint64(),
+		BlockHash:          header.Hash(),
+		Timestamp:          time.Now(),
+		Operator:           operator,
+		Reason:             ctx.String(immutable.Reason.Name),
+		PreviousHeadNumber: head.Number.Uint64(),
+		PreviousHeadHash:   head.Hash(),
 	})
 	return rewind.WriteRewindHistory(history, historyFilepath)
 }
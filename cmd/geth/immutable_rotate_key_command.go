@@ -0,0 +1,145 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/keys"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/vote"
+	"github.com/ethereum/go-ethereum/cmd/immutable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/slices"
+)
+
+// rotateKeyCommand rotates the sealing key of a running validator without
+// downtime.
+func rotateKeyCommand(c *cli.Context) error {
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := openSecretProvider(c)
+	if err != nil {
+		return err
+	}
+	validator, err := urlsToClients(ctx, []string{c.String(immutable.ValidatorURL.Name)})
+	if err != nil {
+		return err
+	}
+	voters, err := urlsToClients(ctx, c.StringSlice(immutable.Voters.Name))
+	if err != nil {
+		return err
+	}
+	_, err = rotateKey(
+		ctx,
+		store,
+		c.String(immutable.SecretID.Name),
+		validator[0],
+		voters,
+		c.Duration(immutable.VoteTimeout.Name),
+		c.Duration(immutable.VotePollInterval.Name),
+	)
+	return err
+}
+
+// rotateKey stages a new key for the validator in the store and votes it in,
+// while the validator keeps sealing with its previous key. The new key then
+// replaces the previous one in the store and the validator reloads it, after
+// which the previous signer is voted out. It returns the new signer.
+func rotateKey(
+	ctx context.Context,
+	store keys.Store,
+	secretID string,
+	validator *gethclient.Client,
+	voterClients []*gethclient.Client,
+	timeout, interval time.Duration,
+) (common.Address, error) {
+	previous, err := validator.Etherbase(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get etherbase of validator: %w", err)
+	}
+	current, voters, err := readVoters(ctx, voterClients)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !slices.Contains(current, previous) {
+		return common.Address{}, fmt.Errorf("validator signer %s is not part of the validator set (%s)", previous, addressesToCSV(current))
+	}
+	key, err := keys.Stage(ctx, store, secretID, previous)
+	if err != nil {
+		return common.Address{}, err
+	}
+	next := crypto.PubkeyToAddress(key.PublicKey)
+	stagedID := keys.StagedSecretID(secretID)
+	log.Info("Staged new validator key", "previous", previous, "new", next, "secret", stagedID)
+
+	// Vote the new signer in. The validator keeps its previous key in the store
+	// until the vote passes, so a failed vote leaves it untouched.
+	plan, err := vote.ComputePlan(current, append(slices.Clone(current), next), voters)
+	if err == nil {
+		err = applyPlan(ctx, voterClients, voters, plan, timeout, interval)
+	}
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to vote in new signer %s staged in %s: %w", next, stagedID, err)
+	}
+	// Promote the new key for the validator to reload it
+	if _, err := keys.Promote(ctx, store, secretID, previous); err != nil {
+		return common.Address{}, fmt.Errorf("new signer %s was voted in but its key could not be promoted from %s: %w", next, stagedID, err)
+	}
+	// Switch the validator over to the new key
+	signer, err := validator.RotateSigner(ctx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("new signer %s was voted in but the validator failed to switch to it: %w", next, err)
+	}
+	if signer != next {
+		return common.Address{}, fmt.Errorf("validator switched to signer %s, expected %s", signer, next)
+	}
+	log.Info("Validator switched to new signer", "signer", signer)
+
+	// Vote the previous signer out, the validator now votes with the new one
+	if current, voters, err = readVoters(ctx, voterClients); err != nil {
+		return common.Address{}, err
+	}
+	desired := slices.DeleteFunc(slices.Clone(current), func(a common.Address) bool { return a == previous })
+	if plan, err = vote.ComputePlan(current, desired, voters); err != nil {
+		return common.Address{}, err
+	}
+	if err := applyPlan(ctx, voterClients, voters, plan, timeout, interval); err != nil {
+		return common.Address{}, fmt.Errorf("failed to vote out previous signer %s: %w", previous, err)
+	}
+	// Confirm the previous signer is gone from every voter's snapshot
+	for i := range voterClients {
+		signers, err := voterClients[i].GetSigners(ctx, nil)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if slices.Contains(signers, previous) {
+			return common.Address{}, fmt.Errorf("previous signer %s is still part of the validator set on voter %d", previous, i)
+		}
+	}
+	log.Info("Validator key rotated", "previous", previous, "new", next)
+	return next, nil
+}
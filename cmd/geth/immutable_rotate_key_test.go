@@ -0,0 +1,148 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/keys"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/tests/immutable/harness"
+	"golang.org/x/exp/slices"
+)
+
+func TestImmutableRotateKey(t *testing.T) {
+	network, err := harness.New(harness.Options{Validators: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer network.Close()
+
+	validators := network.Validators()
+	clients := make([]*gethclient.Client, len(validators))
+	for i, v := range validators {
+		clients[i] = gethclient.New(v.Stack().Attach())
+	}
+	// Seal blocks in the background so that votes are cast
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(20 * time.Millisecond):
+				network.AdvanceBlocks(1)
+			}
+		}
+	}()
+	rotated := validators[0]
+	signer, err := rotateKey(context.Background(), network.Secrets, rotated.Name, clients[0], clients, time.Minute, 20*time.Millisecond)
+	close(stop)
+	<-stopped
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer == rotated.Address {
+		t.Fatal("expected a new signer")
+	}
+	key, err := keys.Retrieve(context.Background(), network.Secrets, rotated.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != signer {
+		t.Fatalf("expected stored key of %s, got %s", signer, addr)
+	}
+	for _, v := range validators {
+		snap, err := v.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := snap.Signers[rotated.Address]; ok {
+			t.Fatalf("%s: previous signer still authorized", v.Name)
+		}
+		if _, ok := snap.Signers[signer]; !ok {
+			t.Fatalf("%s: new signer not authorized", v.Name)
+		}
+	}
+	// The validator keeps sealing with the new key, including after a restart
+	if err := network.Kill(rotated); err != nil {
+		t.Fatal(err)
+	}
+	if err := network.Restart(rotated); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := rotated.Signer(); err != nil || current != signer {
+		t.Fatalf("expected signer %s after restart, got %s (%v)", signer, current, err)
+	}
+	blocks, err := network.AdvanceBlocks(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var authors []common.Address
+	for _, block := range blocks {
+		author, err := rotated.Eth().Engine().Author(block.Header())
+		if err != nil {
+			t.Fatal(err)
+		}
+		authors = append(authors, author)
+	}
+	if !slices.Contains(authors, signer) {
+		t.Fatalf("expected blocks sealed by %s, got %v", signer, authors)
+	}
+}
+
+func TestImmutableRotateKey_VoteFails(t *testing.T) {
+	network, err := harness.New(harness.Options{Validators: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer network.Close()
+
+	validators := network.Validators()
+	clients := make([]*gethclient.Client, len(validators))
+	for i, v := range validators {
+		clients[i] = gethclient.New(v.Stack().Attach())
+	}
+	// Without blocks being sealed, the votes never pass
+	rotated := validators[0]
+	if _, err := rotateKey(context.Background(), network.Secrets, rotated.Name, clients[0], clients, 100*time.Millisecond, 20*time.Millisecond); err == nil {
+		t.Fatal("expected the vote to fail")
+	}
+	// The validator's key is left in place, the new one stays staged
+	key, err := keys.Retrieve(context.Background(), network.Secrets, rotated.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != rotated.Address {
+		t.Fatalf("expected stored key of %s, got %s", rotated.Address, addr)
+	}
+	staged, err := keys.Retrieve(context.Background(), network.Secrets, keys.StagedSecretID(rotated.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := crypto.PubkeyToAddress(staged.PublicKey); addr == rotated.Address {
+		t.Fatal("expected a new staged key")
+	}
+	if current, err := rotated.Signer(); err != nil || current != rotated.Address {
+		t.Fatalf("expected signer %s, got %s (%v)", rotated.Address, current, err)
+	}
+}
@@ -20,17 +20,22 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 
 	"github.com/ethereum/go-ethereum/accounts/immutable"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/keys"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/node"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/registry"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
-	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+	cmdimmutable "github.com/ethereum/go-ethereum/cmd/immutable"
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/secrets"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/urfave/cli/v2"
 )
 
@@ -52,15 +57,15 @@ func runBootNodeCommand(c *cli.Context) error {
 			return err
 		}
 	} else {
-		// Retrieve the key from AWS
-		log.Info("retrieving P2P key from Secrets Manager")
-		awsRegion := os.Getenv("GETH_FLAG_IMMUTABLE_AWS_REGION")
-		if awsRegion == "" {
-			return fmt.Errorf("GETH_FLAG_IMMUTABLE_AWS_REGION is required")
+		// Retrieve the key from the secret provider
+		providerURI := keys.SecretProviderURI()
+		if providerURI == "" {
+			return fmt.Errorf("%s or %s is required", keys.SecretProviderEnvVar, keys.AWSRegionEnvVar)
 		}
-		store, err := aws.NewSecretsManager(awsRegion)
+		log.Info("retrieving P2P key from secret provider", "provider", providerURI)
+		store, err := secrets.Open(providerURI)
 		if err != nil {
-			return fmt.Errorf("failed to create AWS SecretsManager Store: %v ", err)
+			return fmt.Errorf("failed to open secret provider: %v ", err)
 		}
 		podOrdinal, err := ordinalFromPodNameEnvVar()
 		if err != nil {
@@ -85,10 +90,28 @@ func runBootNodeCommand(c *cli.Context) error {
 	}
 
 	// Run the boot node
-	return runBootNode(p2pKey, c.Int(utils.ListenPortFlag.Name))
+	return runBootNode(p2pKey, bootNodeOptions{
+		port:             c.Int(utils.ListenPortFlag.Name),
+		registryFilepath: c.String(cmdimmutable.RegistryFilepath.Name),
+		rpcAddr:          net.JoinHostPort(c.String(utils.HTTPListenAddrFlag.Name), fmt.Sprint(c.Int(utils.HTTPPortFlag.Name))),
+		publicRPCAddr:    c.String(cmdimmutable.RegistryPublicAddr.Name),
+	})
 }
 
-func runBootNode(p2pKey *ecdsa.PrivateKey, port int) error {
+// bootNodeOptions configures the boot node
+type bootNodeOptions struct {
+	port int
+	// registryFilepath is the file holding the registry of the nodes allowed to
+	// join discovery. Discovery is open to every node if it is empty.
+	registryFilepath string
+	// rpcAddr is the listen address of the admin RPC managing the registry
+	rpcAddr string
+	// publicRPCAddr is the listen address of the read-only RPC serving the
+	// signed registry to other nodes
+	publicRPCAddr string
+}
+
+func runBootNode(p2pKey *ecdsa.PrivateKey, opts bootNodeOptions) error {
 	// Log
 	glogger := log.NewGlogHandler(log.NewTerminalHandler(os.Stderr, false))
 	glogger.Verbosity(4)
@@ -96,7 +119,7 @@ func runBootNode(p2pKey *ecdsa.PrivateKey, port int) error {
 	log.SetDefault(log.NewLogger(glogger))
 
 	// UDP session
-	listenAddr := fmt.Sprintf(":%d", port)
+	listenAddr := fmt.Sprintf(":%d", opts.port)
 	addr, err := net.ResolveUDPAddr("udp", listenAddr)
 	if err != nil {
 		return fmt.Errorf("failed to resolve UDP address: %w", err)
@@ -110,6 +133,23 @@ func runBootNode(p2pKey *ecdsa.PrivateKey, port int) error {
 	// Enode DB
 	db, _ := enode.OpenDB("")
 	ln := enode.NewLocalNode(db, p2pKey)
+	ln.Set(enr.Role(role.Boot.String()))
+
+	// Restrict discovery to the registry members
+	var filter func(*enode.Node) bool
+	if opts.registryFilepath != "" {
+		reg, err := registry.Open(opts.registryFilepath, p2pKey)
+		if err != nil {
+			return err
+		}
+		filter = reg.Accepts
+		if _, err := serveRegistryAPI(reg, opts.rpcAddr); err != nil {
+			return err
+		}
+		if _, err := servePublicRegistryAPI(reg, opts.publicRPCAddr); err != nil {
+			return err
+		}
+	}
 
 	// Notice
 	listenerAddr := conn.LocalAddr().(*net.UDPAddr)
@@ -119,6 +159,7 @@ func runBootNode(p2pKey *ecdsa.PrivateKey, port int) error {
 	cfg := discover.Config{
 		PrivateKey:  p2pKey,
 		NetRestrict: nil,
+		Filter:      filter,
 	}
 	if _, err := discover.ListenUDP(conn, ln, cfg); err != nil {
 		return fmt.Errorf("failed to listen UDP: %w", err)
@@ -128,6 +169,67 @@ func runBootNode(p2pKey *ecdsa.PrivateKey, port int) error {
 	select {}
 }
 
+// serveRegistryAPI serves the admin RPC API managing the registry over HTTP.
+// The API is unauthenticated, so it is only served on loopback addresses.
+func serveRegistryAPI(reg *registry.Registry, addr string) (net.Listener, error) {
+	if err := checkLoopback(addr); err != nil {
+		return nil, err
+	}
+	listener, err := serveRPC(registry.NewAPI(reg), addr)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Serving registry admin RPC", "addr", listener.Addr())
+	return listener, nil
+}
+
+// servePublicRegistryAPI serves the signed registry over HTTP to the nodes
+// fetching it, on any address. Only the read-only admin_registry is exposed.
+func servePublicRegistryAPI(reg *registry.Registry, addr string) (net.Listener, error) {
+	listener, err := serveRPC(registry.NewPublicAPI(reg), addr)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Serving public registry RPC", "addr", listener.Addr())
+	return listener, nil
+}
+
+// serveRPC serves the API in the admin namespace over HTTP.
+func serveRPC(api interface{}, addr string) (net.Listener, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("admin", api); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for registry RPC: %w", err)
+	}
+	httpSrv := &http.Server{
+		Handler:           srv,
+		ReadTimeout:       rpc.DefaultHTTPTimeouts.ReadTimeout,
+		ReadHeaderTimeout: rpc.DefaultHTTPTimeouts.ReadHeaderTimeout,
+		WriteTimeout:      rpc.DefaultHTTPTimeouts.WriteTimeout,
+		IdleTimeout:       rpc.DefaultHTTPTimeouts.IdleTimeout,
+	}
+	go httpSrv.Serve(listener)
+	return listener, nil
+}
+
+// checkLoopback refuses listen addresses which are not loopback ones.
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid registry RPC address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("registry RPC must listen on a loopback address, not %q, as it is unauthenticated", host)
+	}
+	return nil
+}
+
 func printNotice(nodeKey *ecdsa.PublicKey, addr net.UDPAddr) {
 	if addr.IP.IsUnspecified() {
 		addr.IP = net.IP{127, 0, 0, 1}
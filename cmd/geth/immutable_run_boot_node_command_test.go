@@ -0,0 +1,115 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/registry"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestImmutableBootNode_RegistryLoopback(t *testing.T) {
+	for addr, ok := range map[string]bool{
+		"localhost:8545": true,
+		"127.0.0.1:8545": true,
+		"[::1]:8545":     true,
+		"0.0.0.0:8545":   false,
+		":8545":          false,
+		"10.0.0.1:8545":  false,
+		"example.com:80": false,
+		"127.0.0.1":      false,
+	} {
+		if err := checkLoopback(addr); (err == nil) != ok {
+			t.Errorf("%s: unexpected result %v", addr, err)
+		}
+	}
+}
+
+func TestImmutableBootNode_PublicRegistry(t *testing.T) {
+	// The fetching node reaches the boot node over a non-loopback interface
+	var host net.IP
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			host = ipnet.IP
+			break
+		}
+	}
+	if host == nil {
+		t.Skip("no non-loopback interface")
+	}
+	key, _ := crypto.GenerateKey()
+	reg, err := registry.Open("", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, _ := crypto.GenerateKey()
+	if err := reg.Add(enode.PubkeyToIDV4(&validator.PublicKey), role.Validator.String()); err != nil {
+		t.Fatal(err)
+	}
+	admin, err := serveRegistryAPI(reg, "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	public, err := servePublicRegistryAPI(reg, net.JoinHostPort(host.String(), "0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer public.Close()
+
+	client, err := rpc.DialContext(context.Background(), "http://"+public.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// The signed registry is served and verifies against the boot node
+	var signed registry.Signed
+	if err := client.Call(&signed, "admin_registry"); err != nil {
+		t.Fatal(err)
+	}
+	if err := signed.Verify(enode.PubkeyToIDV4(&key.PublicKey)); err != nil {
+		t.Fatal(err)
+	}
+	if len(signed.Members) != 1 || signed.Members[0].Role != role.Validator.String() {
+		t.Fatalf("unexpected members: %+v", signed.Members)
+	}
+	// Members can only be managed over loopback
+	if err := client.Call(nil, "admin_revokeMember", signed.Members[0].ID.String()); err == nil {
+		t.Fatal("public registry RPC manages members")
+	}
+	_, port, _ := net.SplitHostPort(admin.Addr().String())
+	if remote, err := rpc.DialContext(context.Background(), "http://"+net.JoinHostPort(host.String(), port)); err == nil {
+		if err := remote.Call(nil, "admin_revokeMember", signed.Members[0].ID.String()); err == nil {
+			t.Fatal("admin registry RPC reachable over a non-loopback interface")
+		}
+		remote.Close()
+	}
+	if _, ok := reg.Role(signed.Members[0].ID); !ok {
+		t.Fatal("member revoked")
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/verify"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/vote"
+	"github.com/ethereum/go-ethereum/cmd/immutable"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/exp/maps"
+)
+
+// runVerifyCommand opens a datadir read-only and reports every difference
+// between its genesis, chain config and clique signers and the configuration
+// expected for the selected Immutable network.
+func runVerifyCommand(ctx *cli.Context) error {
+	network, err := settings.NewNetwork(ctx.String(utils.ImmutableNetworkFlag.Name))
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	hash := rawdb.ReadCanonicalHash(db, 0)
+	if hash == (common.Hash{}) {
+		return errors.New("datadir holds no genesis block")
+	}
+	config := rawdb.ReadChainConfig(db, hash)
+	mismatches, err := verify.Genesis(network, hash, config)
+	if err != nil {
+		return err
+	}
+
+	// Check the signers of the latest stored snapshot against the expected set
+	if path := ctx.String(immutable.ValidatorSetFilepath.Name); path != "" {
+		expected, err := vote.ReadValidatorSet(path)
+		if err != nil {
+			return err
+		}
+		snap, err := verify.LatestSnapshot(db, config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Checking signers of the clique snapshot at block %d\n", snap.Number)
+		mismatches = append(mismatches, verify.Signers(expected, maps.Keys(snap.Signers))...)
+	}
+	for _, mismatch := range mismatches {
+		fmt.Println(mismatch)
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("datadir does not match the %s network: %d mismatches", network, len(mismatches))
+	}
+	fmt.Printf("Datadir matches the %s network\n", network)
+	return nil
+}
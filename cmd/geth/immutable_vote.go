@@ -20,9 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/vote"
 	"github.com/ethereum/go-ethereum/cmd/immutable"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient/gethclient"
@@ -116,6 +120,154 @@ func processValidator(c *cli.Context, add bool) error {
 	return nil
 }
 
+// applyValidatorSetCommand votes the validator set of the network towards the
+// desired set, one change at a time, waiting for each change to be reflected
+// by every voter before moving on.
+func applyValidatorSetCommand(c *cli.Context) error {
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	desired, err := vote.ReadValidatorSet(c.String(immutable.ValidatorSetFilepath.Name))
+	if err != nil {
+		return err
+	}
+	voterURLs := c.StringSlice(immutable.Voters.Name)
+	if len(voterURLs) == 0 {
+		return errors.New("no voters provided")
+	}
+	voterClients, err := urlsToClients(ctx, voterURLs)
+	if err != nil {
+		return err
+	}
+	current, voters, err := readVoters(ctx, voterClients)
+	if err != nil {
+		return err
+	}
+	log.Info("Current validator set", "validators", addressesToCSV(current))
+
+	plan, err := vote.ComputePlan(current, desired, voters)
+	fmt.Print(plan)
+	if err != nil {
+		return err
+	}
+	if c.Bool(immutable.DryRun.Name) {
+		return nil
+	}
+	return applyPlan(ctx, voterClients, voters, plan, c.Duration(immutable.VoteTimeout.Name), c.Duration(immutable.VotePollInterval.Name))
+}
+
+// readVoters returns the validator set the voters agree on, along with the
+// signing address of each voter.
+func readVoters(ctx context.Context, clients []*gethclient.Client) ([]common.Address, []common.Address, error) {
+	var (
+		current []common.Address
+		voters  = make([]common.Address, len(clients))
+	)
+	for i := range clients {
+		signers, err := clients[i].GetSigners(ctx, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get signers: %w", err)
+		}
+		if i == 0 {
+			current = signers
+		} else if !sameAddresses(current, signers) {
+			return nil, nil, fmt.Errorf("validator set on voter %d (%s) differs from voter 0 (%s)", i, addressesToCSV(signers), addressesToCSV(current))
+		}
+		if voters[i], err = clients[i].Etherbase(ctx); err != nil {
+			return nil, nil, fmt.Errorf("failed to get etherbase of voter %d: %w", i, err)
+		}
+	}
+	return current, voters, nil
+}
+
+// applyPlan applies the steps of the plan one at a time, waiting for each
+// change to be reflected by every voter before moving on.
+func applyPlan(ctx context.Context, clients []*gethclient.Client, voters []common.Address, plan vote.Plan, timeout, interval time.Duration) error {
+	for i, step := range plan {
+		log.Info("Applying validator set change", "step", i+1, "validator", step.Validator, "add", step.Add, "quorum", step.Quorum)
+		err := applyStep(ctx, clients, voters, step, timeout, interval)
+
+		// Drop the proposal whether or not it passed, a lingering proposal
+		// would otherwise keep voting and could revert a later change.
+		discardProposals(clients, step.Validator)
+		if err != nil {
+			return fmt.Errorf("step %d failed: %w", i+1, err)
+		}
+		log.Info("Validator set change applied", "step", i+1, "validator", step.Validator, "add", step.Add)
+	}
+	return nil
+}
+
+// applyStep casts the step's proposal on every available voter and polls the
+// voters until all of them report the change, the deadline passes or the
+// context is cancelled.
+func applyStep(ctx context.Context, clients []*gethclient.Client, voters []common.Address, step vote.Step, timeout, interval time.Duration) error {
+	for i := range clients {
+		if !slices.Contains(step.Voters, voters[i]) {
+			continue
+		}
+		if err := clients[i].Propose(ctx, step.Validator, step.Add); err != nil {
+			return fmt.Errorf("failed to propose on voter %d: %w", i, err)
+		}
+		log.Info("Proposed validator set change", "voter", i, "signer", voters[i], "validator", step.Validator, "add", step.Add)
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return fmt.Errorf("change not applied within %v", timeout)
+		case <-ticker.C:
+			applied := 0
+			for i := range clients {
+				signers, err := clients[i].GetSigners(ctx, nil)
+				if err != nil {
+					log.Warn("Failed to get signers", "voter", i, "err", err)
+					continue
+				}
+				if step.Applied(signers) {
+					applied++
+				}
+			}
+			log.Info("Waiting for validator set change", "validator", step.Validator, "applied", applied, "voters", len(clients))
+			if applied == len(clients) {
+				return nil
+			}
+		}
+	}
+}
+
+// discardProposals drops any outstanding proposal for the validator on all
+// voters. It uses its own context so that it also runs after an interrupt.
+func discardProposals(clients []*gethclient.Client, validator common.Address) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for i := range clients {
+		if err := clients[i].Discard(ctx, validator); err != nil {
+			log.Error("Failed to discard proposal", "voter", i, "validator", validator, "err", err)
+		}
+	}
+}
+
+// sameAddresses reports whether both slices contain the same addresses.
+func sameAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, addr := range a {
+		if !slices.Contains(b, addr) {
+			return false
+		}
+	}
+	return true
+}
+
 // addressesToCSV converts a slice of common.Address to a string.
 func addressesToCSV(addresses []common.Address) string {
 	strAddresses := make([]string, 0, len(addresses))
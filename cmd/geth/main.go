@@ -70,6 +70,7 @@ var (
 		// CHANGE(immutable): Add fork overrides
 		utils.OverridePrevrandao,
 		utils.OverrideShanghai,
+		utils.OverrideForks,
 		utils.EnablePersonal,
 		utils.TxPoolLocalsFlag,
 		utils.TxPoolNoLocalsFlag,
@@ -84,6 +85,10 @@ var (
 		utils.TxPoolLifetimeFlag,
 		// CHANGE(immutable): added flags to configure tx pool acls
 		utils.TxPoolBlockListFilePaths,
+		utils.TxPoolExecutionAccessControlFlag,
+		utils.TxPoolAuditLogFlag,
+		utils.TxPoolAuditLogMaxSizeFlag,
+		utils.TxPoolAuditLogMaxBackupsFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
 		utils.BlobPoolPriceBumpFlag,
@@ -130,12 +135,17 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNewPayloadTimeout,
+		utils.MinerExecutionAccessControlFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV4Flag,
 		utils.DiscoveryV5Flag,
 		utils.LegacyDiscoveryV5Flag, // deprecated
 		utils.NetrestrictFlag,
+		// CHANGE(immutable): Add flag advertising the node's role in its ENR.
+		utils.ImmutableRoleFlag,
+		// CHANGE(immutable): Add flag for the peer registry prioritizing validators.
+		utils.ImmutablePeerRegistryFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
 		utils.DNSDiscoveryFlag,
@@ -157,8 +167,20 @@ var (
 		utils.ImmutableGossipDefaultFlag,
 		// CHANGE(immutable): Add flag for disabling tx pool gossiping.
 		utils.ImmutableDisableTxPoolGossipFlag,
+		// CHANGE(immutable): Add flags for the per-role gossip policies.
+		utils.ImmutableGossipValidatorsFlag,
+		utils.ImmutableGossipBootFlag,
+		utils.ImmutableGossipRPCFlag,
+		utils.ImmutableGossipPartnersFlag,
+		utils.ImmutableGossipPolicyFlag,
+		utils.ImmutableFetcherCatchUpFlag,
 		// CHANGE(immutable): Add flag for rpc proxy forwarding.
 		utils.ImmutableRPCProxyFlag,
+		utils.ImmutableRPCProxyUpstreamsFlag,
+		utils.ImmutableRPCProxyRetriesFlag,
+		// CHANGE(immutable): Add flag for enforcing access control on imported blocks.
+		utils.ImmutableBlockAccessPolicyFlag,
+		utils.ImmutableBlockAccessPolicyFromFlag,
 	}, utils.NetworkFlags, utils.DatabaseFlags)
 
 	rpcFlags = []cli.Flag{
@@ -190,6 +212,11 @@ var (
 		utils.IsCliqueRPCDisabledFlag,
 		utils.IsMinerRPCDisabledFlag,
 		utils.IsPersonalRPCDisabledFlag,
+		utils.RPCTracingEndpointFlag,
+		utils.RPCTracingFileFlag,
+		utils.RPCTracingSampleRatioFlag,
+		utils.RPCLogsMaxBlockRangeFlag,
+		utils.RPCLogsMaxResultsFlag,
 		utils.IPCPathFlag,
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCapFlag,
@@ -203,6 +230,7 @@ var (
 	metricsFlags = []cli.Flag{
 		utils.MetricsEnabledFlag,
 		utils.MetricsEnabledExpensiveFlag,
+		utils.MetricsSignerHealthWindowFlag,
 		utils.MetricsHTTPFlag,
 		utils.MetricsPortFlag,
 		utils.MetricsEnableInfluxDBFlag,
@@ -17,6 +17,8 @@
 package immutable
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v2"
 )
 
@@ -75,6 +77,42 @@ var (
 		Category: ImmutableCategory,
 		Value:    "us-east-2",
 	}
+	SecretProvider = &cli.StringFlag{
+		Name:     "secrets",
+		Usage:    "URI of the secret provider: aws://<region>, vault://<host>/<mount>, encfile://<dir>, file://<dir> or env://<prefix>. Defaults to AWS Secrets Manager in --region",
+		Category: ImmutableCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_SECRETS"},
+	}
+	Artefacts = &cli.StringFlag{
+		Name:     "artefacts",
+		Usage:    "URI of the artefact store holding bootstrap outputs: file://<dir>, s3://<bucket>/<prefix>?region=<region>, http[s]://<host>/<prefix>, oci://<registry>/<repository> or git+<remote>",
+		Category: ImmutableCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_ARTEFACTS"},
+	}
+	ArtefactKeyFilepath = &cli.StringFlag{
+		Name:     "artefactkey",
+		Usage:    "File holding the hex private key signing published artefact manifests",
+		Category: ImmutableCategory,
+	}
+	ArtefactSigners = &cli.StringSliceFlag{
+		Name:     "artefactsigners",
+		Usage:    "Addresses trusted to sign the artefact manifests fetched from --artefacts",
+		Category: ImmutableCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_ARTEFACT_SIGNERS"},
+	}
+	RegistryFilepath = &cli.StringFlag{
+		Name:     "registry",
+		Usage:    "File holding the signed registry of the nodes allowed to join discovery, managed through the admin RPC on --http.addr, which must be a loopback address, and --http.port. Discovery is open to every node if unset",
+		Category: ImmutableCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_REGISTRY"},
+	}
+	RegistryPublicAddr = &cli.StringFlag{
+		Name:     "registry.addr",
+		Usage:    "Listen address of the public RPC serving the signed registry (admin_registry) to the nodes fetching it with --p2p.registry",
+		Value:    ":8550",
+		Category: ImmutableCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_REGISTRY_ADDR"},
+	}
 	BlockListFilepath = &cli.StringFlag{
 		Name:     "blocklistfilepath",
 		Usage:    "File path to blocklist file",
@@ -123,6 +161,57 @@ var (
 		Category: ImmutableCategory,
 		Required: true,
 	}
+	ValidatorURL = &cli.StringFlag{
+		Name:     "validatorurl",
+		Usage:    "URL of the validator whose key is rotated, exposing the admin and clique APIs",
+		Category: ImmutableCategory,
+		Required: true,
+	}
+	SecretID = &cli.StringFlag{
+		Name:     "secretid",
+		Usage:    "ID of the secret holding the validator's private key",
+		Category: ImmutableCategory,
+		Required: true,
+	}
+	ValidatorSetFilepath = &cli.StringFlag{
+		Name:     "validatorset",
+		Usage:    "YAML file listing the desired validator set",
+		Category: ImmutableCategory,
+		Required: true,
+	}
+	DryRun = &cli.BoolFlag{
+		Name:     "dryrun",
+		Aliases:  []string{"dry-run"},
+		Usage:    "Print the plan without applying it",
+		Category: ImmutableCategory,
+	}
+	Force = &cli.BoolFlag{
+		Name:     "force",
+		Usage:    "Apply the change even if it is deemed unsafe",
+		Category: ImmutableCategory,
+	}
+	Operator = &cli.StringFlag{
+		Name:     "operator",
+		Usage:    "Name of the operator recorded in the history, defaults to $USER",
+		Category: ImmutableCategory,
+	}
+	Reason = &cli.StringFlag{
+		Name:     "reason",
+		Usage:    "Reason for the change recorded in the history",
+		Category: ImmutableCategory,
+	}
+	VoteTimeout = &cli.DurationFlag{
+		Name:     "votetimeout",
+		Usage:    "Maximum time to wait for each validator set change to be applied",
+		Category: ImmutableCategory,
+		Value:    5 * time.Minute,
+	}
+	VotePollInterval = &cli.DurationFlag{
+		Name:     "votepollinterval",
+		Usage:    "Interval at which the validator set is polled while waiting for a change",
+		Category: ImmutableCategory,
+		Value:    2 * time.Second,
+	}
 	PublicKey = &cli.StringFlag{
 		Name:     "pubkey",
 		Usage:    "public key",
@@ -0,0 +1,95 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package artefacts publishes and fetches the configuration artefacts produced
+// by bootstrap, such as the genesis, the config TOML and the boot node enode
+// list, through stores chosen by URI:
+//
+//	file:///<dir>                                      a local directory tree
+//	s3://<bucket>[/<prefix>]?region=<region>           an AWS S3 bucket
+//	http[s]://<host>[/<prefix>]                        an HTTP blob store accepting GET and PUT
+//	oci://<registry>/<repository>                      an OCI distribution registry
+//	git+<remote>[?branch=<branch>&path=<dir>]          a git repository, e.g. git+ssh://git@host/repo.git
+//
+// Artefacts are content addressed: each one is stored as a blob keyed by its
+// SHA-256 digest and referenced by a manifest signed by the publisher, so that
+// consumers only need to trust the signer rather than the store.
+package artefacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Names of the artefacts published by bootstrap
+const (
+	GenesisName = "genesis.json"
+	ConfigName  = "config.toml"
+	EnodesName  = "enodes.txt"
+)
+
+var (
+	// ErrNotFound is returned when an object does not exist in the store.
+	ErrNotFound = errors.New("artefact not found")
+	// ErrInvalidKey is returned when an object key cannot be mapped onto the store.
+	ErrInvalidKey = errors.New("invalid artefact key")
+)
+
+// Store reads and writes objects by key. Keys are slash separated paths.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, content []byte) error
+	Close() error
+}
+
+// Open returns the store configured by the URI.
+func Open(uri string) (Store, error) {
+	// Git remotes are URLs or scp-like addresses prefixed by the scheme
+	if remote, ok := strings.CutPrefix(uri, "git+"); ok {
+		return newGitStore(remote)
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artefact store URI: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		return newFileStore(u.Path)
+	case "s3":
+		return newS3Store(u)
+	case "http", "https":
+		return newHTTPStore(u)
+	case "oci", "oci+http":
+		return newOCIStore(u)
+	default:
+		return nil, fmt.Errorf("unsupported artefact store %q", u.Scheme)
+	}
+}
+
+// splitKey validates the key and returns its path elements. Keys may not
+// escape the root of the store.
+func splitKey(key string) ([]string, error) {
+	elems := strings.Split(key, "/")
+	for _, elem := range elems {
+		if elem == "" || elem == "." || elem == ".." || strings.ContainsRune(elem, '\\') {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return elems, nil
+}
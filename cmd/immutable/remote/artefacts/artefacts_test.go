@@ -0,0 +1,314 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+var testFiles = map[string][]byte{
+	GenesisName: []byte(`{"config":{"chainId":13473}}`),
+	ConfigName:  []byte("[Eth]\nNetworkId = 13473\n"),
+	EnodesName:  []byte("enode://abc@127.0.0.1:30300\n"),
+}
+
+func TestOpen_Errors(t *testing.T) {
+	for _, uri := range []string{
+		"unknown://foo",
+		"file://",
+		"s3://bucket",
+		"https://",
+		"oci://registry",
+		"git+",
+		"git+file:///repo?path=../up",
+	} {
+		_, err := Open(uri)
+		require.Error(t, err, uri)
+	}
+}
+
+// testPublishFetch publishes the test files to the store and fetches them back.
+func testPublishFetch(t *testing.T, store Store) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	published, err := Publish(ctx, store, "devnet", testFiles, key)
+	require.NoError(t, err)
+	require.Len(t, published.Artefacts, len(testFiles))
+
+	manifest, files, err := Fetch(ctx, store, "devnet", []common.Address{{0x01}, signer})
+	require.NoError(t, err)
+	require.Equal(t, testFiles, files)
+	require.Equal(t, published.Created, manifest.Created)
+
+	_, _, err = Fetch(ctx, store, "devnet", []common.Address{{0x01}})
+	require.ErrorIs(t, err, ErrUntrustedSigner)
+	_, _, err = Fetch(ctx, store, "testnet", []common.Address{signer})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Get(ctx, "blobs/../manifests/devnet.json")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := Open("file://" + dir)
+	require.NoError(t, err)
+	testPublishFetch(t, store)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signers := []common.Address{crypto.PubkeyToAddress(key.PublicKey)}
+	manifest, err := Publish(ctx, store, "devnet", testFiles, key)
+	require.NoError(t, err)
+
+	// Artefacts are stored by digest
+	genesis := manifest.Artefacts[slices.IndexFunc(manifest.Artefacts, func(a Artefact) bool { return a.Name == GenesisName })]
+	path := filepath.Join(dir, "blobs", "sha256", strings.TrimPrefix(genesis.Digest, "sha256:"))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, testFiles[GenesisName], content)
+
+	// Tampering with an artefact is detected
+	require.NoError(t, os.WriteFile(path, []byte(`{"config":{"chainId":1}}`), 0644))
+	_, _, err = Fetch(ctx, store, "devnet", signers)
+	require.ErrorIs(t, err, ErrDigestMismatch)
+	require.NoError(t, os.WriteFile(path, testFiles[GenesisName], 0644))
+
+	// Tampering with the manifest invalidates its signature
+	tampered := *manifest
+	tampered.Artefacts = append([]Artefact{}, manifest.Artefacts...)
+	tampered.Artefacts[0].Name = "other.json"
+	enc, err := json.Marshal(&tampered)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, manifestKey("devnet"), enc))
+	_, _, err = Fetch(ctx, store, "devnet", signers)
+	require.ErrorIs(t, err, ErrUntrustedSigner)
+
+	// Manifests cannot be replayed under another name
+	enc, err = json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, manifestKey("mainnet"), enc))
+	_, _, err = Fetch(ctx, store, "mainnet", signers)
+	require.ErrorContains(t, err, "published as")
+
+	_, err = Publish(ctx, store, "../devnet", testFiles, key)
+	require.Error(t, err)
+	_, err = Publish(ctx, store, "devnet", map[string][]byte{"dir/genesis.json": nil}, key)
+	require.Error(t, err)
+	_, _, err = Fetch(ctx, store, "devnet", nil)
+	require.Error(t, err)
+}
+
+func TestHTTPStore(t *testing.T) {
+	var objects sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			content, _ := io.ReadAll(r.Body)
+			objects.Store(r.URL.Path, content)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			content, ok := objects.Load(r.URL.Path)
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(content.([]byte))
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_ARTEFACTS_TOKEN", "")
+	_, err := Open(server.URL + "/immutable?token-env=TEST_ARTEFACTS_TOKEN")
+	require.Error(t, err)
+
+	t.Setenv(tokenEnvVar, "token")
+	store, err := Open(server.URL + "/immutable/")
+	require.NoError(t, err)
+	testPublishFetch(t, store)
+	_, ok := objects.Load("/immutable/manifests/devnet.json")
+	require.True(t, ok)
+
+	t.Setenv(tokenEnvVar, "")
+	store, err = Open(server.URL + "/immutable")
+	require.NoError(t, err)
+	_, err = store.Get(context.Background(), manifestKey("devnet"))
+	require.ErrorContains(t, err, "status 401")
+}
+
+// testRegistry is a minimal OCI distribution registry serving one repository.
+type testRegistry struct {
+	lock      sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+}
+
+func (reg *testRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/v2/org/artefacts/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && path == "blobs/uploads/":
+		reg.uploads++
+		w.Header().Set("Location", "/v2/org/artefacts/blobs/uploads/session?state=1")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && path == "blobs/uploads/session":
+		content, _ := io.ReadAll(r.Body)
+		digest := r.URL.Query().Get("digest")
+		if Digest(content) != digest || r.URL.Query().Get("state") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reg.blobs[digest] = content
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasPrefix(path, "blobs/"):
+		content, ok := reg.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(content)
+		}
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "manifests/"):
+		if r.Header.Get("Content-Type") != ociManifestMediaType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		var manifest ociManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// Registries refuse manifests referencing unknown blobs
+		for _, desc := range append(manifest.Layers, manifest.Config) {
+			if _, ok := reg.blobs[desc.Digest]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		reg.manifests[strings.TrimPrefix(path, "manifests/")] = content
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "manifests/"):
+		content, ok := reg.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ociManifestMediaType)
+		w.Write(content)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestOCIStore(t *testing.T) {
+	reg := &testRegistry{blobs: make(map[string][]byte), manifests: make(map[string][]byte)}
+	server := httptest.NewServer(reg)
+	defer server.Close()
+
+	t.Setenv(tokenEnvVar, "")
+	store, err := Open("oci+http://" + strings.TrimPrefix(server.URL, "http://") + "/org/artefacts")
+	require.NoError(t, err)
+	testPublishFetch(t, store)
+
+	// The tagged manifest references every artefact
+	var manifest ociManifest
+	require.NoError(t, json.Unmarshal(reg.manifests["devnet"], &manifest))
+	require.Equal(t, artefactsArtifactType, manifest.ArtifactType)
+	require.Len(t, manifest.Layers, len(testFiles)+1)
+	for _, layer := range manifest.Layers[1:] {
+		name := layer.Annotations[ociTitleAnnotation]
+		require.Equal(t, Digest(testFiles[name]), layer.Digest)
+	}
+	// Existing blobs are not uploaded again
+	uploads := reg.uploads
+	require.NoError(t, store.Put(context.Background(), blobKey(Digest(testFiles[GenesisName])), testFiles[GenesisName]))
+	require.Equal(t, uploads, reg.uploads)
+	require.ErrorIs(t, store.Put(context.Background(), blobKey(Digest(nil)), []byte("x")), ErrDigestMismatch)
+}
+
+func TestGitStore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "artefacts.git")
+	out, err := exec.Command("git", "init", "-q", "--bare", remote).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	uri := "git+file://" + remote + "?branch=networks&path=immutable/artefacts"
+	store, err := Open(uri)
+	require.NoError(t, err)
+	testPublishFetch(t, store)
+	require.NoError(t, store.Close())
+
+	// A new working copy sees the pushed objects
+	store, err = Open(uri)
+	require.NoError(t, err)
+	defer store.Close()
+	manifest, err := store.Get(ctx, manifestKey("devnet"))
+	require.NoError(t, err)
+	var m Manifest
+	require.NoError(t, json.Unmarshal(manifest, &m))
+	signer, err := m.Signer()
+	require.NoError(t, err)
+	_, files, err := Fetch(ctx, store, "devnet", []common.Address{signer})
+	require.NoError(t, err)
+	require.Equal(t, testFiles, files)
+
+	out, err = exec.Command("git", "--git-dir", remote, "ls-tree", "-r", "--name-only", "networks").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), "immutable/artefacts/manifests/devnet.json")
+
+	// Remotes are only contacted on first use
+	store, err = Open("git+file://" + filepath.Join(t.TempDir(), "missing.git"))
+	require.NoError(t, err)
+	_, err = store.Get(ctx, manifestKey("devnet"))
+	require.ErrorContains(t, err, "git ls-remote failed")
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// gitDefaultBranch is the branch holding the objects unless the URI names
+// another one with the branch query parameter.
+const gitDefaultBranch = "main"
+
+// gitStore keeps objects in a git repository, below the directory named by
+// the path query parameter. Each object written is committed and pushed to the
+// remote. The branch is fetched into a temporary working copy on first use and
+// is not refreshed afterwards.
+type gitStore struct {
+	remote string
+	branch string
+	root   []string // Path elements of the directory holding the objects
+
+	lock sync.Mutex
+	dir  string // Working copy, empty until checked out
+}
+
+func newGitStore(uri string) (*gitStore, error) {
+	remote, rawQuery, _ := strings.Cut(uri, "?")
+	if remote == "" {
+		return nil, errors.New("git artefact store requires a remote, e.g. git+ssh://git@github.com/org/artefacts.git")
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artefact store URI: %w", err)
+	}
+	branch := query.Get("branch")
+	if branch == "" {
+		branch = gitDefaultBranch
+	}
+	var root []string
+	if path := strings.Trim(query.Get("path"), "/"); path != "" {
+		if root, err = splitKey(path); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git artefact store requires git: %w", err)
+	}
+	return &gitStore{
+		remote: remote,
+		branch: branch,
+		root:   root,
+	}, nil
+}
+
+func (s *gitStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path, _, err := s.path(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return content, err
+}
+
+// Put commits the object and pushes the commit to the remote branch.
+func (s *gitStore) Put(ctx context.Context, key string, content []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path, rel, err := s.path(ctx, key)
+	if err != nil {
+		return err
+	}
+	// Content addressed objects are usually published already
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	if _, err := s.git(ctx, "add", "--", rel); err != nil {
+		return err
+	}
+	if _, err := s.git(ctx, "commit", "-q", "-m", "Publish "+key); err != nil {
+		return err
+	}
+	_, err = s.git(ctx, "push", "-q", "origin", "HEAD:refs/heads/"+s.branch)
+	return err
+}
+
+// Close removes the working copy.
+func (s *gitStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.dir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.dir)
+	s.dir = ""
+	return err
+}
+
+// path returns the path of the file holding the object in the working copy
+// and its path relative to the working copy.
+func (s *gitStore) path(ctx context.Context, key string) (string, string, error) {
+	elems, err := splitKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.checkout(ctx); err != nil {
+		return "", "", err
+	}
+	rel := filepath.Join(append(append([]string{}, s.root...), elems...)...)
+	return filepath.Join(s.dir, rel), rel, nil
+}
+
+// checkout fetches the branch into a new working copy, unless it exists. A
+// branch missing from the remote is created by the first push.
+func (s *gitStore) checkout(ctx context.Context) (err error) {
+	if s.dir != "" {
+		return nil
+	}
+	if s.dir, err = os.MkdirTemp("", "geth-artefacts-"); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(s.dir)
+			s.dir = ""
+		}
+	}()
+	if _, err := s.git(ctx, "init", "-q"); err != nil {
+		return err
+	}
+	if _, err := s.git(ctx, "remote", "add", "origin", s.remote); err != nil {
+		return err
+	}
+	heads, err := s.git(ctx, "ls-remote", "--heads", "origin", "refs/heads/"+s.branch)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(heads) == "" {
+		if _, err := s.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+s.branch); err != nil {
+			return err
+		}
+	} else {
+		if _, err := s.git(ctx, "fetch", "-q", "--depth", "1", "origin", s.branch); err != nil {
+			return err
+		}
+		if _, err := s.git(ctx, "checkout", "-q", "-B", s.branch, "FETCH_HEAD"); err != nil {
+			return err
+		}
+	}
+	// Commits need an identity, fall back to a local one if none is configured
+	if _, err := s.git(ctx, "config", "user.email"); err != nil {
+		for _, kv := range [][2]string{{"user.name", "geth"}, {"user.email", "geth@localhost"}} {
+			if _, err := s.git(ctx, "config", kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// git runs a git command in the working copy and returns its output.
+func (s *gitStore) git(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// tokenEnvVar is the environment variable holding the bearer token sent
+	// to HTTP and OCI stores, unless the URI names another one with the
+	// token-env query parameter.
+	tokenEnvVar = "GETH_ARTEFACTS_TOKEN"
+	// httpTimeout bounds every request sent to HTTP and OCI stores.
+	httpTimeout = 30 * time.Second
+	// maxObjectSize bounds the objects read from HTTP and OCI stores.
+	maxObjectSize = 64 * 1024 * 1024
+)
+
+// httpStore keeps objects in a generic HTTP blob store, such as a WebDAV
+// server or an object store gateway, reading them with GET and writing them
+// with PUT below the base URL.
+type httpStore struct {
+	client *http.Client
+	base   string
+	token  string
+}
+
+func newHTTPStore(u *url.URL) (*httpStore, error) {
+	if u.Host == "" {
+		return nil, errors.New("http artefact store requires a host, e.g. https://artefacts.example.com/immutable")
+	}
+	token, err := bearerToken(u)
+	if err != nil {
+		return nil, err
+	}
+	base := *u
+	base.RawQuery, base.Fragment = "", ""
+	return &httpStore{
+		client: &http.Client{Timeout: httpTimeout},
+		base:   strings.TrimRight(base.String(), "/"),
+		token:  token,
+	}, nil
+}
+
+func (s *httpStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if _, err := splitKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.base+"/"+key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err := checkStatus(resp, key); err != nil {
+		return nil, err
+	}
+	return readBody(resp)
+}
+
+func (s *httpStore) Put(ctx context.Context, key string, content []byte) error {
+	if _, err := splitKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.base+"/"+key, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key)
+}
+
+func (s *httpStore) Close() error {
+	return nil
+}
+
+// bearerToken reads the token configured by the URI. It is optional.
+func bearerToken(u *url.URL) (string, error) {
+	envVar := u.Query().Get("token-env")
+	if envVar == "" {
+		return os.Getenv(tokenEnvVar), nil
+	}
+	token := os.Getenv(envVar)
+	if token == "" {
+		return "", fmt.Errorf("artefact store token %s is not set", envVar)
+	}
+	return token, nil
+}
+
+// doHTTP sends the request with the bearer token, if any.
+func doHTTP(client *http.Client, req *http.Request, token string) (*http.Response, error) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("artefact store request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// checkStatus returns an error for unsuccessful responses.
+func checkStatus(resp *http.Response, key string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("artefact store request for %s failed with status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
+
+// readBody reads the response body, refusing oversized objects.
+func readBody(resp *http.Response) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxObjectSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxObjectSize {
+		return nil, fmt.Errorf("artefact exceeds %d bytes", maxObjectSize)
+	}
+	return content, nil
+}
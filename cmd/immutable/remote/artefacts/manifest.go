@@ -0,0 +1,214 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	manifestVersion = 1
+	digestAlgorithm = "sha256"
+)
+
+var (
+	// ErrUntrustedSigner is returned when a manifest is not signed by a trusted signer.
+	ErrUntrustedSigner = errors.New("artefact manifest not signed by a trusted signer")
+	// ErrDigestMismatch is returned when an artefact does not match its digest in the manifest.
+	ErrDigestMismatch = errors.New("artefact does not match its digest")
+
+	// nameRegexp restricts manifest and artefact names to those usable as file
+	// names and as OCI tags.
+	nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+)
+
+// Artefact describes a published artefact.
+type Artefact struct {
+	Name   string `json:"name"`
+	Digest string `json:"digest"`
+	Size   int    `json:"size"`
+}
+
+// Manifest lists the artefacts published together under a name, usually the
+// name of the network they configure. It is signed by the publisher.
+type Manifest struct {
+	Version   int           `json:"version"`
+	Name      string        `json:"name"`
+	Created   time.Time     `json:"created"`
+	Artefacts []Artefact    `json:"artefacts"`
+	Signature hexutil.Bytes `json:"signature,omitempty"`
+}
+
+// SigningHash returns the hash of the manifest covered by its signature.
+func (m *Manifest) SigningHash() (common.Hash, error) {
+	unsigned := *m
+	unsigned.Signature = nil
+	enc, err := json.Marshal(&unsigned)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(enc), nil
+}
+
+// Signer recovers the address that signed the manifest.
+func (m *Manifest) Signer() (common.Address, error) {
+	hash, err := m.SigningHash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.SigToPub(hash[:], m.Signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid artefact manifest signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Digest returns the content address of an artefact.
+func Digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return digestAlgorithm + ":" + hex.EncodeToString(sum[:])
+}
+
+// Publish stores the artefacts by digest and then the manifest listing them
+// under the name, signed with the key. Readers never see a manifest before the
+// artefacts it references.
+func Publish(ctx context.Context, store Store, name string, files map[string][]byte, key *ecdsa.PrivateKey) (*Manifest, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid artefact manifest name %q", name)
+	}
+	names := make([]string, 0, len(files))
+	for artefact := range files {
+		if !nameRegexp.MatchString(artefact) {
+			return nil, fmt.Errorf("invalid artefact name %q", artefact)
+		}
+		names = append(names, artefact)
+	}
+	sort.Strings(names)
+
+	manifest := &Manifest{
+		Version: manifestVersion,
+		Name:    name,
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+	for _, artefact := range names {
+		content := files[artefact]
+		digest := Digest(content)
+		if err := store.Put(ctx, blobKey(digest), content); err != nil {
+			return nil, fmt.Errorf("failed to publish artefact %s: %w", artefact, err)
+		}
+		manifest.Artefacts = append(manifest.Artefacts, Artefact{
+			Name:   artefact,
+			Digest: digest,
+			Size:   len(content),
+		})
+	}
+	hash, err := manifest.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Signature, err = crypto.Sign(hash[:], key); err != nil {
+		return nil, fmt.Errorf("failed to sign artefact manifest: %w", err)
+	}
+	enc, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, manifestKey(name), enc); err != nil {
+		return nil, fmt.Errorf("failed to publish artefact manifest: %w", err)
+	}
+	log.Info("Published artefacts", "name", name, "artefacts", len(names), "signer", crypto.PubkeyToAddress(key.PublicKey))
+	return manifest, nil
+}
+
+// Fetch retrieves the manifest published under the name and the artefacts it
+// lists. The manifest must be signed by one of the signers and every artefact
+// must match its digest.
+func Fetch(ctx context.Context, store Store, name string, signers []common.Address) (*Manifest, map[string][]byte, error) {
+	if len(signers) == 0 {
+		return nil, nil, errors.New("no trusted artefact signers configured")
+	}
+	if !nameRegexp.MatchString(name) {
+		return nil, nil, fmt.Errorf("invalid artefact manifest name %q", name)
+	}
+	enc, err := store.Get(ctx, manifestKey(name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch artefact manifest %s: %w", name, err)
+	}
+	manifest := new(Manifest)
+	if err := json.Unmarshal(enc, manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode artefact manifest %s: %w", name, err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, nil, fmt.Errorf("unsupported artefact manifest version %d", manifest.Version)
+	}
+	// The name is signed, so that a manifest cannot be replayed under another name
+	if manifest.Name != name {
+		return nil, nil, fmt.Errorf("artefact manifest %s is published as %s", manifest.Name, name)
+	}
+	signer, err := manifest.Signer()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(signers, signer) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUntrustedSigner, signer)
+	}
+	files := make(map[string][]byte, len(manifest.Artefacts))
+	for _, artefact := range manifest.Artefacts {
+		if !nameRegexp.MatchString(artefact.Name) {
+			return nil, nil, fmt.Errorf("invalid artefact name %q", artefact.Name)
+		}
+		if !strings.HasPrefix(artefact.Digest, digestAlgorithm+":") {
+			return nil, nil, fmt.Errorf("unsupported digest %q of artefact %s", artefact.Digest, artefact.Name)
+		}
+		content, err := store.Get(ctx, blobKey(artefact.Digest))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch artefact %s: %w", artefact.Name, err)
+		}
+		if len(content) != artefact.Size || Digest(content) != artefact.Digest {
+			return nil, nil, fmt.Errorf("%w: %s", ErrDigestMismatch, artefact.Name)
+		}
+		files[artefact.Name] = content
+	}
+	log.Info("Fetched artefacts", "name", name, "artefacts", len(files), "signer", signer, "created", manifest.Created)
+	return manifest, files, nil
+}
+
+// blobKey returns the key of the artefact with the digest.
+func blobKey(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+// manifestKey returns the key of the manifest with the name.
+func manifestKey(name string) string {
+	return "manifests/" + name + ".json"
+}
@@ -0,0 +1,291 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	ociManifestMediaType  = "application/vnd.oci.image.manifest.v1+json"
+	ociEmptyMediaType     = "application/vnd.oci.empty.v1+json"
+	ociTitleAnnotation    = "org.opencontainers.image.title"
+	artefactsArtifactType = "application/vnd.immutable.artefacts.manifest.v1+json"
+	artefactMediaType     = "application/octet-stream"
+)
+
+// ociEmptyConfig is the config blob of artifact manifests that have no config.
+var ociEmptyConfig = []byte("{}")
+
+// ociDescriptor references a blob of an OCI repository.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int               `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest packaging an artefact manifest.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociStore keeps objects in a repository of an OCI distribution registry.
+// Artefacts are pushed as blobs and each artefact manifest is tagged with its
+// name as an OCI manifest whose first layer is the artefact manifest itself,
+// followed by the artefacts it lists so that the registry retains them.
+type ociStore struct {
+	client     *http.Client
+	registry   string // Scheme and host of the registry
+	repository string
+	token      string
+}
+
+func newOCIStore(u *url.URL) (*ociStore, error) {
+	scheme := "https"
+	if u.Scheme == "oci+http" {
+		scheme = "http"
+	}
+	repository := strings.Trim(u.Path, "/")
+	if u.Host == "" || repository == "" {
+		return nil, errors.New("oci artefact store requires a registry and repository, e.g. oci://ghcr.io/org/artefacts")
+	}
+	token, err := bearerToken(u)
+	if err != nil {
+		return nil, err
+	}
+	return &ociStore{
+		client:     &http.Client{Timeout: httpTimeout},
+		registry:   scheme + "://" + u.Host,
+		repository: repository,
+		token:      token,
+	}, nil
+}
+
+func (s *ociStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if digest, ok := s.blobDigest(key); ok {
+		return s.getBlob(ctx, digest)
+	}
+	if tag, ok := s.manifestTag(key); ok {
+		var manifest ociManifest
+		if err := s.getManifest(ctx, tag, &manifest); err != nil {
+			return nil, err
+		}
+		if manifest.ArtifactType != artefactsArtifactType || len(manifest.Layers) == 0 {
+			return nil, fmt.Errorf("%s:%s is not an artefact manifest", s.repository, tag)
+		}
+		return s.getBlob(ctx, manifest.Layers[0].Digest)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidKey, key)
+}
+
+func (s *ociStore) Put(ctx context.Context, key string, content []byte) error {
+	if digest, ok := s.blobDigest(key); ok {
+		if Digest(content) != digest {
+			return fmt.Errorf("%w: %s", ErrDigestMismatch, key)
+		}
+		return s.putBlob(ctx, content)
+	}
+	tag, ok := s.manifestTag(key)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	// Reference the artefacts from the OCI manifest so they are retained
+	var artefacts Manifest
+	if err := json.Unmarshal(content, &artefacts); err != nil {
+		return fmt.Errorf("failed to decode artefact manifest: %w", err)
+	}
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		ArtifactType:  artefactsArtifactType,
+		Config: ociDescriptor{
+			MediaType: ociEmptyMediaType,
+			Digest:    Digest(ociEmptyConfig),
+			Size:      len(ociEmptyConfig),
+		},
+		Layers: []ociDescriptor{{
+			MediaType: artefactsArtifactType,
+			Digest:    Digest(content),
+			Size:      len(content),
+		}},
+	}
+	for _, artefact := range artefacts.Artefacts {
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   artefactMediaType,
+			Digest:      artefact.Digest,
+			Size:        artefact.Size,
+			Annotations: map[string]string{ociTitleAnnotation: artefact.Name},
+		})
+	}
+	for _, blob := range [][]byte{ociEmptyConfig, content} {
+		if err := s.putBlob(ctx, blob); err != nil {
+			return err
+		}
+	}
+	enc, err := json.Marshal(&manifest)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.endpoint("manifests", tag), bytes.NewReader(enc))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ociManifestMediaType)
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, key)
+}
+
+func (s *ociStore) Close() error {
+	return nil
+}
+
+// blobDigest returns the digest of the blob addressed by the key, if any.
+func (s *ociStore) blobDigest(key string) (string, bool) {
+	elems, err := splitKey(key)
+	if err != nil || len(elems) != 3 || elems[0] != "blobs" {
+		return "", false
+	}
+	return elems[1] + ":" + elems[2], true
+}
+
+// manifestTag returns the tag of the manifest addressed by the key, if any.
+func (s *ociStore) manifestTag(key string) (string, bool) {
+	elems, err := splitKey(key)
+	if err != nil || len(elems) != 2 || elems[0] != "manifests" {
+		return "", false
+	}
+	tag, ok := strings.CutSuffix(elems[1], ".json")
+	return tag, ok && nameRegexp.MatchString(tag)
+}
+
+// endpoint returns the URL of a resource of the repository.
+func (s *ociStore) endpoint(kind, reference string) string {
+	return fmt.Sprintf("%s/v2/%s/%s/%s", s.registry, s.repository, kind, reference)
+}
+
+func (s *ociStore) getBlob(ctx context.Context, digest string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint("blobs", digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, digest)
+	}
+	if err := checkStatus(resp, digest); err != nil {
+		return nil, err
+	}
+	return readBody(resp)
+}
+
+func (s *ociStore) getManifest(ctx context.Context, tag string, manifest *ociManifest) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint("manifests", tag), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", ociManifestMediaType)
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s:%s", ErrNotFound, s.repository, tag)
+	}
+	if err := checkStatus(resp, tag); err != nil {
+		return err
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, manifest); err != nil {
+		return fmt.Errorf("failed to decode manifest %s:%s: %w", s.repository, tag, err)
+	}
+	return nil
+}
+
+// putBlob uploads the blob in a single request unless the registry has it.
+func (s *ociStore) putBlob(ctx context.Context, content []byte) error {
+	digest := Digest(content)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.endpoint("blobs", digest), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	// Open an upload session and complete it with the whole blob
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint("blobs", "uploads/"), nil)
+	if err != nil {
+		return err
+	}
+	resp, err = doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if err := checkStatus(resp, digest); err != nil {
+		return err
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.Header.Get("Location") == "" {
+		return fmt.Errorf("registry returned no upload location for %s", digest)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPut, location.String(), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err = doHTTP(s.client, req, s.token)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkStatus(resp, digest)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package artefacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+)
+
+// s3Store keeps objects in an S3 bucket below an optional prefix.
+type s3Store struct {
+	os     *aws.ObjectStore
+	bucket string
+	prefix string
+}
+
+func newS3Store(u *url.URL) (*s3Store, error) {
+	region := u.Query().Get("region")
+	if u.Host == "" || region == "" {
+		return nil, errors.New("s3 artefact store requires a bucket and region, e.g. s3://bucket/prefix?region=us-east-2")
+	}
+	os, err := aws.NewObjectStore(region)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{
+		os:     os,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if _, err := splitKey(key); err != nil {
+		return nil, err
+	}
+	content, err := s.os.GetObject(ctx, s.bucket, path.Join(s.prefix, key))
+	if aws.IsObjectNotFound(err) {
+		return nil, fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return content, err
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, content []byte) error {
+	if _, err := splitKey(key); err != nil {
+		return err
+	}
+	return s.os.PutObject(s.bucket, path.Join(s.prefix, key), content)
+}
+
+func (s *s3Store) Close() error {
+	return nil
+}
@@ -18,9 +18,13 @@ package aws
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/ethereum/go-ethereum/log"
@@ -50,3 +54,24 @@ func (os ObjectStore) PutObject(bucket, key string, content []byte) error {
 	}
 	return nil
 }
+
+func (os ObjectStore) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	out, err := os.os.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// IsObjectNotFound returns true if the error reports a missing S3 object.
+func IsObjectNotFound(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	return awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound"
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	secretGetMeter      = metrics.NewRegisteredMeter("immutable/secrets/get", nil)
+	secretCacheHitMeter = metrics.NewRegisteredMeter("immutable/secrets/get/cachehit", nil)
+	secretErrorMeter    = metrics.NewRegisteredMeter("immutable/secrets/get/error", nil)
+	secretPutMeter      = metrics.NewRegisteredMeter("immutable/secrets/put", nil)
+)
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context making retrievals bypass the cache, e.g. to
+// pick up a secret rotated by another process. The retrieved secret replaces
+// the cached one.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+type cacheEntry struct {
+	secret  string
+	expires time.Time
+}
+
+// auditedCache wraps a provider, logging every retrieval of a secret from the
+// backend and caching retrieved secrets for a limited time. Cache hits are
+// only logged at debug level. Secret values are never logged.
+type auditedCache struct {
+	backend  Provider
+	provider string
+	ttl      time.Duration
+
+	entries map[string]cacheEntry
+	lock    sync.Mutex
+	now     func() time.Time // Overridden in tests
+}
+
+func newAuditedCache(backend Provider, provider string, ttl time.Duration) *auditedCache {
+	return &auditedCache{
+		backend:  backend,
+		provider: provider,
+		ttl:      ttl,
+		entries:  make(map[string]cacheEntry),
+		now:      time.Now,
+	}
+}
+
+// GetSecret returns the cached secret if it has not expired, retrieving it
+// from the backend otherwise.
+func (c *auditedCache) GetSecret(ctx context.Context, id string) (string, error) {
+	secretGetMeter.Mark(1)
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+
+	c.lock.Lock()
+	entry, ok := c.entries[id]
+	c.lock.Unlock()
+	expired := ok && !c.now().Before(entry.expires)
+	if ok && !bypass && !expired {
+		secretCacheHitMeter.Mark(1)
+		log.Debug("Secret cache hit", "provider", c.provider, "id", id)
+		return entry.secret, nil
+	}
+	reason := "miss"
+	switch {
+	case bypass:
+		reason = "bypass"
+	case expired:
+		reason = "expired"
+	}
+	secret, err := c.backend.GetSecret(ctx, id)
+	if err != nil {
+		secretErrorMeter.Mark(1)
+		log.Warn("Secret fetch failed", "provider", c.provider, "id", id, "reason", reason, "err", err)
+		return "", err
+	}
+	log.Info("Secret fetched", "provider", c.provider, "id", id, "reason", reason)
+	c.store(id, secret)
+	return secret, nil
+}
+
+// PutSecretString writes the secret to the backend and caches it.
+func (c *auditedCache) PutSecretString(id, content string) error {
+	secretPutMeter.Mark(1)
+	if err := c.backend.PutSecretString(id, content); err != nil {
+		log.Warn("Secret update failed", "provider", c.provider, "id", id, "err", err)
+		return err
+	}
+	log.Info("Secret updated", "provider", c.provider, "id", id)
+	c.store(id, content)
+	return nil
+}
+
+func (c *auditedCache) store(id, secret string) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries[id] = cacheEntry{secret: secret, expires: c.now().Add(c.ttl)}
+}
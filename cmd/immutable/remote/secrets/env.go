@@ -0,0 +1,67 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envProvider reads secrets injected into the process environment, e.g. by a
+// container orchestrator. The variable holding a secret is named by the
+// provider's prefix followed by the ID in upper case, with every character
+// other than letters and digits replaced by an underscore.
+type envProvider struct {
+	prefix string
+}
+
+func newEnvProvider(prefix string) *envProvider {
+	return &envProvider{prefix: prefix}
+}
+
+// envVarName returns the environment variable holding the secret
+func (p *envProvider) envVarName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, id)
+	return p.prefix + name
+}
+
+func (p *envProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	secret, ok := os.LookupEnv(p.envVarName(id))
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not set", ErrNotFound, p.envVarName(id))
+	}
+	return secret, nil
+}
+
+// PutSecretString fails as injected secrets cannot be persisted.
+func (p *envProvider) PutSecretString(id, content string) error {
+	return fmt.Errorf("%w: %s must be injected into the environment", ErrReadOnly, p.envVarName(id))
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// passphraseEnvVar is the environment variable holding the passphrase of
+	// encrypted secret files, unless the URI names another one with the
+	// passphrase-env query parameter or a file with passphrase-file.
+	passphraseEnvVar = "GETH_SECRETS_PASSPHRASE"
+
+	encryptedFileVersion = 1
+	scryptR              = 8
+	scryptKeyLen         = 32
+
+	// Bounds of the scrypt parameters accepted from encrypted secret files,
+	// so that a tampered file cannot make key derivation arbitrarily weak or
+	// expensive. They cover the light and standard keystore parameters.
+	minScryptN = keystore.LightScryptN
+	maxScryptN = keystore.StandardScryptN
+	maxScryptP = keystore.LightScryptP
+)
+
+// fileProvider stores each secret in a plaintext file named by its ID below
+// a directory. It is intended for local development only.
+type fileProvider struct {
+	dir string
+}
+
+func newFileProvider(dir string) (*fileProvider, error) {
+	if dir == "" {
+		return nil, errors.New("file secret provider requires a directory, e.g. file:///var/secrets")
+	}
+	return &fileProvider{dir: dir}, nil
+}
+
+func (p *fileProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	content, err := readSecretFile(p.dir, id)
+	return string(content), err
+}
+
+func (p *fileProvider) PutSecretString(id, content string) error {
+	return writeSecretFile(p.dir, id, []byte(content))
+}
+
+// encryptedFileProvider stores each secret in a file named by its ID below a
+// directory, encrypted with AES-GCM under a key derived from a passphrase with
+// scrypt. The ID is authenticated along with the secret, so that encrypted
+// files cannot be swapped.
+type encryptedFileProvider struct {
+	dir        string
+	passphrase []byte
+	scryptN    int
+	scryptP    int
+}
+
+// encryptedFile is the format of encrypted secret files
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func newEncryptedFileProvider(u *url.URL) (*encryptedFileProvider, error) {
+	if u.Path == "" {
+		return nil, errors.New("encrypted file secret provider requires a directory, e.g. encfile:///var/secrets")
+	}
+	query := u.Query()
+	var passphrase string
+	if file := query.Get("passphrase-file"); file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase = strings.TrimRight(string(content), "\r\n")
+	} else {
+		envVar := query.Get("passphrase-env")
+		if envVar == "" {
+			envVar = passphraseEnvVar
+		}
+		passphrase = os.Getenv(envVar)
+	}
+	if passphrase == "" {
+		return nil, errors.New("encrypted file secret provider requires a passphrase")
+	}
+	p := &encryptedFileProvider{
+		dir:        u.Path,
+		passphrase: []byte(passphrase),
+		scryptN:    keystore.StandardScryptN,
+		scryptP:    keystore.StandardScryptP,
+	}
+	// Lightweight key derivation is only meant for tests and local networks
+	if query.Get("scrypt") == "light" {
+		p.scryptN, p.scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	return p, nil
+}
+
+func (p *encryptedFileProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	content, err := readSecretFile(p.dir, id)
+	if err != nil {
+		return "", err
+	}
+	var file encryptedFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return "", fmt.Errorf("failed to decode encrypted secret %s: %w", id, err)
+	}
+	if file.Version != encryptedFileVersion || file.KDF != "scrypt" {
+		return "", fmt.Errorf("unsupported encrypted secret %s: version %d, kdf %q", id, file.Version, file.KDF)
+	}
+	if file.N < minScryptN || file.N > maxScryptN || file.R != scryptR || file.P < 1 || file.P > maxScryptP {
+		return "", fmt.Errorf("unsupported encrypted secret %s: scrypt parameters n=%d, r=%d, p=%d", id, file.N, file.R, file.P)
+	}
+	aead, err := p.cipher(file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s, wrong passphrase or tampered file", id)
+	}
+	return string(plaintext), nil
+}
+
+func (p *encryptedFileProvider) PutSecretString(id, content string) error {
+	file := encryptedFile{
+		Version: encryptedFileVersion,
+		KDF:     "scrypt",
+		N:       p.scryptN,
+		R:       scryptR,
+		P:       p.scryptP,
+		Salt:    make([]byte, 32),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return err
+	}
+	aead, err := p.cipher(file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, []byte(content), []byte(id))
+
+	enc, err := json.Marshal(&file)
+	if err != nil {
+		return err
+	}
+	return writeSecretFile(p.dir, id, enc)
+}
+
+// cipher derives the encryption key from the passphrase.
+func (p *encryptedFileProvider) cipher(salt []byte, n, r, pp int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(p.passphrase, salt, n, r, pp, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive secret key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// secretPath returns the path of the file holding the secret. IDs may contain
+// slashes to nest secrets in directories, but may not escape the directory.
+func secretPath(dir, id string) (string, error) {
+	if id == "" || filepath.IsAbs(id) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(id), "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidID, id)
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(id)), nil
+}
+
+func readSecretFile(dir, id string) ([]byte, error) {
+	path, err := secretPath(dir, id)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return content, err
+}
+
+// writeSecretFile replaces the secret file atomically, readable by the owner only.
+func writeSecretFile(dir, id string, content []byte) error {
+	path, err := secretPath(dir, id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package secrets provides access to the secrets of Immutable nodes, such as
+// their private keys, through providers chosen by URI:
+//
+//	aws://<region>                                AWS Secrets Manager
+//	vault://<host>[:port]/<mount>                 HashiCorp Vault KV v2 compatible API over HTTPS
+//	vault+http://<host>[:port]/<mount>            the same over plain HTTP
+//	file:///<dir>                                 plaintext files, for local development only
+//	encfile:///<dir>                              scrypt and AES-GCM encrypted files
+//	env://[<prefix>]                              environment variables injected into the process
+//
+// Every provider is wrapped so that secret retrievals are audited and cached.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/immutable/remote/aws"
+)
+
+// DefaultCacheTTL is how long retrieved secrets are cached unless the URI
+// configures otherwise with the cache query parameter, e.g. cache=0 to disable it.
+const DefaultCacheTTL = time.Minute
+
+var (
+	// ErrNotFound is returned when a secret does not exist.
+	ErrNotFound = errors.New("secret not found")
+	// ErrReadOnly is returned when a secret is written to a read-only provider.
+	ErrReadOnly = errors.New("secret provider is read-only")
+	// ErrInvalidID is returned when a secret ID cannot be mapped onto the provider.
+	ErrInvalidID = errors.New("invalid secret ID")
+)
+
+// Provider retrieves and stores secrets by ID.
+type Provider interface {
+	GetSecret(ctx context.Context, id string) (string, error)
+	PutSecretString(id, content string) error
+}
+
+// IsNotFound returns true if the error reports a missing secret.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || aws.IsNotFound(err)
+}
+
+// Open returns the provider configured by the URI, audited and cached.
+func Open(uri string) (Provider, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret provider URI: %w", err)
+	}
+	ttl := DefaultCacheTTL
+	if cache := u.Query().Get("cache"); cache != "" {
+		if ttl, err = time.ParseDuration(cache); err != nil {
+			return nil, fmt.Errorf("invalid secret cache duration %q: %w", cache, err)
+		}
+	}
+	var backend Provider
+	switch u.Scheme {
+	case "aws":
+		if u.Host == "" {
+			return nil, errors.New("aws secret provider requires a region, e.g. aws://us-east-2")
+		}
+		backend, err = newAWSProvider(u.Host)
+	case "vault", "vault+http":
+		backend, err = newVaultProvider(u)
+	case "file":
+		backend, err = newFileProvider(u.Path)
+	case "encfile":
+		backend, err = newEncryptedFileProvider(u)
+	case "env":
+		backend = newEnvProvider(u.Host)
+	default:
+		return nil, fmt.Errorf("unsupported secret provider %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newAuditedCache(backend, u.Scheme, ttl), nil
+}
+
+// awsProvider adapts AWS Secrets Manager, reporting missing secrets as ErrNotFound.
+type awsProvider struct {
+	sm *aws.SecretsManager
+}
+
+func newAWSProvider(region string) (*awsProvider, error) {
+	sm, err := aws.NewSecretsManager(region)
+	if err != nil {
+		return nil, err
+	}
+	return &awsProvider{sm: sm}, nil
+}
+
+func (p *awsProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	secret, err := p.sm.GetSecret(ctx, id)
+	if aws.IsNotFound(err) {
+		return "", fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return secret, err
+}
+
+func (p *awsProvider) PutSecretString(id, content string) error {
+	return p.sm.PutSecretString(id, content)
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_Errors(t *testing.T) {
+	t.Setenv(vaultTokenEnvVar, "")
+	t.Setenv(passphraseEnvVar, "")
+	for _, uri := range []string{
+		"unknown://foo",
+		"aws://",
+		"vault://vault:8200/secret",
+		"vault://vault:8200",
+		"file://",
+		"encfile:///tmp/secrets",
+		"env://?cache=soon",
+	} {
+		_, err := Open(uri)
+		require.Error(t, err, uri)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Open("file://" + dir)
+	require.NoError(t, err)
+
+	_, err = p.GetSecret(context.Background(), "validator/key")
+	require.True(t, IsNotFound(err))
+
+	require.NoError(t, p.PutSecretString("validator/key", "secret"))
+	secret, err := p.GetSecret(context.Background(), "validator/key")
+	require.NoError(t, err)
+	require.Equal(t, "secret", secret)
+
+	info, err := os.Stat(filepath.Join(dir, "validator", "key"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	for _, id := range []string{"", "../key", "validator/../../key", "/etc/passwd"} {
+		require.ErrorIs(t, p.PutSecretString(id, "secret"), ErrInvalidID, id)
+	}
+}
+
+func TestEncryptedFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(passphraseEnvVar, "correct horse")
+	p, err := Open("encfile://" + dir + "?scrypt=light&cache=0")
+	require.NoError(t, err)
+
+	require.NoError(t, p.PutSecretString("boot", "boot-secret"))
+	require.NoError(t, p.PutSecretString("validator", "validator-secret"))
+	secret, err := p.GetSecret(context.Background(), "validator")
+	require.NoError(t, err)
+	require.Equal(t, "validator-secret", secret)
+
+	// Secrets are not stored in plaintext
+	content, err := os.ReadFile(filepath.Join(dir, "validator"))
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "validator-secret")
+
+	// Files cannot be swapped between IDs
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "validator"), mustReadFile(t, filepath.Join(dir, "boot")), 0600))
+	_, err = p.GetSecret(context.Background(), "validator")
+	require.Error(t, err)
+
+	// The passphrase must match
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	require.NoError(t, os.WriteFile(passphraseFile, []byte("wrong\n"), 0600))
+	wrong, err := Open("encfile://" + dir + "?passphrase-file=" + passphraseFile)
+	require.NoError(t, err)
+	_, err = wrong.GetSecret(context.Background(), "boot")
+	require.Error(t, err)
+
+	// Scrypt parameters outside the allowed range are rejected before key derivation
+	var file encryptedFile
+	require.NoError(t, json.Unmarshal(mustReadFile(t, filepath.Join(dir, "boot")), &file))
+	for _, tamper := range []func(*encryptedFile){
+		func(f *encryptedFile) { f.N = 1 << 30 },
+		func(f *encryptedFile) { f.N = 2 },
+		func(f *encryptedFile) { f.R = 1 << 20 },
+		func(f *encryptedFile) { f.P = 1 << 20 },
+		func(f *encryptedFile) { f.P = 0 },
+	} {
+		tampered := file
+		tamper(&tampered)
+		enc, err := json.Marshal(&tampered)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "tampered"), enc, 0600))
+		_, err = p.GetSecret(context.Background(), "tampered")
+		require.ErrorContains(t, err, "scrypt parameters")
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("IMX_GETH_VALIDATOR_0_KEY", "secret")
+	p, err := Open("env://IMX_")
+	require.NoError(t, err)
+
+	secret, err := p.GetSecret(context.Background(), "geth/validator-0/key")
+	require.NoError(t, err)
+	require.Equal(t, "secret", secret)
+
+	_, err = p.GetSecret(context.Background(), "geth/validator-1/key")
+	require.True(t, IsNotFound(err))
+	require.ErrorIs(t, p.PutSecretString("geth/validator-0/key", "other"), ErrReadOnly)
+}
+
+// mockVault serves the subset of the Vault KV v2 API used by the provider.
+type mockVault struct {
+	token   string
+	entries map[string]map[string]interface{}
+	lock    sync.Mutex
+}
+
+func (v *mockVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != v.token {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+		return
+	}
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/secret/data/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := v.entries[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": data, "metadata": map[string]interface{}{"version": 1}},
+		})
+	case http.MethodPost, http.MethodPut:
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.entries[path] = body.Data
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"version": 1}})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	vault := &mockVault{token: "root", entries: make(map[string]map[string]interface{})}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	t.Setenv(vaultTokenEnvVar, "root")
+	uri := "vault+http://" + strings.TrimPrefix(server.URL, "http://") + "/secret?cache=0"
+	p, err := Open(uri)
+	require.NoError(t, err)
+
+	_, err = p.GetSecret(context.Background(), "geth/validator-0")
+	require.True(t, IsNotFound(err))
+
+	require.NoError(t, p.PutSecretString("geth/validator-0", "secret"))
+	require.Equal(t, map[string]interface{}{"value": "secret"}, vault.entries["geth/validator-0"])
+	secret, err := p.GetSecret(context.Background(), "geth/validator-0")
+	require.NoError(t, err)
+	require.Equal(t, "secret", secret)
+
+	// Secrets can be kept in another field
+	vault.entries["geth/boot-0"] = map[string]interface{}{"key": "boot-secret"}
+	custom, err := Open(strings.Replace(uri, "?", "?field=key&", 1))
+	require.NoError(t, err)
+	secret, err = custom.GetSecret(context.Background(), "geth/boot-0")
+	require.NoError(t, err)
+	require.Equal(t, "boot-secret", secret)
+	_, err = p.GetSecret(context.Background(), "geth/boot-0")
+	require.True(t, IsNotFound(err))
+
+	// Requests are authenticated with the token
+	t.Setenv("OTHER_TOKEN", "wrong")
+	unauthorized, err := Open(strings.Replace(uri, "?", "?token-env=OTHER_TOKEN&", 1))
+	require.NoError(t, err)
+	_, err = unauthorized.GetSecret(context.Background(), "geth/validator-0")
+	require.ErrorContains(t, err, "permission denied")
+}
+
+// countingProvider counts the retrievals reaching it
+type countingProvider struct {
+	secrets map[string]string
+	gets    int
+}
+
+func (p *countingProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	p.gets++
+	secret, ok := p.secrets[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (p *countingProvider) PutSecretString(id, content string) error {
+	p.secrets[id] = content
+	return nil
+}
+
+func TestAuditedCache(t *testing.T) {
+	backend := &countingProvider{secrets: map[string]string{"key": "secret"}}
+	cache := newAuditedCache(backend, "test", time.Minute)
+	now := time.Unix(0, 0)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		secret, err := cache.GetSecret(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, "secret", secret)
+	}
+	require.Equal(t, 1, backend.gets)
+
+	// Errors are not cached
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetSecret(ctx, "missing")
+		require.True(t, errors.Is(err, ErrNotFound))
+	}
+	require.Equal(t, 3, backend.gets)
+
+	// Secrets rotated elsewhere are picked up once expired or when bypassing
+	// the cache, while secrets written through the cache are cached
+	backend.secrets["key"] = "rotated"
+	secret, _ := cache.GetSecret(ctx, "key")
+	require.Equal(t, "secret", secret)
+	secret, _ = cache.GetSecret(WithoutCache(ctx), "key")
+	require.Equal(t, "rotated", secret)
+
+	require.NoError(t, cache.PutSecretString("key", "updated"))
+	secret, _ = cache.GetSecret(ctx, "key")
+	require.Equal(t, "updated", secret)
+	require.Equal(t, 4, backend.gets)
+
+	now = now.Add(time.Minute)
+	_, _ = cache.GetSecret(ctx, "key")
+	require.Equal(t, 5, backend.gets)
+}
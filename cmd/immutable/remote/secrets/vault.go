@@ -0,0 +1,172 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// vaultTokenEnvVar is the environment variable holding the Vault token,
+	// unless the URI names another one with the token-env query parameter.
+	vaultTokenEnvVar = "VAULT_TOKEN"
+	// vaultDefaultField is the field of the KV entry holding the secret,
+	// unless the URI names another one with the field query parameter.
+	vaultDefaultField = "value"
+	// vaultTimeout bounds every request sent to Vault.
+	vaultTimeout = 10 * time.Second
+)
+
+// vaultProvider accesses secrets through the HTTP API of a HashiCorp Vault KV
+// version 2 secrets engine. Each secret is a field of the KV entry at its ID.
+type vaultProvider struct {
+	client    *http.Client
+	address   string // Scheme and host of the Vault server
+	mount     string // Mount path of the KV secrets engine
+	field     string
+	token     string
+	namespace string
+}
+
+func newVaultProvider(u *url.URL) (*vaultProvider, error) {
+	scheme := "https"
+	if u.Scheme == "vault+http" {
+		scheme = "http"
+	}
+	mount := strings.Trim(u.Path, "/")
+	if u.Host == "" || mount == "" {
+		return nil, errors.New("vault secret provider requires a host and mount path, e.g. vault://vault:8200/secret")
+	}
+	query := u.Query()
+	tokenEnvVar := query.Get("token-env")
+	if tokenEnvVar == "" {
+		tokenEnvVar = vaultTokenEnvVar
+	}
+	token := os.Getenv(tokenEnvVar)
+	if token == "" {
+		return nil, fmt.Errorf("vault secret provider requires a token in %s", tokenEnvVar)
+	}
+	field := query.Get("field")
+	if field == "" {
+		field = vaultDefaultField
+	}
+	return &vaultProvider{
+		client:    &http.Client{Timeout: vaultTimeout},
+		address:   scheme + "://" + u.Host,
+		mount:     mount,
+		field:     field,
+		token:     token,
+		namespace: query.Get("namespace"),
+	}, nil
+}
+
+// vaultResponse is the body of KV v2 read responses and of error responses.
+type vaultResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// GetSecret reads the latest version of the KV entry at the ID.
+func (p *vaultProvider) GetSecret(ctx context.Context, id string) (string, error) {
+	var resp vaultResponse
+	status, err := p.do(ctx, http.MethodGet, id, nil, &resp)
+	if status == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return "", err
+	}
+	// Deleted versions are returned without data
+	value, ok := resp.Data.Data[p.field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s has no field %q", ErrNotFound, id, p.field)
+	}
+	secret, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q of secret %s is not a string", p.field, id)
+	}
+	return secret, nil
+}
+
+// PutSecretString writes a new version of the KV entry at the ID.
+func (p *vaultProvider) PutSecretString(id, content string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+
+	body := map[string]interface{}{
+		"data": map[string]string{p.field: content},
+	}
+	_, err := p.do(ctx, http.MethodPost, id, body, nil)
+	return err
+}
+
+// do sends a request for the KV entry at the ID and decodes the response into
+// result, returning the status code.
+func (p *vaultProvider) do(ctx context.Context, method, id string, body, result interface{}) (int, error) {
+	if id == "" || strings.Contains(id, "..") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		enc, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reqBody = bytes.NewReader(enc)
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", p.address, p.mount, strings.TrimLeft(id, "/"))
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp vaultResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		return resp.StatusCode, fmt.Errorf("vault request for %s failed with status %d: %s", id, resp.StatusCode, strings.Join(errResp.Errors, "; "))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode vault response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -36,9 +36,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
@@ -177,6 +179,51 @@ var (
 		Category: flags.EthCategory,
 		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_DISABLETXPOOLGOSSIP"},
 	}
+	// CHANGE(immutable): Add flags for the per-role transaction gossip policies.
+	ImmutableGossipValidatorsFlag = &cli.StringSliceFlag{
+		Name:     "gossip.validators",
+		Usage:    "Enode URLs or IDs of the peers gossiped to as validators",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_GOSSIP_VALIDATORS"},
+	}
+	ImmutableGossipBootFlag = &cli.StringSliceFlag{
+		Name:     "gossip.boot",
+		Usage:    "Enode URLs or IDs of the peers gossiped to as boot nodes",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_GOSSIP_BOOT"},
+	}
+	ImmutableGossipRPCFlag = &cli.StringSliceFlag{
+		Name:     "gossip.rpc",
+		Usage:    "Enode URLs or IDs of the peers gossiped to as RPC nodes",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_GOSSIP_RPC"},
+	}
+	ImmutableGossipPartnersFlag = &cli.StringSliceFlag{
+		Name:     "gossip.partners",
+		Usage:    "Enode URLs or IDs of the peers gossiped to as partners",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_GOSSIP_PARTNERS"},
+	}
+	ImmutableGossipPolicyFlag = &cli.StringSliceFlag{
+		Name:     "gossip.policy",
+		Usage:    "Transaction gossip policies as role=mode, with roles validator, boot, rpc, partner, inbound, default and modes full, sqrt, announce, none",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_GOSSIP_POLICY"},
+	}
+	// CHANGE(immutable): Add flag for the block fetcher catch-up mode.
+	ImmutableFetcherCatchUpFlag = &cli.StringFlag{
+		Name:     "fetcher.catchup",
+		Usage:    "Block fetcher catch-up mode, queueing blocks regardless of their distance from the head (off, auto, on)",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_FETCHER_CATCHUP"},
+	}
+	// CHANGE(immutable): Add flag for the peer registry prioritizing validators.
+	ImmutablePeerRegistryFlag = &cli.StringFlag{
+		Name:     "p2p.registry",
+		Usage:    "URL of the boot node's public registry RPC (--registry.addr). Validators of the registry, which must be signed by a boot node, bypass the peer limit and receive every propagated block",
+		Category: flags.NetworkingCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_PEER_REGISTRY"},
+	}
 	// CHANGE(immutable): Add flag to forward to the Immutable RPC.
 	ImmutableRPCProxyFlag = &cli.BoolFlag{
 		Name:     "rpcproxy",
@@ -184,6 +231,31 @@ var (
 		Category: flags.EthCategory,
 		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_RPCPROXY"},
 	}
+	ImmutableRPCProxyUpstreamsFlag = &cli.StringSliceFlag{
+		Name:     "rpcproxy.upstreams",
+		Usage:    "Upstream RPCs transactions are forwarded to, as URL[;weight=N][;name=NAME] (defaults to the Immutable RPC of the network)",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_RPCPROXY_UPSTREAMS"},
+	}
+	ImmutableRPCProxyRetriesFlag = &cli.IntFlag{
+		Name:     "rpcproxy.retries",
+		Usage:    "Number of other upstreams a forwarded call is retried on after a transport error",
+		Value:    ethconfig.Defaults.RPCProxy.Retries,
+		Category: flags.EthCategory,
+	}
+	// CHANGE(immutable): Add flag to enforce the txpool access-control lists on imported blocks.
+	ImmutableBlockAccessPolicyFlag = &cli.StringFlag{
+		Name:     "blockaccesspolicy",
+		Usage:    "Check the transactions of imported blocks against the txpool blocklists and either \"log\" or \"reject\" violating blocks",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_BLOCKACCESSPOLICY"},
+	}
+	ImmutableBlockAccessPolicyFromFlag = &cli.Uint64Flag{
+		Name:     "blockaccesspolicy.from",
+		Usage:    "First block number checked by --blockaccesspolicy (default: blocks sealed after the node started)",
+		Category: flags.EthCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_BLOCKACCESSPOLICY_FROM"},
+	}
 	// Dev mode
 	DeveloperFlag = &cli.BoolFlag{
 		Name:     "dev",
@@ -292,6 +364,11 @@ var (
 		Usage:    "Manually specify the Shanghai fork timestamp. Intended for testing only, use --zkevm.* instead",
 		Category: flags.EthCategory,
 	}
+	OverrideForks = &cli.StringFlag{
+		Name:     "override.forks",
+		Usage:    "Fork schedule YAML file overriding the fork timestamps. Intended for testing only, use --zkevm.* instead",
+		Category: flags.EthCategory,
+	}
 	SyncModeFlag = &flags.TextMarshalerFlag{
 		Name:     "syncmode",
 		Usage:    `Blockchain sync mode ("snap" or "full")`,
@@ -389,10 +466,34 @@ var (
 	// CHANGE(immutable): allow the setting of ACLs block/allow lists for txpool
 	TxPoolBlockListFilePaths = &cli.StringSliceFlag{
 		Name:     "txpool.blocklistfilepaths",
-		Usage:    "TxPoolBlockListFilePaths allows you to specify one or more file paths containing blocklists for the transaction pool",
+		Usage:    "TxPoolBlockListFilePaths allows you to specify one or more file paths or URIs (file://, contract://0x.., http(s)://) containing blocklists for the transaction pool",
 		Category: flags.TxPoolCategory,
 		Value:    &cli.StringSlice{},
 	}
+	// CHANGE(immutable): allow auditing the transactions dropped by the txpool ACLs
+	TxPoolAuditLogFlag = &cli.StringFlag{
+		Name:     "txpool.auditlog",
+		Usage:    "JSONL file to record the transactions dropped by the txpool blocklists in (relative to datadir, disabled if empty)",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAuditLogMaxSizeFlag = &cli.IntFlag{
+		Name:     "txpool.auditlog.maxsize",
+		Usage:    "Size in megabytes after which the txpool audit log is rotated",
+		Value:    ethconfig.Defaults.TxPool.AuditLogMaxSize,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAuditLogMaxBackupsFlag = &cli.IntFlag{
+		Name:     "txpool.auditlog.maxbackups",
+		Usage:    "Number of rotated txpool audit log files to keep (0 = keep all)",
+		Value:    ethconfig.Defaults.TxPool.AuditLogMaxBackups,
+		Category: flags.TxPoolCategory,
+	}
+	// CHANGE(immutable): allow checking the accounts reached during execution against the txpool ACLs
+	TxPoolExecutionAccessControlFlag = &cli.BoolFlag{
+		Name:     "txpool.executionaccesscontrol",
+		Usage:    "Simulate incoming transactions and reject the ones whose internal calls reach a blocklisted address",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -519,6 +620,12 @@ var (
 		Value:    ethconfig.Defaults.Miner.NewPayloadTimeout,
 		Category: flags.MinerCategory,
 	}
+	// CHANGE(immutable): allow skipping sealed transactions whose internal calls reach a blocklisted address
+	MinerExecutionAccessControlFlag = &cli.BoolFlag{
+		Name:     "miner.executionaccesscontrol",
+		Usage:    "Trace sealed transactions and skip the ones whose internal calls reach an address blocklisted by the txpool",
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -632,6 +739,35 @@ var (
 		Value:    node.DefaultConfig.DisablePersonal,
 		Category: flags.APICategory,
 	}
+	// CHANGE(immutable): add flags to export OpenTelemetry traces of the RPC stack
+	RPCTracingEndpointFlag = &cli.StringFlag{
+		Name:     "rpc.tracing.endpoint",
+		Usage:    "OTLP/HTTP collector to export RPC traces to (host:port or URL, e.g. http://localhost:4318)",
+		Category: flags.APICategory,
+	}
+	RPCTracingFileFlag = &cli.StringFlag{
+		Name:     "rpc.tracing.file",
+		Usage:    "File to append RPC traces to as JSON",
+		Category: flags.APICategory,
+	}
+	RPCTracingSampleRatioFlag = &cli.Float64Flag{
+		Name:     "rpc.tracing.sampleratio",
+		Usage:    "Fraction of RPC traces sampled when the caller did not send a sampled traceparent",
+		Value:    1,
+		Category: flags.APICategory,
+	}
+	// CHANGE(immutable): add flags to limit eth_getLogs queries
+	RPCLogsMaxBlockRangeFlag = &cli.Int64Flag{
+		Name:     "rpc.logs.maxblockrange",
+		Usage:    "Maximum block range of eth_getLogs queries, and of each eth_getLogsPaged page",
+		Value:    ethconfig.Defaults.FilterMaxBlockRange,
+		Category: flags.APICategory,
+	}
+	RPCLogsMaxResultsFlag = &cli.IntFlag{
+		Name:     "rpc.logs.maxresults",
+		Usage:    "Maximum number of logs returned by eth_getLogs queries and eth_getLogsPaged pages (0 = unlimited)",
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -880,6 +1016,13 @@ var (
 		// CHANGE(immutable): Add env var
 		EnvVars: []string{"GETH_FLAG_NET_RESTRICT"},
 	}
+	// CHANGE(immutable): Add flag advertising the node's role in its ENR.
+	ImmutableRoleFlag = &cli.StringFlag{
+		Name:     "p2p.role",
+		Usage:    "Role of the node advertised in the imx-role entry of its ENR (validator, boot, rpc, partner, partner-public)",
+		Category: flags.NetworkingCategory,
+		EnvVars:  []string{"GETH_FLAG_IMMUTABLE_ROLE"},
+	}
 	DNSDiscoveryFlag = &cli.StringFlag{
 		Name:     "discovery.dns",
 		Usage:    "Sets DNS discovery entry points (use \"\" to disable DNS)",
@@ -943,6 +1086,13 @@ var (
 		Usage:    "Enable expensive metrics collection and reporting",
 		Category: flags.MetricsCategory,
 	}
+	// CHANGE(immutable): Add flag to set the window of the clique signer health metrics.
+	MetricsSignerHealthWindowFlag = &cli.Uint64Flag{
+		Name:     "metrics.signerhealth.window",
+		Usage:    "Number of most recent blocks the clique signer health metrics are computed over",
+		Value:    ethconfig.Defaults.SignerHealthWindow,
+		Category: flags.MetricsCategory,
+	}
 
 	// MetricsHTTPFlag defines the endpoint for a stand-alone metrics HTTP endpoint.
 	// Since the pprof service enables sensitive/vulnerable behavior, this allows a user
@@ -1466,6 +1616,14 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		}
 		cfg.NetRestrict = list
 	}
+	// CHANGE(immutable): Advertise the node's role
+	if ctx.IsSet(ImmutableRoleFlag.Name) {
+		r, err := role.NewFromString(ctx.String(ImmutableRoleFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", ImmutableRoleFlag.Name, err)
+		}
+		cfg.Role = r.String()
+	}
 
 	if ctx.Bool(DeveloperFlag.Name) {
 		// --dev mode can't use p2p networking.
@@ -1548,6 +1706,20 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(IsPersonalRPCDisabledFlag.Name) {
 		cfg.DisablePersonal = ctx.Bool(IsPersonalRPCDisabledFlag.Name)
 	}
+	// CHANGE(immutable): configure OpenTelemetry tracing of the RPC stack
+	if ctx.IsSet(RPCTracingEndpointFlag.Name) {
+		cfg.Tracing.Endpoint = ctx.String(RPCTracingEndpointFlag.Name)
+	}
+	if ctx.IsSet(RPCTracingFileFlag.Name) {
+		cfg.Tracing.File = ctx.String(RPCTracingFileFlag.Name)
+	}
+	if ctx.IsSet(RPCTracingSampleRatioFlag.Name) {
+		ratio := ctx.Float64(RPCTracingSampleRatioFlag.Name)
+		if ratio <= 0 || ratio > 1 {
+			Fatalf("Invalid --%s %v, must be in (0, 1]", RPCTracingSampleRatioFlag.Name, ratio)
+		}
+		cfg.Tracing.SampleRatio = ratio
+	}
 	// deprecation notice for log debug flags (TODO: find a more appropriate place to put these?)
 	if ctx.IsSet(LogBacktraceAtFlag.Name) {
 		log.Warn("log.backtrace flag is deprecated")
@@ -1652,6 +1824,18 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolBlockListFilePaths.Name) {
 		cfg.BlockListFilePaths = ctx.StringSlice(TxPoolBlockListFilePaths.Name)
 	}
+	if ctx.IsSet(TxPoolExecutionAccessControlFlag.Name) {
+		cfg.ExecutionAccessControl = ctx.Bool(TxPoolExecutionAccessControlFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAuditLogFlag.Name) {
+		cfg.AuditLog = ctx.String(TxPoolAuditLogFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAuditLogMaxSizeFlag.Name) {
+		cfg.AuditLogMaxSize = ctx.Int(TxPoolAuditLogMaxSizeFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAuditLogMaxBackupsFlag.Name) {
+		cfg.AuditLogMaxBackups = ctx.Int(TxPoolAuditLogMaxBackupsFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1670,6 +1854,10 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNewPayloadTimeout.Name) {
 		cfg.NewPayloadTimeout = ctx.Duration(MinerNewPayloadTimeout.Name)
 	}
+	// CHANGE(immutable): added flag to trace sealed transactions against the txpool acls
+	if ctx.IsSet(MinerExecutionAccessControlFlag.Name) {
+		cfg.ExecutionAccessControl = ctx.Bool(MinerExecutionAccessControlFlag.Name)
+	}
 }
 
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
@@ -1840,6 +2028,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(CacheLogSizeFlag.Name) {
 		cfg.FilterLogCacheSize = ctx.Int(CacheLogSizeFlag.Name)
 	}
+	// CHANGE(immutable): Handle eth_getLogs limits configuration.
+	if ctx.IsSet(RPCLogsMaxBlockRangeFlag.Name) {
+		if cfg.FilterMaxBlockRange = ctx.Int64(RPCLogsMaxBlockRangeFlag.Name); cfg.FilterMaxBlockRange <= 0 {
+			Fatalf("Invalid --%s %d, must be positive", RPCLogsMaxBlockRangeFlag.Name, cfg.FilterMaxBlockRange)
+		}
+	}
+	if ctx.IsSet(RPCLogsMaxResultsFlag.Name) {
+		cfg.FilterMaxLogs = ctx.Int(RPCLogsMaxResultsFlag.Name)
+	}
 	if !ctx.Bool(SnapshotFlag.Name) || cfg.SnapshotCache == 0 {
 		// If snap-sync is requested, this flag is also required
 		if cfg.SyncMode == downloader.SnapSync {
@@ -1887,24 +2084,76 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.EthDiscoveryURLs = SplitAndTrim(urls)
 		}
 	}
-	// CHANGE(immutable): Handle gossiping configuration.
-	cfg.GossipDefault = ctx.Bool(ImmutableGossipDefaultFlag.Name)
+	// CHANGE(immutable): Handle gossiping configuration, keeping the config file's
+	// settings unless overridden.
+	if ctx.IsSet(ImmutableGossipDefaultFlag.Name) {
+		cfg.GossipDefault = ctx.Bool(ImmutableGossipDefaultFlag.Name)
+	}
 	// CHANGE(immutable): Handle disable txpool gossip configuration.
-	cfg.DisableTxPoolGossip = ctx.Bool(ImmutableDisableTxPoolGossipFlag.Name)
+	if ctx.IsSet(ImmutableDisableTxPoolGossipFlag.Name) {
+		cfg.DisableTxPoolGossip = ctx.Bool(ImmutableDisableTxPoolGossipFlag.Name)
+	}
+	// CHANGE(immutable): Handle per-role gossip policy configuration.
+	setGossip(ctx, &cfg.Gossip)
+	// CHANGE(immutable): Handle block fetcher catch-up mode configuration, honouring
+	// the deprecated long range sync env var.
+	setFetcherCatchUp(ctx, cfg)
+	// CHANGE(immutable): Handle peer registry configuration.
+	if ctx.IsSet(ImmutablePeerRegistryFlag.Name) {
+		cfg.PeerRegistry = ctx.String(ImmutablePeerRegistryFlag.Name)
+	}
+	// CHANGE(immutable): Handle block access policy configuration.
+	if ctx.IsSet(ImmutableBlockAccessPolicyFlag.Name) {
+		cfg.BlockAccessPolicy = ctx.String(ImmutableBlockAccessPolicyFlag.Name)
+	}
+	if ctx.IsSet(ImmutableBlockAccessPolicyFromFlag.Name) {
+		from := ctx.Uint64(ImmutableBlockAccessPolicyFromFlag.Name)
+		cfg.BlockAccessPolicyFrom = &from
+	}
+	// CHANGE(immutable): Handle the clique signer health metrics window.
+	if ctx.IsSet(MetricsSignerHealthWindowFlag.Name) {
+		if cfg.SignerHealthWindow = ctx.Uint64(MetricsSignerHealthWindowFlag.Name); cfg.SignerHealthWindow == 0 || cfg.SignerHealthWindow > clique.MaxHealthRange {
+			Fatalf("Invalid --%s %d, must be between 1 and %d", MetricsSignerHealthWindowFlag.Name, cfg.SignerHealthWindow, clique.MaxHealthRange)
+		}
+	}
 	// Override any default configs for hard coded networks.
 	// CHANGE(immutable): Handle proxy RPC forwarding configuration. Ensure this is only on RPC nodes
 	// and is set correctly depending on the Immutable network flag.
 	CheckExclusive(ctx, ImmutableRPCProxyFlag, MiningEnabledFlag)
-	if ctx.IsSet(ImmutableRPCProxyFlag.Name) && ctx.IsSet(ImmutableNetworkFlag.Name) {
-		network, err := settings.NewNetwork(ctx.String(ImmutableNetworkFlag.Name))
-		if err != nil {
-			Fatalf(err.Error())
+	if ctx.IsSet(ImmutableRPCProxyFlag.Name) {
+		cfg.RPCProxy.Enabled = ctx.Bool(ImmutableRPCProxyFlag.Name)
+	}
+	if cfg.RPCProxy.Enabled && ctx.Bool(MiningEnabledFlag.Name) {
+		Fatalf("RPC proxy mode is enabled in the config file, it cannot be used with --%s", MiningEnabledFlag.Name)
+	}
+	if cfg.RPCProxy.Enabled {
+		if ctx.IsSet(ImmutableRPCProxyUpstreamsFlag.Name) {
+			cfg.RPCProxy.Upstreams = nil
+			for _, s := range ctx.StringSlice(ImmutableRPCProxyUpstreamsFlag.Name) {
+				upstream, err := rpc.ParseProxyUpstream(s)
+				if err != nil {
+					Fatalf("Invalid --%s: %v", ImmutableRPCProxyUpstreamsFlag.Name, err)
+				}
+				cfg.RPCProxy.Upstreams = append(cfg.RPCProxy.Upstreams, upstream)
+			}
 		}
-		cfg.RPCProxyURL, err = network.RPC()
-		if err != nil {
-			Fatalf(err.Error())
+		if len(cfg.RPCProxy.Upstreams) == 0 && ctx.IsSet(ImmutableNetworkFlag.Name) {
+			network, err := settings.NewNetwork(ctx.String(ImmutableNetworkFlag.Name))
+			if err != nil {
+				Fatalf(err.Error())
+			}
+			url, err := network.RPC()
+			if err != nil {
+				Fatalf(err.Error())
+			}
+			cfg.RPCProxy.Upstreams = []rpc.ProxyUpstream{{Name: network.String(), URL: url, Weight: 1}}
+		}
+		if ctx.IsSet(ImmutableRPCProxyRetriesFlag.Name) {
+			cfg.RPCProxy.Retries = ctx.Int(ImmutableRPCProxyRetriesFlag.Name)
+		}
+		for _, upstream := range cfg.RPCProxy.Upstreams {
+			log.Info("Setting RPC Proxy upstream on node", "url", upstream.URL, "weight", upstream.Weight, "name", upstream.Name)
 		}
-		log.Info("Setting RPC Proxy on node", "url", cfg.RPCProxyURL)
 	}
 	switch {
 	// CHANGE(immutable): handle Immutable networks
@@ -2070,6 +2319,9 @@ func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSyst
 func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconfig.Config) *filters.FilterSystem {
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
 		LogCacheSize: ethcfg.FilterLogCacheSize,
+		// CHANGE(immutable): Limits of eth_getLogs queries
+		MaxBlockRange: ethcfg.FilterMaxBlockRange,
+		MaxLogs:       ethcfg.FilterMaxLogs,
 	})
 	stack.RegisterAPIs([]rpc.API{{
 		Namespace: "eth",
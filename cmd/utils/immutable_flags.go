@@ -17,11 +17,18 @@
 package utils
 
 import (
+	"os"
+	"strings"
+
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/fetcher"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/ethereum/go-ethereum/triedb/hashdb"
 	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/urfave/cli/v2"
 )
 
 // MakeTrieDatabaseNoContext is MakeTrieDatabase without being coupled to cli context to allow for further reuse.
@@ -48,3 +55,49 @@ func MakeTrieDatabaseNoContext(stateScheme string, disk ethdb.Database, preimage
 	}
 	return triedb.NewDatabase(disk, config)
 }
+
+// setGossip applies the gossip flags on top of the configured gossip policies.
+// Allowlists given as flags replace the configured ones, while policies only
+// override the roles they name.
+func setGossip(ctx *cli.Context, cfg *ethconfig.GossipConfig) {
+	if ctx.IsSet(ImmutableGossipValidatorsFlag.Name) {
+		cfg.Validators = ctx.StringSlice(ImmutableGossipValidatorsFlag.Name)
+	}
+	if ctx.IsSet(ImmutableGossipBootFlag.Name) {
+		cfg.Boot = ctx.StringSlice(ImmutableGossipBootFlag.Name)
+	}
+	if ctx.IsSet(ImmutableGossipRPCFlag.Name) {
+		cfg.RPC = ctx.StringSlice(ImmutableGossipRPCFlag.Name)
+	}
+	if ctx.IsSet(ImmutableGossipPartnersFlag.Name) {
+		cfg.Partners = ctx.StringSlice(ImmutableGossipPartnersFlag.Name)
+	}
+	for _, policy := range ctx.StringSlice(ImmutableGossipPolicyFlag.Name) {
+		role, mode, ok := strings.Cut(policy, "=")
+		if !ok {
+			Fatalf("Invalid --%s %q, expected role=mode", ImmutableGossipPolicyFlag.Name, policy)
+		}
+		if cfg.Policies == nil {
+			cfg.Policies = make(map[string]string)
+		}
+		cfg.Policies[strings.TrimSpace(role)] = strings.TrimSpace(mode)
+	}
+}
+
+// setFetcherCatchUp configures the block fetcher catch-up mode. The deprecated
+// long range sync env var, which lifted the fetcher's queue distance limit, is
+// mapped to the "on" mode unless the mode is set by its flag.
+func setFetcherCatchUp(ctx *cli.Context, cfg *ethconfig.Config) {
+	longRangeSync := os.Getenv(fetcher.LongRangeSyncEnvVar) != ""
+	if ctx.IsSet(ImmutableFetcherCatchUpFlag.Name) {
+		cfg.FetcherCatchUp = ctx.String(ImmutableFetcherCatchUpFlag.Name)
+		if longRangeSync {
+			log.Warn("Ignoring deprecated "+fetcher.LongRangeSyncEnvVar+" in favour of --"+ImmutableFetcherCatchUpFlag.Name, "mode", cfg.FetcherCatchUp)
+		}
+		return
+	}
+	if longRangeSync {
+		log.Warn(fetcher.LongRangeSyncEnvVar+" is deprecated, use --"+ImmutableFetcherCatchUpFlag.Name+"="+fetcher.CatchUpOn, "mode", fetcher.CatchUpOn)
+		cfg.FetcherCatchUp = fetcher.CatchUpOn
+	}
+}
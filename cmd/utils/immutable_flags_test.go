@@ -0,0 +1,93 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/fetcher"
+	"github.com/urfave/cli/v2"
+)
+
+func TestImmutableSetGossip(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range []cli.Flag{ImmutableGossipValidatorsFlag, ImmutableGossipBootFlag, ImmutableGossipRPCFlag, ImmutableGossipPartnersFlag, ImmutableGossipPolicyFlag} {
+		if err := f.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+	}
+	validator := "enode://a979fb575495b8d6db44f750317d0f4622bf4c2aa3365d6af7c284339968eef29b69ad0dce72a4d8db5ebb4968de0e3bec910127f134779fbcb0cb6d3331163c@52.16.188.185:30303"
+	args := []string{
+		"--gossip.validators", validator,
+		"--gossip.policy", "partner=none",
+		"--gossip.policy", " inbound = announce ",
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	// Flags override the configured allowlists and the policies of their roles
+	cfg := ethconfig.GossipConfig{
+		Validators: []string{"0x01"},
+		Partners:   []string{"0x02"},
+		Policies:   map[string]string{"partner": "full", "rpc": "sqrt"},
+	}
+	setGossip(cli.NewContext(cli.NewApp(), set, nil), &cfg)
+
+	want := ethconfig.GossipConfig{
+		Validators: []string{validator},
+		Partners:   []string{"0x02"},
+		Policies:   map[string]string{"partner": "none", "rpc": "sqrt", "inbound": "announce"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("gossip config mismatch:\nhave %+v\nwant %+v", cfg, want)
+	}
+}
+
+func TestImmutableSetFetcherCatchUp(t *testing.T) {
+	newContext := func(args ...string) *cli.Context {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		if err := ImmutableFetcherCatchUpFlag.Apply(set); err != nil {
+			t.Fatal(err)
+		}
+		if err := set.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+	// The deprecated env var forces the catch-up mode on
+	t.Setenv(fetcher.LongRangeSyncEnvVar, "1")
+	cfg := ethconfig.Config{FetcherCatchUp: fetcher.CatchUpAuto}
+	setFetcherCatchUp(newContext(), &cfg)
+	if cfg.FetcherCatchUp != fetcher.CatchUpOn {
+		t.Fatalf("expected catch-up mode %q, got %q", fetcher.CatchUpOn, cfg.FetcherCatchUp)
+	}
+	// Unless the mode is set by its flag
+	setFetcherCatchUp(newContext("--fetcher.catchup", fetcher.CatchUpOff), &cfg)
+	if cfg.FetcherCatchUp != fetcher.CatchUpOff {
+		t.Fatalf("expected catch-up mode %q, got %q", fetcher.CatchUpOff, cfg.FetcherCatchUp)
+	}
+	// Without either, the configured mode is kept
+	t.Setenv(fetcher.LongRangeSyncEnvVar, "")
+	cfg.FetcherCatchUp = fetcher.CatchUpAuto
+	setFetcherCatchUp(newContext(), &cfg)
+	if cfg.FetcherCatchUp != fetcher.CatchUpAuto {
+		t.Fatalf("expected catch-up mode %q, got %q", fetcher.CatchUpAuto, cfg.FetcherCatchUp)
+	}
+}
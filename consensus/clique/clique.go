@@ -186,6 +186,9 @@ type Clique struct {
 	signFn SignerFn       // Signer function to authorize hashes with
 	lock   sync.RWMutex   // Protects the signer and proposals fields
 
+	// CHANGE(immutable): Recently observed sealed headers, used to detect equivocations
+	sealed *lru.Cache[sealKey, *types.Header]
+
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
 }
@@ -208,6 +211,8 @@ func New(config *params.CliqueConfig, db ethdb.Database) *Clique {
 		recents:    recents,
 		signatures: signatures,
 		proposals:  make(map[common.Address]bool),
+		// CHANGE(immutable): Track sealed headers for equivocation detection
+		sealed: lru.NewCache[sealKey, *types.Header](inmemorySealed),
 	}
 }
 
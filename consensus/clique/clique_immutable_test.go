@@ -26,7 +26,10 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
 )
 
 func TestImmutableClique_ForksEnabled_BlocksValid(t *testing.T) {
@@ -122,3 +125,251 @@ func TestImmutableClique_ForksEnabled_BlocksValid(t *testing.T) {
 		})
 	}
 }
+
+func TestImmutableClique_ObserveHeader_DetectsEquivocation(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = New(params.AllCliqueProtocolChanges.Clique, db)
+		ap     = newTesterAccountPool()
+	)
+	// Authorize signers A and B in the genesis, X is an outsider
+	genspec := &core.Genesis{
+		Config:    params.AllCliqueProtocolChanges,
+		ExtraData: make([]byte, extraVanity+2*common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	signers := []common.Address{ap.address("A"), ap.address("B")}
+	slices.SortFunc(signers, common.Address.Cmp)
+	for i, signer := range signers {
+		copy(genspec.ExtraData[extraVanity+i*common.AddressLength:], signer[:])
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	newHeader := func(number int64, signer string, time uint64) *types.Header {
+		header := &types.Header{
+			ParentHash: chain.Genesis().Hash(),
+			Number:     big.NewInt(number),
+			Time:       time,
+			Difficulty: diffInTurn,
+			Extra:      make([]byte, extraVanity+extraSeal),
+		}
+		ap.sign(header, signer)
+		return header
+	}
+	first := newHeader(1, "A", 100)
+	engine.ObserveHeader(chain, first, "chain", "")
+	engine.ObserveHeader(chain, first, "fetcher", "peer-1")
+	engine.ObserveHeader(chain, newHeader(1, "B", 101), "side", "")
+	engine.ObserveHeader(chain, newHeader(2, "A", 102), "chain", "")
+
+	// Conflicting headers by an unauthorized key are not evidence
+	engine.ObserveHeader(chain, newHeader(1, "X", 100), "fetcher", "peer-3")
+	engine.ObserveHeader(chain, newHeader(1, "X", 101), "fetcher", "peer-3")
+
+	api := &API{clique: engine}
+	evidence, err := api.GetEquivocations()
+	if err != nil {
+		t.Fatalf("failed to read equivocations: %v", err)
+	}
+	if len(evidence) != 0 {
+		t.Fatalf("unexpected equivocations: have %d, want 0", len(evidence))
+	}
+
+	second := newHeader(1, "A", 103)
+	engine.ObserveHeader(chain, second, "fetcher", "peer-2")
+	engine.ObserveHeader(chain, second, "side", "")
+
+	evidence, err = api.GetEquivocations()
+	if err != nil {
+		t.Fatalf("failed to read equivocations: %v", err)
+	}
+	if len(evidence) != 1 {
+		t.Fatalf("equivocation count mismatch: have %d, want 1", len(evidence))
+	}
+	e := evidence[0]
+	if e.Signer != ap.address("A") || uint64(e.Number) != 1 {
+		t.Errorf("evidence mismatch: have %x #%d, want %x #1", e.Signer, e.Number, ap.address("A"))
+	}
+	if e.First.Hash() != first.Hash() || e.Second.Hash() != second.Hash() {
+		t.Errorf("evidence headers mismatch: have %x/%x, want %x/%x", e.First.Hash(), e.Second.Hash(), first.Hash(), second.Hash())
+	}
+	if e.Source != "fetcher" || e.Peer != "peer-2" {
+		t.Errorf("evidence origin mismatch: have %s/%s, want fetcher/peer-2", e.Source, e.Peer)
+	}
+	var decoded types.Header
+	if err := rlp.DecodeBytes(e.SecondRLP, &decoded); err != nil {
+		t.Fatalf("failed to decode evidence header: %v", err)
+	}
+	if signer, err := engine.Author(&decoded); err != nil || signer != ap.address("A") {
+		t.Errorf("evidence seal mismatch: have %x (%v), want %x", signer, err, ap.address("A"))
+	}
+	// Evidence is kept out of the clique snapshot key space
+	it := db.NewIterator(rawdb.CliqueSnapshotPrefix, nil)
+	for it.Next() {
+		if len(it.Key()) != len(rawdb.CliqueSnapshotPrefix)+common.HashLength {
+			t.Errorf("unexpected key in the clique snapshot key space: %x", it.Key())
+		}
+	}
+	it.Release()
+
+	// Undecodable entries are skipped
+	rawdb.WriteCliqueEquivocation(db, 2, ap.address("B"), common.Hash{0x01}, []byte{0x01})
+	evidence, err = api.GetEquivocations()
+	if err != nil {
+		t.Fatalf("failed to read equivocations: %v", err)
+	}
+	if len(evidence) != 1 {
+		t.Fatalf("equivocation count mismatch: have %d, want 1", len(evidence))
+	}
+}
+
+func TestImmutableClique_SignerHealth_MissedSlots(t *testing.T) {
+	// Initialize a Clique chain with three signers, one of which never seals
+	var (
+		ap      = newTesterAccountPool()
+		names   = []string{"A", "B", "C"}
+		signers = make([]common.Address, len(names))
+		keys    = make(map[common.Address]string)
+	)
+	for i, name := range names {
+		signers[i] = ap.address(name)
+		keys[signers[i]] = name
+	}
+	slices.SortFunc(signers, common.Address.Cmp)
+	offline := signers[2]
+
+	genspec := &core.Genesis{
+		Config:    params.AllCliqueProtocolChanges,
+		ExtraData: make([]byte, extraVanity+len(signers)*common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	for i, signer := range signers {
+		copy(genspec.ExtraData[extraVanity+i*common.AddressLength:], signer[:])
+	}
+	engine := New(params.AllCliqueProtocolChanges.Clique, rawdb.NewMemoryDatabase())
+	engine.fakeDiff = true
+
+	const count = 30
+	_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, count, func(i int, block *core.BlockGen) {
+		block.SetDifficulty(diffInTurn)
+	})
+	// Seal every block with the scheduled signer if it is online and allowed
+	// to sign, otherwise with the first online signer that is allowed.
+	var (
+		last       common.Address
+		missed     uint64
+		collisions = make(map[common.Address]uint64)
+	)
+	for i, block := range blocks {
+		header := block.Header()
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+
+		number := header.Number.Uint64()
+		scheduled := signers[number%uint64(len(signers))]
+		sealer := scheduled
+		switch {
+		case scheduled == offline:
+			missed++
+			sealer = signers[0]
+			if sealer == last {
+				sealer = signers[1]
+			}
+		case scheduled == last:
+			collisions[scheduled]++
+			sealer = signers[0]
+			if sealer == last {
+				sealer = signers[1]
+			}
+		}
+		header.Difficulty = diffNoTurn
+		if sealer == scheduled {
+			header.Difficulty = diffInTurn
+		}
+		ap.sign(header, keys[sealer])
+		blocks[i] = block.WithSeal(header)
+		last = sealer
+	}
+	chain, _ := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert blocks: %v", err)
+	}
+	report, err := engine.SignerHealth(chain, 1, count)
+	if err != nil {
+		t.Fatalf("failed to compute signer health: %v", err)
+	}
+	if len(report.Signers) != len(signers) {
+		t.Fatalf("signer count mismatch: have %d, want %d", len(report.Signers), len(signers))
+	}
+	var sealed uint64
+	for _, h := range report.Signers {
+		sealed += h.InTurnBlocks + h.OutOfTurnBlocks
+		if h.Collisions != collisions[h.Signer] {
+			t.Errorf("signer %x collisions mismatch: have %d, want %d", h.Signer, h.Collisions, collisions[h.Signer])
+		}
+		if h.Signer != offline {
+			continue
+		}
+		if h.MissedInTurn != missed || len(h.MissedSlots) != int(missed) {
+			t.Errorf("offline signer missed slots mismatch: have %d, want %d", h.MissedInTurn, missed)
+		}
+		if h.InTurnBlocks != 0 || h.OutOfTurnBlocks != 0 || h.LastSigned != nil {
+			t.Errorf("offline signer reported as sealing: %+v", h)
+		}
+	}
+	if sealed != count {
+		t.Errorf("sealed block count mismatch: have %d, want %d", sealed, count)
+	}
+	if _, err := engine.SignerHealth(chain, 0, count); err == nil {
+		t.Errorf("expected error for range starting at genesis")
+	}
+}
+
+func TestImmutableClique_SignerHealthMetrics_Unregister(t *testing.T) {
+	var (
+		staying = common.HexToAddress("0x01")
+		leaving = common.HexToAddress("0x02")
+		gauge   = func(signer common.Address) string { return signerHealthPrefix(signer) + "inturn" }
+		m       = NewSignerHealthMetrics()
+	)
+	defer m.Stop()
+
+	m.Update(&SignerHealthReport{
+		Signers: []*SignerHealth{{Signer: staying, InTurnBlocks: 1}, {Signer: leaving, InTurnBlocks: 2}},
+		current: []common.Address{staying, leaving},
+	})
+	for _, signer := range []common.Address{staying, leaving} {
+		if metrics.Get(gauge(signer)) == nil {
+			t.Fatalf("signer %x gauge not registered", signer)
+		}
+	}
+	// The leaving signer still sealed blocks in the window but is no longer
+	// part of the signer set at its end.
+	m.Update(&SignerHealthReport{
+		Signers: []*SignerHealth{{Signer: staying, InTurnBlocks: 3}, {Signer: leaving, InTurnBlocks: 1}},
+		current: []common.Address{staying},
+	})
+	if metrics.Get(gauge(leaving)) != nil {
+		t.Errorf("gauge of removed signer still registered")
+	}
+	for _, name := range signerHealthGauges {
+		if metrics.Get(signerHealthPrefix(leaving)+name) != nil {
+			t.Errorf("gauge %s of removed signer still registered", name)
+		}
+	}
+	if metrics.Get(gauge(staying)) == nil {
+		t.Errorf("gauge of remaining signer unregistered")
+	}
+	m.Stop()
+	if metrics.Get(gauge(staying)) != nil {
+		t.Errorf("gauge still registered after stop")
+	}
+}
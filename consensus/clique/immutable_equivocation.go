@@ -0,0 +1,221 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// inmemorySealed is the number of recently observed (signer, number) pairs
+// kept in memory to detect equivocations.
+const inmemorySealed = 4096
+
+var (
+	equivocationMeter = metrics.NewRegisteredMeter("clique/equivocations", nil)
+
+	// errInvalidEquivocation is returned when a stored evidence pair does not
+	// prove that a single signer sealed two blocks at the same height.
+	errInvalidEquivocation = errors.New("invalid equivocation evidence")
+)
+
+// sealKey identifies the slot a signer may seal exactly one block for.
+type sealKey struct {
+	signer common.Address
+	number uint64
+}
+
+// Equivocation is the evidence that a signer sealed two different headers at
+// the same height. Both headers carry the signer's seal in their extra-data, so
+// the evidence can be verified independently by recovering the signatures.
+type Equivocation struct {
+	Signer     common.Address
+	Number     uint64
+	First      *types.Header // Header seen first
+	Second     *types.Header // Conflicting header seen later
+	Source     string        // Where the conflicting header was observed (e.g. chain, side, fetcher)
+	Peer       string        // Peer which delivered the conflicting header, if known
+	DetectedAt uint64        // Unix time of detection
+}
+
+// Verify checks that both headers are distinct, at the same height and sealed
+// by the recorded signer.
+func (e *Equivocation) Verify() error {
+	if e.First == nil || e.Second == nil || e.First.Hash() == e.Second.Hash() {
+		return errInvalidEquivocation
+	}
+	if e.First.Number.Uint64() != e.Number || e.Second.Number.Uint64() != e.Number {
+		return errInvalidEquivocation
+	}
+	for _, header := range []*types.Header{e.First, e.Second} {
+		signer, err := ecrecover(header, lru.NewCache[common.Hash, common.Address](1))
+		if err != nil {
+			return err
+		}
+		if signer != e.Signer {
+			return errInvalidEquivocation
+		}
+	}
+	return nil
+}
+
+// EquivocationEvidence is the RPC representation of an equivocation. The RLP
+// encoded headers allow the seals to be re-verified by third parties.
+type EquivocationEvidence struct {
+	Signer     common.Address `json:"signer"`
+	Number     hexutil.Uint64 `json:"number"`
+	First      *types.Header  `json:"first"`
+	Second     *types.Header  `json:"second"`
+	FirstRLP   hexutil.Bytes  `json:"firstRlp"`
+	SecondRLP  hexutil.Bytes  `json:"secondRlp"`
+	Source     string         `json:"source"`
+	Peer       string         `json:"peer"`
+	DetectedAt hexutil.Uint64 `json:"detectedAt"`
+}
+
+// ObserveHeader checks a sealed header against the headers previously sealed by
+// the same signer at the same height, and against the canonical header at that
+// height. Any conflict is persisted as evidence, metered and logged. Headers
+// whose parent is unknown or whose signer is not authorized by the parent's
+// snapshot are ignored.
+func (c *Clique) ObserveHeader(chain consensus.ChainHeaderReader, header *types.Header, source, peer string) {
+	if header.Number == nil || header.Number.Sign() == 0 {
+		return
+	}
+	signer, err := ecrecover(header, c.signatures)
+	if err != nil {
+		log.Debug("Failed to recover clique signer", "number", header.Number, "hash", header.Hash(), "err", err)
+		return
+	}
+	number := header.Number.Uint64()
+
+	// Only authorized signers can equivocate. Headers sealed by any other key,
+	// which anyone can produce, are neither evidence nor worth remembering.
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		log.Debug("Failed to retrieve clique snapshot of observed header", "number", number, "hash", header.Hash(), "err", err)
+		return
+	}
+	if _, ok := snap.Signers[signer]; !ok {
+		log.Debug("Ignoring header sealed by unauthorized signer", "number", number, "hash", header.Hash(), "signer", signer, "source", source, "peer", peer)
+		return
+	}
+	key := sealKey{signer: signer, number: number}
+
+	prev, ok := c.sealed.Get(key)
+	if !ok {
+		if canonical := chain.GetHeaderByNumber(number); canonical != nil {
+			if author, err := ecrecover(canonical, c.signatures); err == nil && author == signer {
+				prev, ok = canonical, true
+			}
+		}
+	}
+	if !ok {
+		c.sealed.Add(key, header)
+		return
+	}
+	if prev.Hash() == header.Hash() {
+		return
+	}
+	if rawdb.HasCliqueEquivocation(c.db, number, signer, header.Hash()) {
+		return
+	}
+	evidence := &Equivocation{
+		Signer:     signer,
+		Number:     number,
+		First:      prev,
+		Second:     header,
+		Source:     source,
+		Peer:       peer,
+		DetectedAt: uint64(time.Now().Unix()),
+	}
+	if err := writeEquivocation(c.db, evidence); err != nil {
+		log.Error("Failed to store clique equivocation", "err", err)
+	}
+	equivocationMeter.Mark(1)
+	log.Error("Clique equivocation detected", "signer", signer, "number", number,
+		"first", prev.Hash(), "second", header.Hash(), "source", source, "peer", peer)
+}
+
+// writeEquivocation persists an equivocation evidence pair.
+func writeEquivocation(db ethdb.KeyValueWriter, e *Equivocation) error {
+	blob, err := rlp.EncodeToBytes(e)
+	if err != nil {
+		return err
+	}
+	rawdb.WriteCliqueEquivocation(db, e.Number, e.Signer, e.Second.Hash(), blob)
+	return nil
+}
+
+// readEquivocations retrieves all stored equivocations, ordered by block number.
+// Entries which can't be decoded are skipped.
+func readEquivocations(db ethdb.Iteratee) []*Equivocation {
+	var evidence []*Equivocation
+	for _, blob := range rawdb.ReadCliqueEquivocations(db) {
+		e := new(Equivocation)
+		if err := rlp.DecodeBytes(blob, e); err != nil {
+			log.Warn("Skipping undecodable clique equivocation", "err", err)
+			continue
+		}
+		evidence = append(evidence, e)
+	}
+	return evidence
+}
+
+// GetEquivocations returns the evidence of all signers observed sealing two
+// different blocks at the same height.
+func (api *API) GetEquivocations() ([]*EquivocationEvidence, error) {
+	stored := readEquivocations(api.clique.db)
+	results := make([]*EquivocationEvidence, 0, len(stored))
+	for _, e := range stored {
+		if err := e.Verify(); err != nil {
+			log.Warn("Skipping invalid clique equivocation", "signer", e.Signer, "number", e.Number, "err", err)
+			continue
+		}
+		first, err := rlp.EncodeToBytes(e.First)
+		if err != nil {
+			return nil, err
+		}
+		second, err := rlp.EncodeToBytes(e.Second)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &EquivocationEvidence{
+			Signer:     e.Signer,
+			Number:     hexutil.Uint64(e.Number),
+			First:      e.First,
+			Second:     e.Second,
+			FirstRLP:   first,
+			SecondRLP:  second,
+			Source:     e.Source,
+			Peer:       e.Peer,
+			DetectedAt: hexutil.Uint64(e.DetectedAt),
+		})
+	}
+	return results, nil
+}
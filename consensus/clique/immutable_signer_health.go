@@ -0,0 +1,250 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// defaultHealthRange is the number of blocks inspected when no range is
+	// given to clique_getSignerHealth.
+	defaultHealthRange = 1024
+
+	// MaxHealthRange is the maximum number of blocks a single signer health
+	// report may cover.
+	MaxHealthRange = 16384
+)
+
+var errInvalidHealthRange = errors.New("invalid block range")
+
+// SignerHealth is the liveness record of a single signer over a block range.
+type SignerHealth struct {
+	Signer          common.Address   `json:"signer"`
+	InTurnBlocks    uint64           `json:"inTurnBlocks"`
+	OutOfTurnBlocks uint64           `json:"outOfTurnBlocks"`
+	MissedInTurn    uint64           `json:"missedInTurnSlots"`
+	MissedSlots     []hexutil.Uint64 `json:"missedSlots"`
+	Collisions      uint64           `json:"recentSignerCollisions"`
+	LastSigned      *hexutil.Uint64  `json:"lastSignedBlock"`
+
+	// AverageLatency is the mean time in seconds between a block sealed by
+	// the signer and its parent, to be compared against the clique period.
+	AverageLatency float64 `json:"averageBlockLatency"`
+
+	latencyTotal uint64
+}
+
+// SignerHealthReport is the liveness of all signers over a block range.
+type SignerHealthReport struct {
+	From    hexutil.Uint64  `json:"from"`
+	To      hexutil.Uint64  `json:"to"`
+	Period  hexutil.Uint64  `json:"period"`
+	Signers []*SignerHealth `json:"signers"`
+
+	current []common.Address // Signer set of the snapshot at the end of the range
+}
+
+// SignerHealth replays the headers in the inclusive range [from, to] on top of
+// the snapshot preceding it, attributing every in-turn slot to the signer
+// scheduled for it. A slot sealed by another signer counts as missed by the
+// scheduled signer, unless the recent signer limit prevented it from sealing,
+// which is counted as a collision instead.
+func (c *Clique) SignerHealth(chain consensus.ChainHeaderReader, from, to uint64) (*SignerHealthReport, error) {
+	if from == 0 || from > to || to-from+1 > MaxHealthRange {
+		return nil, fmt.Errorf("%w: [%d, %d]", errInvalidHealthRange, from, to)
+	}
+	parent := chain.GetHeaderByNumber(from - 1)
+	if parent == nil {
+		return nil, fmt.Errorf("missing block %d", from-1)
+	}
+	snap, err := c.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	health := make(map[common.Address]*SignerHealth)
+	get := func(signer common.Address) *SignerHealth {
+		if h, ok := health[signer]; ok {
+			return h
+		}
+		h := &SignerHealth{Signer: signer}
+		health[signer] = h
+		return h
+	}
+	for _, signer := range snap.signers() {
+		get(signer)
+	}
+	for number := from; number <= to; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, fmt.Errorf("missing block %d", number)
+		}
+		signers := snap.signers()
+		scheduled := signers[number%uint64(len(signers))]
+
+		sealer, err := ecrecover(header, c.signatures)
+		if err != nil {
+			return nil, err
+		}
+		h := get(sealer)
+		if sealer == scheduled {
+			h.InTurnBlocks++
+		} else {
+			h.OutOfTurnBlocks++
+
+			missed := get(scheduled)
+			if snap.recentlySigned(number, scheduled) {
+				missed.Collisions++
+			} else {
+				missed.MissedInTurn++
+				missed.MissedSlots = append(missed.MissedSlots, hexutil.Uint64(number))
+			}
+		}
+		last := hexutil.Uint64(number)
+		h.LastSigned = &last
+		if header.Time > parent.Time {
+			h.latencyTotal += header.Time - parent.Time
+		}
+		if snap, err = snap.apply([]*types.Header{header}); err != nil {
+			return nil, err
+		}
+		parent = header
+	}
+	report := &SignerHealthReport{
+		From:    hexutil.Uint64(from),
+		To:      hexutil.Uint64(to),
+		Period:  hexutil.Uint64(c.config.Period),
+		current: snap.signers(),
+	}
+	for _, h := range health {
+		if sealed := h.InTurnBlocks + h.OutOfTurnBlocks; sealed > 0 {
+			h.AverageLatency = float64(h.latencyTotal) / float64(sealed)
+		}
+		report.Signers = append(report.Signers, h)
+	}
+	slices.SortFunc(report.Signers, func(a, b *SignerHealth) int {
+		return a.Signer.Cmp(b.Signer)
+	})
+	return report, nil
+}
+
+// recentlySigned reports whether the signer is barred from sealing the given
+// block number by the recent signer limit.
+func (s *Snapshot) recentlySigned(number uint64, signer common.Address) bool {
+	limit := uint64(len(s.Signers)/2 + 1)
+	for seen, recent := range s.Recents {
+		if recent == signer && (number < limit || seen > number-limit) {
+			return true
+		}
+	}
+	return false
+}
+
+// signerHealthGauges are the names of the per-signer health gauges.
+var signerHealthGauges = []string{"inturn", "outofturn", "missed", "collisions", "lastsigned", "latency"}
+
+// SignerHealthMetrics publishes signer health reports as per-signer gauges.
+type SignerHealthMetrics struct {
+	exported map[common.Address]struct{} // Signers whose gauges are registered
+}
+
+// NewSignerHealthMetrics creates an empty signer health gauge exporter.
+func NewSignerHealthMetrics() *SignerHealthMetrics {
+	return &SignerHealthMetrics{exported: make(map[common.Address]struct{})}
+}
+
+// Update sets the per-signer health gauges from the given report. Only the
+// signers of the snapshot at the end of the report's range are exported; the
+// gauges of signers that left the signer set are unregistered.
+func (m *SignerHealthMetrics) Update(report *SignerHealthReport) {
+	current := make(map[common.Address]struct{}, len(report.current))
+	for _, signer := range report.current {
+		current[signer] = struct{}{}
+	}
+	for _, h := range report.Signers {
+		if _, ok := current[h.Signer]; !ok {
+			continue
+		}
+		prefix := signerHealthPrefix(h.Signer)
+		metrics.GetOrRegisterGauge(prefix+"inturn", nil).Update(int64(h.InTurnBlocks))
+		metrics.GetOrRegisterGauge(prefix+"outofturn", nil).Update(int64(h.OutOfTurnBlocks))
+		metrics.GetOrRegisterGauge(prefix+"missed", nil).Update(int64(h.MissedInTurn))
+		metrics.GetOrRegisterGauge(prefix+"collisions", nil).Update(int64(h.Collisions))
+		if h.LastSigned != nil {
+			metrics.GetOrRegisterGauge(prefix+"lastsigned", nil).Update(int64(*h.LastSigned))
+		}
+		metrics.GetOrRegisterGaugeFloat64(prefix+"latency", nil).Update(h.AverageLatency)
+		m.exported[h.Signer] = struct{}{}
+	}
+	for signer := range m.exported {
+		if _, ok := current[signer]; !ok {
+			m.unregister(signer)
+		}
+	}
+}
+
+// Stop unregisters the gauges of all exported signers.
+func (m *SignerHealthMetrics) Stop() {
+	for signer := range m.exported {
+		m.unregister(signer)
+	}
+}
+
+func (m *SignerHealthMetrics) unregister(signer common.Address) {
+	prefix := signerHealthPrefix(signer)
+	for _, name := range signerHealthGauges {
+		metrics.Unregister(prefix + name)
+	}
+	delete(m.exported, signer)
+}
+
+func signerHealthPrefix(signer common.Address) string {
+	return "clique/signer/" + strings.ToLower(signer.Hex()) + "/"
+}
+
+// GetSignerHealth returns the missed in-turn slots, out-of-turn blocks, last
+// signed block, average block latency and recent signer collisions of every
+// signer over the inclusive block range. The range defaults to the last 1024
+// blocks ending at the current head.
+func (api *API) GetSignerHealth(from *rpc.BlockNumber, to *rpc.BlockNumber) (*SignerHealthReport, error) {
+	head := api.chain.CurrentHeader().Number.Uint64()
+	end := head
+	if to != nil && *to >= 0 {
+		end = uint64(to.Int64())
+	}
+	if end > head {
+		return nil, errUnknownBlock
+	}
+	start := uint64(1)
+	if end > defaultHealthRange {
+		start = end - defaultHealthRange + 1
+	}
+	if from != nil && *from >= 0 {
+		start = uint64(from.Int64())
+	}
+	return api.clique.SignerHealth(api.chain, start, end)
+}
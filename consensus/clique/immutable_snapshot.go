@@ -0,0 +1,64 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errNoStoredSnapshot is returned if no voting snapshot is stored at any
+// checkpoint of the canonical chain.
+var errNoStoredSnapshot = errors.New("no stored clique snapshot")
+
+// LoadLatestSnapshot returns the most recent voting snapshot stored at a
+// checkpoint of the canonical chain at or below the given block number. It
+// only reads the database, so it is safe to use on read-only datadirs.
+func LoadLatestSnapshot(config *params.CliqueConfig, db ethdb.Database, number uint64) (*Snapshot, error) {
+	for checkpoint := number - number%checkpointInterval; ; checkpoint -= checkpointInterval {
+		if hash := rawdb.ReadCanonicalHash(db, checkpoint); hash != (common.Hash{}) {
+			if snap, err := loadSnapshot(config, nil, db, hash); err == nil {
+				return snap, nil
+			}
+		}
+		if checkpoint == 0 {
+			return nil, errNoStoredSnapshot
+		}
+	}
+}
+
+// CanSign reports whether the signer is allowed to seal the block following
+// the snapshot, and if so, whether it would be sealing it in-turn. It applies
+// the same rules as seal verification.
+func (s *Snapshot) CanSign(signer common.Address) (allowed bool, inturn bool) {
+	if _, ok := s.Signers[signer]; !ok {
+		return false, false
+	}
+	number := s.Number + 1
+	for seen, recent := range s.Recents {
+		if recent == signer {
+			if limit := uint64(len(s.Signers)/2 + 1); seen > number-limit {
+				return false, false
+			}
+		}
+	}
+	return true, s.inturn(number, signer)
+}
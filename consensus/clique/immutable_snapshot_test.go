@@ -0,0 +1,94 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestImmutableLoadLatestSnapshot(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	config := params.AllCliqueProtocolChanges.Clique
+
+	if _, err := LoadLatestSnapshot(config, db, 5000); !errors.Is(err, errNoStoredSnapshot) {
+		t.Fatalf("expected %v, got %v", errNoStoredSnapshot, err)
+	}
+	// Store snapshots at the genesis and the first checkpoint
+	for i, signers := range [][]common.Address{{{0x01}}, {{0x01}, {0x02}}} {
+		number := uint64(i) * checkpointInterval
+		hash := common.Hash{byte(i + 1)}
+		rawdb.WriteCanonicalHash(db, hash, number)
+		if err := newSnapshot(config, nil, number, hash, signers).store(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		number  uint64
+		want    uint64
+		signers int
+	}{
+		{0, 0, 1},
+		{checkpointInterval - 1, 0, 1},
+		{checkpointInterval, checkpointInterval, 2},
+		{3 * checkpointInterval, checkpointInterval, 2},
+	}
+	for _, test := range tests {
+		snap, err := LoadLatestSnapshot(config, db, test.number)
+		if err != nil {
+			t.Fatalf("number %d: %v", test.number, err)
+		}
+		if snap.Number != test.want || len(snap.Signers) != test.signers {
+			t.Errorf("number %d: got snapshot %d with %d signers, want %d with %d", test.number, snap.Number, len(snap.Signers), test.want, test.signers)
+		}
+	}
+}
+
+func TestImmutableSnapshotCanSign(t *testing.T) {
+	config := params.AllCliqueProtocolChanges.Clique
+	signers := []common.Address{{0x01}, {0x02}, {0x03}}
+
+	snap := newSnapshot(config, nil, 4, common.Hash{}, signers)
+	snap.Recents[4] = common.Address{0x03}
+	snap.Recents[3] = common.Address{0x02}
+
+	tests := []struct {
+		signer  common.Address
+		allowed bool
+		inturn  bool
+	}{
+		{common.Address{0x01}, true, false},
+		{common.Address{0x02}, true, false}, // shifted out of recents by block 5
+		{common.Address{0x03}, false, false},
+		{common.Address{0x04}, false, false},
+	}
+	for _, test := range tests {
+		allowed, inturn := snap.CanSign(test.signer)
+		if allowed != test.allowed || inturn != test.inturn {
+			t.Errorf("signer %x: got allowed %t inturn %t, want %t %t", test.signer, allowed, inturn, test.allowed, test.inturn)
+		}
+	}
+	// Block 6 is in-turn for the first signer
+	snap.Number = 5
+	if allowed, inturn := snap.CanSign(common.Address{0x01}); !allowed || !inturn {
+		t.Errorf("unexpected result for block 6: allowed %t inturn %t", allowed, inturn)
+	}
+}
@@ -109,6 +109,11 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 	}
 
+	// CHANGE(immutable): Reject blocks carrying transactions that violate the access-control policy
+	if err := v.bc.checkAccessPolicy(block); err != nil {
+		return err
+	}
+
 	// Ancestor block must be known.
 	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
 		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
@@ -267,6 +267,12 @@ type BlockChain struct {
 	processor  Processor // Block transaction processor interface
 	forker     *ForkChoice
 	vmConfig   vm.Config
+
+	// CHANGE(immutable): Optional access-control policy enforced on imported blocks
+	accessPolicy atomic.Pointer[blockAccessPolicy]
+
+	// CHANGE(immutable): Peer attribution of recently delivered blocks, used when quarantining forks
+	blockOrigins *lru.Cache[common.Hash, rawdb.BlockOrigin]
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -310,6 +316,8 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 		futureBlocks:  lru.NewCache[common.Hash, *types.Block](maxFutureBlocks),
 		engine:        engine,
 		vmConfig:      vmConfig,
+		// CHANGE(immutable): Track block origins for fork quarantine
+		blockOrigins: lru.NewCache[common.Hash, rawdb.BlockOrigin](blockOriginCacheLimit),
 	}
 	bc.flushInterval.Store(int64(cacheConfig.TrieTimeLimit))
 	bc.forker = NewForkChoice(bc, shouldPreserve)
@@ -2193,6 +2201,8 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 				"newHead.Hash", newHead.Hash().String(),
 				"newHead.ParentHash", newHead.ParentHash().String())
 			blockReorgMeter.Mark(1)
+			// CHANGE(immutable): Persist the competing branch so it can be inspected later
+			bc.quarantineFork(oldChain, newChain, oldBlock, newBlock, newHead)
 			return ErrReorgAttempted
 		}
 	}
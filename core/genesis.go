@@ -213,6 +213,8 @@ type ChainOverrides struct {
 	// CHANGE(immutable): Add Prevrandao and Shanghai overrides
 	OverridePrevrandao *uint64
 	OverrideShanghai   *uint64
+	// CHANGE(immutable): Add fork schedule overrides, fork times by name
+	OverrideForks map[string]uint64
 }
 
 // SetupGenesisBlock writes or updates the genesis block in db.
@@ -236,6 +238,10 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *triedb.Database, g
 	if genesis != nil && genesis.Config == nil {
 		return params.AllEthashProtocolChanges, common.Hash{}, errGenesisNoConfig
 	}
+	// CHANGE(immutable): Reject schedules of unknown forks
+	if err := validateForkOverrides(overrides); err != nil {
+		return params.AllEthashProtocolChanges, common.Hash{}, err
+	}
 	applyOverrides := func(config *params.ChainConfig) {
 		if config != nil {
 			if overrides != nil && overrides.OverrideCancun != nil {
@@ -252,6 +258,10 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *triedb.Database, g
 			if overrides != nil && overrides.OverrideShanghai != nil {
 				config.ShanghaiTime = overrides.OverrideShanghai
 			}
+			// CHANGE(immutable): Apply the fork schedule
+			if overrides != nil {
+				applyForkOverrides(config, overrides.OverrideForks)
+			}
 		}
 	}
 	// Just commit the new block if there is no stored genesis block.
@@ -301,32 +311,46 @@ func SetupGenesisBlockWithOverride(db ethdb.Database, triedb *triedb.Database, g
 	}
 	// Get the existing chain configuration.
 	newcfg := genesis.configOrDefault(stored)
+	storedcfg := rawdb.ReadChainConfig(db, stored)
+	// CHANGE(immutable): Without a genesis, apply the overrides to a copy so that
+	// neither the default configs nor the stored one, which the overrides are
+	// checked against below, are modified.
+	//
+	// Special case: if a private network is being used (no genesis and also no
+	// mainnet hash in the database), we must not apply the `configOrDefault`
+	// chain config as that would be AllProtocolChanges (applying any new fork
+	// on top of an existing private network genesis block). In that case, only
+	// apply the overrides.
+	if genesis == nil {
+		if storedcfg != nil && stored != params.MainnetGenesisHash {
+			newcfg = storedcfg
+		}
+		cpy := *newcfg
+		newcfg = &cpy
+	}
 	applyOverrides(newcfg)
 	if err := newcfg.CheckConfigForkOrder(); err != nil {
 		return newcfg, common.Hash{}, err
 	}
-	storedcfg := rawdb.ReadChainConfig(db, stored)
 	if storedcfg == nil {
 		log.Warn("Found genesis block without chain config")
 		rawdb.WriteChainConfig(db, stored, newcfg)
 		return newcfg, stored, nil
 	}
 	storedData, _ := json.Marshal(storedcfg)
-	// Special case: if a private network is being used (no genesis and also no
-	// mainnet hash in the database), we must not apply the `configOrDefault`
-	// chain config as that would be AllProtocolChanges (applying any new fork
-	// on top of an existing private network genesis block). In that case, only
-	// apply the overrides.
-	if genesis == nil && stored != params.MainnetGenesisHash {
-		newcfg = storedcfg
-		applyOverrides(newcfg)
-	}
 	// Check config compatibility and write the config. Compatibility errors
 	// are returned to the caller unless we're already at block zero.
 	head := rawdb.ReadHeadHeader(db)
 	if head == nil {
 		return newcfg, stored, errors.New("missing head header")
 	}
+	// CHANGE(immutable): Forks which already activated must not be rescheduled,
+	// unless the chain is still at its genesis block
+	if overrides != nil && head.Number.Uint64() != 0 {
+		if err := checkPassedForks(storedcfg, overrides.OverrideForks, head.Time); err != nil {
+			return newcfg, stored, err
+		}
+	}
 	compatErr := storedcfg.CheckCompatible(newcfg, head.Number.Uint64(), head.Time)
 	if compatErr != nil && ((head.Number.Uint64() != 0 && compatErr.RewindToBlock != 0) || (head.Time != 0 && compatErr.RewindToTime != 0)) {
 		return newcfg, stored, compatErr
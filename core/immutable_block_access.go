@@ -0,0 +1,173 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// blockAccessViolationMeter counts the imported transactions violating the
+	// access-control policy, whether their blocks are rejected or not.
+	blockAccessViolationMeter = metrics.NewRegisteredMeter("chain/accesscontrol/violations", nil)
+	// blockAccessRejectMeter counts the blocks rejected by the access-control policy.
+	blockAccessRejectMeter = metrics.NewRegisteredMeter("chain/accesscontrol/rejected", nil)
+
+	// ErrBlockAccessViolation is returned when an imported block contains
+	// transactions violating the node's access-control policy.
+	ErrBlockAccessViolation = errors.New("block contains transactions violating the access-control policy")
+)
+
+// AccessPolicy decides whether a transaction may be included in a block,
+// typically backed by the transaction pool's access-control lists.
+type AccessPolicy interface {
+	// CheckTransactionAccess returns an error if the transaction violates the policy.
+	CheckTransactionAccess(sender common.Address, tx *types.Transaction) error
+}
+
+// AccessPolicyMode selects how violations of the access-control policy found
+// in imported blocks are handled.
+type AccessPolicyMode string
+
+const (
+	// AccessPolicyOff disables the enforcement of the policy on imported blocks.
+	AccessPolicyOff AccessPolicyMode = ""
+	// AccessPolicyLog accepts violating blocks, only reporting them.
+	AccessPolicyLog AccessPolicyMode = "log"
+	// AccessPolicyReject rejects violating blocks as invalid. Rejected blocks are
+	// quarantined in the bad block store, retrievable through debug_getBadBlocks.
+	AccessPolicyReject AccessPolicyMode = "reject"
+)
+
+// ParseAccessPolicyMode parses the textual representation of a policy mode.
+func ParseAccessPolicyMode(mode string) (AccessPolicyMode, error) {
+	switch m := AccessPolicyMode(mode); m {
+	case AccessPolicyOff, AccessPolicyLog, AccessPolicyReject:
+		return m, nil
+	default:
+		return AccessPolicyOff, fmt.Errorf("invalid block access policy mode %q, want %q or %q", mode, AccessPolicyLog, AccessPolicyReject)
+	}
+}
+
+// blockAccessPolicy is an access-control policy enforced on imported blocks.
+type blockAccessPolicy struct {
+	policy AccessPolicy
+	mode   AccessPolicyMode
+
+	fromNumber *uint64 // First block checked, if configured
+	fromTime   uint64  // Otherwise, blocks sealed before the policy was enabled are not checked
+
+	skipped     atomic.Uint64 // Imported blocks not checked because of the cutoff
+	skipsLogged time.Time     // Last time the number of skipped blocks was reported
+	skipsLock   sync.Mutex
+}
+
+// applies reports whether the policy is enforced on the block, counting the
+// blocks skipped because they precede the cutoff.
+func (p *blockAccessPolicy) applies(block *types.Block) bool {
+	if p.fromNumber != nil {
+		if block.NumberU64() >= *p.fromNumber {
+			return true
+		}
+	} else if block.Time() >= p.fromTime {
+		return true
+	}
+	skipped := p.skipped.Add(1)
+
+	p.skipsLock.Lock()
+	defer p.skipsLock.Unlock()
+	if time.Since(p.skipsLogged) >= 8*time.Second {
+		p.skipsLogged = time.Now()
+		log.Info("Skipping access-control policy on blocks before cutoff", "skipped", skipped, "number", block.Number(), "hash", block.Hash())
+	}
+	return false
+}
+
+// SetAccessPolicy enables the enforcement of the access-control policy on
+// imported blocks, or disables it if mode is AccessPolicyOff.
+//
+// If from is set, only the blocks numbered from it onwards are checked.
+// Otherwise only blocks with a timestamp after the policy is enabled are, so
+// that historical blocks, sealed before the current lists were in force, can
+// still be synced. The number of skipped blocks is reported in the logs.
+func (bc *BlockChain) SetAccessPolicy(policy AccessPolicy, mode AccessPolicyMode, from *uint64) {
+	if policy == nil || mode == AccessPolicyOff {
+		bc.accessPolicy.Store(nil)
+		return
+	}
+	p := &blockAccessPolicy{
+		policy:     policy,
+		mode:       mode,
+		fromNumber: from,
+		fromTime:   uint64(time.Now().Unix()),
+	}
+	bc.accessPolicy.Store(p)
+	if from != nil {
+		log.Info("Enforcing access-control policy on imported blocks", "mode", mode, "from", *from)
+	} else {
+		log.Info("Enforcing access-control policy on imported blocks", "mode", mode, "from", time.Unix(int64(p.fromTime), 0))
+	}
+}
+
+// checkAccessPolicy checks every transaction of the block against the access
+// control policy, if enabled. All violations are logged with the block's
+// signer; the block is rejected only in AccessPolicyReject mode.
+func (bc *BlockChain) checkAccessPolicy(block *types.Block) error {
+	p := bc.accessPolicy.Load()
+	if p == nil || !p.applies(block) {
+		return nil
+	}
+	var (
+		signer     = types.MakeSigner(bc.chainConfig, block.Number(), block.Time())
+		violations []common.Hash
+	)
+	for _, tx := range block.Transactions() {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			// Invalid signatures are rejected by the state processor
+			continue
+		}
+		if err := p.policy.CheckTransactionAccess(sender, tx); err != nil {
+			violations = append(violations, tx.Hash())
+			log.Warn("Imported block contains unauthorized transaction", "number", block.Number(), "hash", block.Hash(),
+				"tx", tx.Hash(), "from", sender, "to", tx.To(), "err", err)
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	blockAccessViolationMeter.Mark(int64(len(violations)))
+
+	author, _ := bc.engine.Author(block.Header())
+	log.Error("Imported block violates access-control policy", "number", block.Number(), "hash", block.Hash(),
+		"author", author, "txs", violations, "mode", p.mode)
+
+	if p.mode != AccessPolicyReject {
+		return nil
+	}
+	blockAccessRejectMeter.Mark(1)
+	return fmt.Errorf("%w: %d transactions %v", ErrBlockAccessViolation, len(violations), violations)
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// senderBlockingPolicy is an AccessPolicy blocking a single sender.
+type senderBlockingPolicy struct {
+	blocked common.Address
+}
+
+func (p *senderBlockingPolicy) CheckTransactionAccess(sender common.Address, tx *types.Transaction) error {
+	if sender == p.blocked {
+		return errors.New("sender is blocked")
+	}
+	return nil
+}
+
+func TestImmutableBlockAccessPolicy(t *testing.T) {
+	tests := []struct {
+		mode      AccessPolicyMode
+		expectErr bool
+	}{
+		{mode: AccessPolicyOff, expectErr: false},
+		{mode: AccessPolicyLog, expectErr: false},
+		{mode: AccessPolicyReject, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(string(test.mode), func(t *testing.T) {
+			var (
+				key, _  = crypto.GenerateKey()
+				address = crypto.PubkeyToAddress(key.PublicKey)
+				gspec   = &Genesis{
+					Config:  params.TestChainConfig,
+					Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+					BaseFee: big.NewInt(params.InitialBaseFee),
+				}
+				signer = types.LatestSigner(gspec.Config)
+			)
+			_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, func(i int, b *BlockGen) {
+				tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+				b.AddTx(tx)
+			})
+			chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create chain: %v", err)
+			}
+			defer chain.Stop()
+
+			// Generated blocks are timestamped in the past, check them anyway
+			from := uint64(1)
+			chain.SetAccessPolicy(&senderBlockingPolicy{blocked: address}, test.mode, &from)
+			_, err = chain.InsertChain(blocks)
+			if test.expectErr {
+				if !errors.Is(err, ErrBlockAccessViolation) {
+					t.Fatalf("expected access violation, got: %v", err)
+				}
+				if chain.CurrentBlock().Number.Uint64() != 0 {
+					t.Fatalf("violating block imported, head %d", chain.CurrentBlock().Number)
+				}
+				if bad := rawdb.ReadAllBadBlocks(chain.db); len(bad) != 1 || bad[0].Hash() != blocks[0].Hash() {
+					t.Fatalf("violating block not quarantined: %d bad blocks", len(bad))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to insert chain: %v", err)
+			}
+			if chain.CurrentBlock().Number.Uint64() != 2 {
+				t.Fatalf("unexpected head %d", chain.CurrentBlock().Number)
+			}
+		})
+	}
+}
+
+func TestImmutableBlockAccessPolicy_Cutoff(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0x01}, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+	})
+	from := uint64(3)
+	tests := []struct {
+		name    string
+		from    *uint64
+		head    uint64
+		skipped uint64
+	}{
+		// Generated blocks are timestamped in the past, before the policy is enabled
+		{name: "start time", from: nil, head: 3, skipped: 3},
+		{name: "block number", from: &from, head: 2, skipped: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create chain: %v", err)
+			}
+			defer chain.Stop()
+
+			chain.SetAccessPolicy(&senderBlockingPolicy{blocked: address}, AccessPolicyReject, test.from)
+			chain.InsertChain(blocks)
+			if head := chain.CurrentBlock().Number.Uint64(); head != test.head {
+				t.Errorf("head mismatch: have %d, want %d", head, test.head)
+			}
+			if skipped := chain.accessPolicy.Load().skipped.Load(); skipped != test.skipped {
+				t.Errorf("skipped blocks mismatch: have %d, want %d", skipped, test.skipped)
+			}
+		})
+	}
+}
+
+func TestImmutableParseAccessPolicyMode(t *testing.T) {
+	for _, mode := range []string{"", "log", "reject"} {
+		if _, err := ParseAccessPolicyMode(mode); err != nil {
+			t.Errorf("failed to parse %q: %v", mode, err)
+		}
+	}
+	if _, err := ParseAccessPolicyMode("quarantine"); err == nil {
+		t.Errorf("expected error parsing unknown mode")
+	}
+}
@@ -18,8 +18,12 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // ImmutableGenesisBlock returns the immutable genesis block for the specified network (mainnet, testnet, devnet).
@@ -35,3 +39,50 @@ func ImmutableGenesisBlock(network string) *Genesis {
 	}
 	return genesis
 }
+
+// validateForkOverrides checks that every scheduled fork is a timestamp based
+// fork of the chain config.
+func validateForkOverrides(overrides *ChainOverrides) error {
+	if overrides == nil {
+		return nil
+	}
+	for name := range overrides.OverrideForks {
+		if _, err := new(params.ChainConfig).ForkTime(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyForkOverrides sets the scheduled fork times on the chain config.
+func applyForkOverrides(config *params.ChainConfig, forks map[string]uint64) {
+	for name, time := range forks {
+		if err := config.SetForkTime(name, time); err != nil {
+			panic(err) // Validated by validateForkOverrides
+		}
+	}
+}
+
+// checkPassedForks returns an error if the schedule changes the time of a fork
+// which activated at or before the head time, or activates a fork in the past.
+func checkPassedForks(stored *params.ChainConfig, forks map[string]uint64, headTime uint64) error {
+	names := maps.Keys(forks)
+	slices.Sort(names)
+
+	for _, name := range names {
+		scheduled := forks[name]
+		current, err := stored.ForkTime(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case current != nil && *current == scheduled:
+			continue
+		case current != nil && *current <= headTime:
+			return fmt.Errorf("fork %s activated at %d and cannot be rescheduled to %d", name, *current, scheduled)
+		case scheduled <= headTime:
+			return fmt.Errorf("fork %s cannot be scheduled at %d, before the head time %d", name, scheduled, headTime)
+		}
+	}
+	return nil
+}
@@ -25,6 +25,9 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -169,3 +172,49 @@ func TestImmutableTestCheckCompatible(t *testing.T) {
 		})
 	}
 }
+
+func TestImmutableGenesis_ForkOverrides(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	cancun := uint64(settings.DevnetCancunFork.Unix())
+
+	// Unknown forks are rejected
+	_, _, err := SetupGenesisBlockWithOverride(db, tdb, ImmutableGenesisBlock("devnet"), &ChainOverrides{OverrideForks: map[string]uint64{"unknown": 1}})
+	assert.Error(t, err)
+
+	// The schedule is applied to the chain config
+	config, hash, err := SetupGenesisBlockWithOverride(db, tdb, ImmutableGenesisBlock("devnet"), &ChainOverrides{OverrideForks: map[string]uint64{"cancun": cancun}})
+	assert.NoError(t, err)
+	assert.Equal(t, cancun, *config.CancunTime)
+
+	// Move the head past the Cancun fork
+	head := &types.Header{ParentHash: hash, Number: big.NewInt(1), Time: cancun + 100}
+	rawdb.WriteHeader(db, head)
+	rawdb.WriteHeadHeaderHash(db, head.Hash())
+
+	setup := func(forks map[string]uint64) error {
+		_, _, err := SetupGenesisBlockWithOverride(db, tdb, ImmutableGenesisBlock("devnet"), &ChainOverrides{OverrideForks: forks})
+		return err
+	}
+	// Passed forks can't be rescheduled, future forks can
+	assert.NoError(t, setup(map[string]uint64{"cancun": cancun}))
+	assert.Error(t, setup(map[string]uint64{"cancun": cancun + 1000}))
+	assert.Error(t, setup(map[string]uint64{"prague": cancun + 50}))
+	assert.NoError(t, setup(map[string]uint64{"cancun": cancun, "prague": cancun + 1000}))
+	assert.NoError(t, setup(map[string]uint64{"cancun": cancun, "prague": cancun + 2000}))
+
+	// The same holds without a genesis, when the stored config is overridden
+	setupStored := func(forks map[string]uint64) error {
+		_, _, err := SetupGenesisBlockWithOverride(db, tdb, nil, &ChainOverrides{OverrideForks: forks})
+		return err
+	}
+	assert.NoError(t, setupStored(map[string]uint64{"cancun": cancun}))
+	assert.Error(t, setupStored(map[string]uint64{"cancun": cancun + 1000}))
+	assert.Error(t, setupStored(map[string]uint64{"prague": cancun + 50}))
+	assert.NoError(t, setupStored(map[string]uint64{"prague": cancun + 3000}))
+
+	config, _, err = SetupGenesisBlockWithOverride(db, tdb, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, cancun, *config.CancunTime)
+	assert.Equal(t, cancun+3000, *config.PragueTime)
+}
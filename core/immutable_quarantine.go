@@ -0,0 +1,260 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// blockOriginCacheLimit is the number of recently delivered blocks whose
+	// origin is remembered for attribution.
+	blockOriginCacheLimit = 1024
+
+	// maxQuarantineDepth is the maximum number of blocks per branch that are
+	// stored when a fork is quarantined.
+	maxQuarantineDepth = 128
+)
+
+var blockQuarantineMeter = metrics.NewRegisteredMeter("chain/reorg/quarantined", nil)
+
+// RecordBlockOrigin remembers which component and peer delivered the given
+// blocks, so that they can be attributed if they are later quarantined.
+func (bc *BlockChain) RecordBlockOrigin(source, peer string, blocks types.Blocks) {
+	for _, block := range blocks {
+		hash := block.Hash()
+		bc.blockOrigins.Add(hash, rawdb.BlockOrigin{Hash: hash, Source: source, Peer: peer})
+	}
+}
+
+// quarantineFork persists a branch that was refused by the reorg invariant
+// together with the canonical branch it attempted to replace. The chains are
+// given as accumulated by reorg: both in descending order, with oldBlock and
+// newBlock being the next blocks to compare at equal height, and newHead the
+// head of the rejected branch.
+//
+// At most maxQuarantineDepth blocks per branch are stored, the most recent
+// ones. If the common ancestor lies deeper, the fork is marked as truncated
+// and its common ancestor left unset.
+func (bc *BlockChain) quarantineFork(oldChain, newChain types.Blocks, oldBlock, newBlock, newHead *types.Block) {
+	truncated := len(oldChain) > maxQuarantineDepth || len(newChain) > maxQuarantineDepth
+	if len(oldChain) > maxQuarantineDepth {
+		oldChain = oldChain[:maxQuarantineDepth]
+	}
+	if len(newChain) > maxQuarantineDepth {
+		newChain = newChain[:maxQuarantineDepth]
+	}
+	oldChain, newChain = slices.Clone(oldChain), slices.Clone(newChain)
+	for !truncated && oldBlock.Hash() != newBlock.Hash() {
+		if len(oldChain) >= maxQuarantineDepth || len(newChain) >= maxQuarantineDepth {
+			truncated = true
+			break
+		}
+		oldChain = append(oldChain, oldBlock)
+		newChain = append(newChain, newBlock)
+
+		oldBlock = bc.GetBlock(oldBlock.ParentHash(), oldBlock.NumberU64()-1)
+		newBlock = bc.GetBlock(newBlock.ParentHash(), newBlock.NumberU64()-1)
+		if oldBlock == nil || newBlock == nil {
+			log.Error("Failed to find common ancestor of quarantined fork")
+			return
+		}
+	}
+	slices.Reverse(oldChain)
+	slices.Reverse(newChain)
+
+	// If the old branch alone is deeper than can be stored, reorg collected no
+	// blocks of the new branch. Store its head, which identifies the fork.
+	if len(newChain) == 0 {
+		newChain = types.Blocks{newHead}
+	}
+
+	fork := &rawdb.QuarantinedFork{
+		Time:      uint64(time.Now().Unix()),
+		Canonical: oldChain,
+		Side:      newChain,
+		Truncated: truncated,
+	}
+	if truncated {
+		log.Warn("Quarantined fork truncated", "depth", maxQuarantineDepth)
+	} else {
+		fork.CommonHash, fork.CommonNumber = newBlock.Hash(), newBlock.NumberU64()
+	}
+	for _, block := range newChain {
+		if origin, ok := bc.blockOrigins.Get(block.Hash()); ok {
+			fork.Origins = append(fork.Origins, origin)
+		}
+	}
+	// A fork that keeps growing is quarantined again on every new block, only
+	// retain the longest version of it.
+	for _, block := range newChain[:len(newChain)-1] {
+		if rawdb.HasQuarantinedFork(bc.db, block.Hash()) {
+			rawdb.DeleteQuarantinedFork(bc.db, block.Hash())
+		}
+	}
+	rawdb.WriteQuarantinedFork(bc.db, fork)
+	blockQuarantineMeter.Mark(1)
+
+	log.Warn("Quarantined competing fork", "id", fork.ID(), "common", fork.CommonNumber, "truncated", fork.Truncated,
+		"canonical", len(fork.Canonical), "side", len(fork.Side), "origins", len(fork.Origins))
+}
+
+// ForkBlock summarises one block of a quarantined fork branch.
+type ForkBlock struct {
+	Number       hexutil.Uint64  `json:"number"`
+	Hash         common.Hash     `json:"hash"`
+	ParentHash   common.Hash     `json:"parentHash"`
+	Time         hexutil.Uint64  `json:"timestamp"`
+	Difficulty   *hexutil.Big    `json:"difficulty"`
+	Signer       *common.Address `json:"signer"`
+	Transactions []common.Hash   `json:"transactions"`
+}
+
+// ForkHeight pairs the canonical and rejected blocks at one height.
+type ForkHeight struct {
+	Number    hexutil.Uint64 `json:"number"`
+	Canonical *ForkBlock     `json:"canonical"`
+	Side      *ForkBlock     `json:"side"`
+
+	// Equivocation is set if both blocks at this height were sealed by the
+	// same signer.
+	Equivocation bool `json:"equivocation"`
+}
+
+// ForkDiff is the comparison of the two branches of a quarantined fork.
+type ForkDiff struct {
+	ID           common.Hash         `json:"id"`
+	Time         hexutil.Uint64      `json:"quarantinedAt"`
+	CommonNumber hexutil.Uint64      `json:"commonNumber"`
+	CommonHash   common.Hash         `json:"commonHash"`
+	Truncated    bool                `json:"truncated"`
+	Origins      []rawdb.BlockOrigin `json:"origins"`
+	Heights      []*ForkHeight       `json:"heights"`
+
+	// DoubleSigners lists the signers which sealed different blocks at the
+	// same height on the two branches.
+	DoubleSigners []common.Address `json:"doubleSigners"`
+
+	CanonicalOnlyTxs []common.Hash `json:"canonicalOnlyTransactions"`
+	SideOnlyTxs      []common.Hash `json:"sideOnlyTransactions"`
+	SharedTxs        []common.Hash `json:"sharedTransactions"`
+}
+
+// DiffQuarantinedFork compares the headers, signers and transactions of both
+// branches of a quarantined fork. The author function recovers the sealer of a
+// header, usually the consensus engine's Author method.
+func DiffQuarantinedFork(fork *rawdb.QuarantinedFork, author func(*types.Header) (common.Address, error)) *ForkDiff {
+	diff := &ForkDiff{
+		ID:           fork.ID(),
+		Time:         hexutil.Uint64(fork.Time),
+		CommonNumber: hexutil.Uint64(fork.CommonNumber),
+		CommonHash:   fork.CommonHash,
+		Truncated:    fork.Truncated,
+		Origins:      fork.Origins,
+	}
+	heights := make(map[uint64]*ForkHeight)
+	height := func(number uint64) *ForkHeight {
+		if h, ok := heights[number]; ok {
+			return h
+		}
+		h := &ForkHeight{Number: hexutil.Uint64(number)}
+		heights[number] = h
+		diff.Heights = append(diff.Heights, h)
+		return h
+	}
+	canonicalTxs := make(map[common.Hash]struct{})
+	for _, block := range fork.Canonical {
+		height(block.NumberU64()).Canonical = newForkBlock(block, author)
+		for _, tx := range block.Transactions() {
+			canonicalTxs[tx.Hash()] = struct{}{}
+		}
+	}
+	sideTxs := make(map[common.Hash]struct{})
+	for _, block := range fork.Side {
+		height(block.NumberU64()).Side = newForkBlock(block, author)
+		for _, tx := range block.Transactions() {
+			hash := tx.Hash()
+			sideTxs[hash] = struct{}{}
+			if _, ok := canonicalTxs[hash]; ok {
+				diff.SharedTxs = append(diff.SharedTxs, hash)
+			} else {
+				diff.SideOnlyTxs = append(diff.SideOnlyTxs, hash)
+			}
+		}
+	}
+	for _, block := range fork.Canonical {
+		for _, tx := range block.Transactions() {
+			if _, ok := sideTxs[tx.Hash()]; !ok {
+				diff.CanonicalOnlyTxs = append(diff.CanonicalOnlyTxs, tx.Hash())
+			}
+		}
+	}
+	slices.SortFunc(diff.Heights, func(a, b *ForkHeight) int {
+		switch {
+		case a.Number < b.Number:
+			return -1
+		case a.Number > b.Number:
+			return 1
+		}
+		return 0
+	})
+	seen := make(map[common.Address]struct{})
+	for _, h := range diff.Heights {
+		if h.Canonical == nil || h.Side == nil || h.Canonical.Signer == nil || h.Side.Signer == nil {
+			continue
+		}
+		if *h.Canonical.Signer != *h.Side.Signer {
+			continue
+		}
+		h.Equivocation = true
+		if _, ok := seen[*h.Side.Signer]; !ok {
+			seen[*h.Side.Signer] = struct{}{}
+			diff.DoubleSigners = append(diff.DoubleSigners, *h.Side.Signer)
+		}
+	}
+	return diff
+}
+
+func newForkBlock(block *types.Block, author func(*types.Header) (common.Address, error)) *ForkBlock {
+	fb := &ForkBlock{
+		Number:     hexutil.Uint64(block.NumberU64()),
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+		Time:       hexutil.Uint64(block.Time()),
+		Difficulty: (*hexutil.Big)(block.Difficulty()),
+	}
+	if author != nil {
+		if signer, err := author(block.Header()); err == nil {
+			fb.Signer = &signer
+		} else {
+			log.Debug("Failed to recover fork block signer", "hash", block.Hash(), "err", err)
+		}
+	}
+	fb.Transactions = make([]common.Hash, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		fb.Transactions = append(fb.Transactions, tx.Hash())
+	}
+	return fb
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestImmutableReorgQuarantine(t *testing.T) {
+	genDb, _, blockchain, err := newCanonicalWithInvariants(ethash.NewFaker(), 0, full, scheme)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	genesis := blockchain.GetBlockByHash(blockchain.CurrentBlock().Hash())
+	canonical, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 4, func(i int, b *BlockGen) {
+		b.OffsetTime(15)
+	})
+	side, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 10, func(i int, b *BlockGen) {
+		b.OffsetTime(1)
+	})
+	if _, err := blockchain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	blockchain.RecordBlockOrigin("downloader", "peer-1", side)
+	if _, err := blockchain.InsertChain(side); !errors.Is(err, ErrReorgAttempted) {
+		t.Fatalf("expected reorg error but got: %v", err)
+	}
+
+	forks := rawdb.ReadAllQuarantinedForks(blockchain.db)
+	if len(forks) != 1 {
+		t.Fatalf("quarantined fork count mismatch: have %d, want 1", len(forks))
+	}
+	fork := forks[0]
+	if fork.CommonHash != genesis.Hash() || fork.CommonNumber != 0 {
+		t.Fatalf("common ancestor mismatch: have %d %x, want 0 %x", fork.CommonNumber, fork.CommonHash, genesis.Hash())
+	}
+	if len(fork.Canonical) != len(canonical) {
+		t.Fatalf("canonical branch length mismatch: have %d, want %d", len(fork.Canonical), len(canonical))
+	}
+	for i, block := range fork.Canonical {
+		if block.Hash() != canonical[i].Hash() {
+			t.Errorf("canonical block %d mismatch: have %x, want %x", i, block.Hash(), canonical[i].Hash())
+		}
+	}
+	if len(fork.Side) == 0 {
+		t.Fatalf("side branch is empty")
+	}
+	for i, block := range fork.Side {
+		if block.Hash() != side[i].Hash() {
+			t.Errorf("side block %d mismatch: have %x, want %x", i, block.Hash(), side[i].Hash())
+		}
+	}
+	if len(fork.Origins) != len(fork.Side) || fork.Origins[0].Peer != "peer-1" || fork.Origins[0].Source != "downloader" {
+		t.Fatalf("unexpected block origins: %v", fork.Origins)
+	}
+	if stored := rawdb.ReadQuarantinedFork(blockchain.db, fork.ID()); stored == nil {
+		t.Fatalf("quarantined fork %x not found by id", fork.ID())
+	}
+
+	// Both branches are sealed by the zero coinbase, so every shared height is
+	// an equivocation.
+	diff := DiffQuarantinedFork(fork, blockchain.Engine().Author)
+	want := len(fork.Canonical)
+	if len(fork.Side) > want {
+		want = len(fork.Side)
+	}
+	if len(diff.Heights) != want {
+		t.Fatalf("height count mismatch: have %d, want %d", len(diff.Heights), want)
+	}
+	for _, h := range diff.Heights {
+		if want := h.Canonical != nil && h.Side != nil; h.Equivocation != want {
+			t.Errorf("height %d equivocation mismatch: have %v, want %v", h.Number, h.Equivocation, want)
+		}
+	}
+	if len(diff.DoubleSigners) != 1 || diff.DoubleSigners[0] != (common.Address{}) {
+		t.Fatalf("unexpected double signers: %v", diff.DoubleSigners)
+	}
+}
+
+func TestImmutableReorgQuarantine_Truncated(t *testing.T) {
+	genDb, _, blockchain, err := newCanonicalWithInvariants(ethash.NewFaker(), 0, full, scheme)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	genesis := blockchain.GetBlockByHash(blockchain.CurrentBlock().Hash())
+	canonical, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, maxQuarantineDepth+10, func(i int, b *BlockGen) {
+		b.OffsetTime(15)
+	})
+	side, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, maxQuarantineDepth+20, func(i int, b *BlockGen) {
+		b.OffsetTime(1)
+	})
+	if _, err := blockchain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	if _, err := blockchain.InsertChain(side); !errors.Is(err, ErrReorgAttempted) {
+		t.Fatalf("expected reorg error but got: %v", err)
+	}
+	forks := rawdb.ReadAllQuarantinedForks(blockchain.db)
+	if len(forks) != 1 {
+		t.Fatalf("quarantined fork count mismatch: have %d, want 1", len(forks))
+	}
+	fork := forks[0]
+	if !fork.Truncated {
+		t.Fatalf("fork deeper than %d blocks not marked as truncated", maxQuarantineDepth)
+	}
+	if fork.CommonHash != (common.Hash{}) || fork.CommonNumber != 0 {
+		t.Fatalf("truncated fork has common ancestor %d %x", fork.CommonNumber, fork.CommonHash)
+	}
+	for name, branch := range map[string]types.Blocks{"canonical": fork.Canonical, "side": fork.Side} {
+		if len(branch) == 0 || len(branch) > maxQuarantineDepth {
+			t.Fatalf("%s branch length out of bounds: %d", name, len(branch))
+		}
+		for i := 1; i < len(branch); i++ {
+			if branch[i].ParentHash() != branch[i-1].Hash() {
+				t.Fatalf("%s branch not contiguous at %d", name, i)
+			}
+		}
+	}
+	if head := fork.Canonical[len(fork.Canonical)-1]; head.Hash() != canonical[len(canonical)-1].Hash() {
+		t.Fatalf("canonical head mismatch: have %x, want %x", head.Hash(), canonical[len(canonical)-1].Hash())
+	}
+	if diff := DiffQuarantinedFork(fork, blockchain.Engine().Author); !diff.Truncated {
+		t.Fatalf("fork diff not marked as truncated")
+	}
+}
+
+func TestImmutableReorgQuarantine_LongerOldBranch(t *testing.T) {
+	genDb, _, blockchain, err := newCanonicalWithInvariants(ethash.NewFaker(), 0, full, scheme)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	genesis := blockchain.GetBlockByHash(blockchain.CurrentBlock().Hash())
+	canonical, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, maxQuarantineDepth+10, func(i int, b *BlockGen) {
+		b.OffsetTime(15)
+	})
+	side, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 5, func(i int, b *BlockGen) {
+		b.OffsetTime(1)
+	})
+	if _, err := blockchain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert canonical chain: %v", err)
+	}
+	// The short branch has a lower total difficulty than the canonical one, so
+	// it is stored as a side chain. Reorg onto it directly, as done for a clique
+	// branch of in-turn blocks outweighing a longer branch of out-of-turn ones.
+	if _, err := blockchain.InsertChain(side); err != nil {
+		t.Fatalf("failed to insert side chain: %v", err)
+	}
+	head := side[len(side)-1]
+	if err := blockchain.reorg(blockchain.CurrentBlock(), head); !errors.Is(err, ErrReorgAttempted) {
+		t.Fatalf("expected reorg error but got: %v", err)
+	}
+	forks := rawdb.ReadAllQuarantinedForks(blockchain.db)
+	if len(forks) != 1 {
+		t.Fatalf("quarantined fork count mismatch: have %d, want 1", len(forks))
+	}
+	fork := forks[0]
+	if !fork.Truncated {
+		t.Fatalf("fork deeper than %d blocks not marked as truncated", maxQuarantineDepth)
+	}
+	if fork.ID() != head.Hash() {
+		t.Fatalf("fork id mismatch: have %x, want %x", fork.ID(), head.Hash())
+	}
+	if len(fork.Canonical) != maxQuarantineDepth {
+		t.Fatalf("canonical branch length mismatch: have %d, want %d", len(fork.Canonical), maxQuarantineDepth)
+	}
+	if stored := rawdb.ReadQuarantinedFork(blockchain.db, head.Hash()); stored == nil {
+		t.Fatalf("quarantined fork %x not found by head hash", head.Hash())
+	}
+}
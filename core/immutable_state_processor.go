@@ -0,0 +1,26 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+// ExecutionVetoer is implemented by EVM loggers that may refuse a transaction
+// once it has executed. The veto is consulted before the transaction's state
+// changes are finalised, so callers can still revert them to a snapshot taken
+// before the transaction.
+type ExecutionVetoer interface {
+	// Veto returns a non-nil error if the transaction must not be applied.
+	Veto() error
+}
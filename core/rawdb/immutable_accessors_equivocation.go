@@ -0,0 +1,66 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// cliqueEquivocationKey = cliqueEquivocationPrefix + num (uint64 big endian) + signer + hash
+func cliqueEquivocationKey(number uint64, signer common.Address, hash common.Hash) []byte {
+	key := make([]byte, 0, len(cliqueEquivocationPrefix)+8+common.AddressLength+common.HashLength)
+	key = append(key, cliqueEquivocationPrefix...)
+	key = binary.BigEndian.AppendUint64(key, number)
+	key = append(key, signer.Bytes()...)
+	return append(key, hash.Bytes()...)
+}
+
+// HasCliqueEquivocation checks whether the evidence of the signer sealing the
+// block with the given hash along with another block at the same height is
+// stored.
+func HasCliqueEquivocation(db ethdb.KeyValueReader, number uint64, signer common.Address, hash common.Hash) bool {
+	has, err := db.Has(cliqueEquivocationKey(number, signer, hash))
+	return err == nil && has
+}
+
+// ReadCliqueEquivocations retrieves the RLP encoded evidence of all stored
+// clique equivocations, ordered by block number.
+func ReadCliqueEquivocations(db ethdb.Iteratee) [][]byte {
+	it := db.NewIterator(cliqueEquivocationPrefix, nil)
+	defer it.Release()
+
+	var blobs [][]byte
+	for it.Next() {
+		if len(it.Key()) != len(cliqueEquivocationPrefix)+8+common.AddressLength+common.HashLength {
+			continue
+		}
+		blobs = append(blobs, common.CopyBytes(it.Value()))
+	}
+	return blobs
+}
+
+// WriteCliqueEquivocation stores the RLP encoded evidence of the signer sealing
+// the block with the given hash along with another block at the same height.
+func WriteCliqueEquivocation(db ethdb.KeyValueWriter, number uint64, signer common.Address, hash common.Hash, blob []byte) {
+	if err := db.Put(cliqueEquivocationKey(number, signer, hash), blob); err != nil {
+		log.Crit("Failed to store clique equivocation", "err", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
+)
+
+// QuarantinedForksToKeep is the maximum number of quarantined forks retained.
+// When the limit is exceeded the oldest entries are dropped.
+const QuarantinedForksToKeep = 32
+
+// BlockOrigin attributes a block to the component and peer that delivered it.
+type BlockOrigin struct {
+	Hash   common.Hash `json:"hash"`
+	Source string      `json:"source"` // Component which handed the block to the chain (e.g. fetcher, downloader)
+	Peer   string      `json:"peer"`   // Identifier of the remote peer, empty if unknown
+}
+
+// QuarantinedFork is a competing branch which was refused by the reorg
+// invariant, stored together with the canonical branch it tried to replace.
+type QuarantinedFork struct {
+	Time         uint64         // Unix time at which the fork was quarantined
+	CommonHash   common.Hash    // Hash of the last block shared by both branches, unset if truncated
+	CommonNumber uint64         // Number of the last block shared by both branches, unset if truncated
+	Canonical    []*types.Block // Canonical blocks above the common ancestor, ascending
+	Side         []*types.Block // Rejected blocks above the common ancestor, ascending
+	Origins      []BlockOrigin  // Known origins of the rejected blocks
+
+	// Truncated is set if the branches were deeper than could be stored, in
+	// which case they hold only the most recent blocks.
+	Truncated bool `rlp:"optional"`
+}
+
+// ID returns the identifier of the quarantined fork, which is the hash of the
+// rejected branch's head block.
+func (f *QuarantinedFork) ID() common.Hash {
+	if len(f.Side) == 0 {
+		return common.Hash{}
+	}
+	return f.Side[len(f.Side)-1].Hash()
+}
+
+// quarantinedForkKey = quarantinedForkPrefix + hash
+func quarantinedForkKey(hash common.Hash) []byte {
+	return append(quarantinedForkPrefix, hash.Bytes()...)
+}
+
+// quarantinedForkIndexKey = quarantinedForkIndexPrefix + time (uint64 big endian) + hash
+func quarantinedForkIndexKey(time uint64, hash common.Hash) []byte {
+	key := make([]byte, 0, len(quarantinedForkIndexPrefix)+8+common.HashLength)
+	key = append(key, quarantinedForkIndexPrefix...)
+	key = binary.BigEndian.AppendUint64(key, time)
+	return append(key, hash.Bytes()...)
+}
+
+// readQuarantinedForkIndex retrieves the index keys of all quarantined forks,
+// ordered by quarantine time with the oldest first.
+func readQuarantinedForkIndex(db ethdb.Iteratee) [][]byte {
+	it := db.NewIterator(quarantinedForkIndexPrefix, nil)
+	defer it.Release()
+
+	var keys [][]byte
+	for it.Next() {
+		if len(it.Key()) != len(quarantinedForkIndexPrefix)+8+common.HashLength {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(it.Key()))
+	}
+	return keys
+}
+
+// HasQuarantinedFork checks whether a quarantined fork whose rejected head has
+// the given hash is stored, without decoding it.
+func HasQuarantinedFork(db ethdb.KeyValueReader, hash common.Hash) bool {
+	has, err := db.Has(quarantinedForkKey(hash))
+	return err == nil && has
+}
+
+// ReadQuarantinedFork retrieves the quarantined fork whose rejected head has
+// the given hash.
+func ReadQuarantinedFork(db ethdb.KeyValueReader, hash common.Hash) *QuarantinedFork {
+	blob, err := db.Get(quarantinedForkKey(hash))
+	if err != nil || len(blob) == 0 {
+		return nil
+	}
+	fork := new(QuarantinedFork)
+	if err := rlp.DecodeBytes(blob, fork); err != nil {
+		log.Error("Invalid quarantined fork RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return fork
+}
+
+// ReadAllQuarantinedForks retrieves all quarantined forks in the database,
+// sorted by quarantine time with the most recent first.
+func ReadAllQuarantinedForks(db ethdb.Iteratee) []*QuarantinedFork {
+	it := db.NewIterator(quarantinedForkPrefix, nil)
+	defer it.Release()
+
+	var forks []*QuarantinedFork
+	for it.Next() {
+		if len(it.Key()) != len(quarantinedForkPrefix)+common.HashLength {
+			continue
+		}
+		fork := new(QuarantinedFork)
+		if err := rlp.DecodeBytes(it.Value(), fork); err != nil {
+			log.Error("Invalid quarantined fork RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		forks = append(forks, fork)
+	}
+	slices.SortStableFunc(forks, func(a, b *QuarantinedFork) int {
+		// Note: sorting in descending time order.
+		switch {
+		case a.Time > b.Time:
+			return -1
+		case a.Time < b.Time:
+			return 1
+		}
+		return 0
+	})
+	return forks
+}
+
+// WriteQuarantinedFork stores a quarantined fork. If the number of stored forks
+// exceeds QuarantinedForksToKeep, the oldest ones are dropped. Pruning walks the
+// time index only, so stored forks are never decoded on the write path.
+func WriteQuarantinedFork(db ethdb.KeyValueStore, fork *QuarantinedFork) {
+	id := fork.ID()
+	data, err := rlp.EncodeToBytes(fork)
+	if err != nil {
+		log.Crit("Failed to encode quarantined fork", "err", err)
+	}
+	if err := db.Put(quarantinedForkKey(id), data); err != nil {
+		log.Crit("Failed to write quarantined fork", "err", err)
+	}
+	index := quarantinedForkIndexKey(fork.Time, id)
+	if err := db.Put(index, nil); err != nil {
+		log.Crit("Failed to write quarantined fork index", "err", err)
+	}
+	// Drop index entries left behind by earlier writes of the same fork
+	var keys [][]byte
+	for _, key := range readQuarantinedForkIndex(db) {
+		if bytes.HasSuffix(key, id.Bytes()) && !bytes.Equal(key, index) {
+			deleteQuarantinedForkIndex(db, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	for i := 0; i < len(keys)-QuarantinedForksToKeep; i++ {
+		if err := db.Delete(quarantinedForkKey(common.BytesToHash(keys[i][len(keys[i])-common.HashLength:]))); err != nil {
+			log.Crit("Failed to delete quarantined fork", "err", err)
+		}
+		deleteQuarantinedForkIndex(db, keys[i])
+	}
+}
+
+// DeleteQuarantinedFork removes the quarantined fork with the given id.
+func DeleteQuarantinedFork(db ethdb.KeyValueStore, hash common.Hash) {
+	if err := db.Delete(quarantinedForkKey(hash)); err != nil {
+		log.Crit("Failed to delete quarantined fork", "err", err)
+	}
+	for _, key := range readQuarantinedForkIndex(db) {
+		if bytes.HasSuffix(key, hash.Bytes()) {
+			deleteQuarantinedForkIndex(db, key)
+		}
+	}
+}
+
+func deleteQuarantinedForkIndex(db ethdb.KeyValueWriter, key []byte) {
+	if err := db.Delete(key); err != nil {
+		log.Crit("Failed to delete quarantined fork index", "err", err)
+	}
+}
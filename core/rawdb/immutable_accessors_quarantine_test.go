@@ -0,0 +1,71 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func testQuarantinedFork(time uint64, number int64) *QuarantinedFork {
+	head := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number), Time: time})
+	return &QuarantinedFork{Time: time, Side: []*types.Block{head}}
+}
+
+func TestImmutableQuarantinedForkPruning(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var forks []*QuarantinedFork
+	for i := 0; i < QuarantinedForksToKeep; i++ {
+		fork := testQuarantinedFork(uint64(100+i), int64(i))
+		WriteQuarantinedFork(db, fork)
+		forks = append(forks, fork)
+	}
+	// Corrupt the oldest fork: pruning must drop it without decoding it
+	if err := db.Put(quarantinedForkKey(forks[0].ID()), []byte{0xff}); err != nil {
+		t.Fatal(err)
+	}
+	// Rewriting a fork with a newer time must not leave a stale index entry
+	forks[1].Time = 500
+	WriteQuarantinedFork(db, forks[1])
+	if have := len(readQuarantinedForkIndex(db)); have != QuarantinedForksToKeep {
+		t.Fatalf("index size mismatch: have %d, want %d", have, QuarantinedForksToKeep)
+	}
+	WriteQuarantinedFork(db, testQuarantinedFork(600, 1000))
+
+	if HasQuarantinedFork(db, forks[0].ID()) {
+		t.Fatal("oldest fork not pruned")
+	}
+	for _, fork := range forks[1:] {
+		if !HasQuarantinedFork(db, fork.ID()) {
+			t.Fatalf("fork %x pruned too early", fork.ID())
+		}
+	}
+	stored := ReadAllQuarantinedForks(db)
+	if len(stored) != QuarantinedForksToKeep {
+		t.Fatalf("stored forks mismatch: have %d, want %d", len(stored), QuarantinedForksToKeep)
+	}
+	if stored[0].Time != 600 || stored[1].ID() != forks[1].ID() {
+		t.Fatalf("unexpected order: %d %x", stored[0].Time, stored[1].ID())
+	}
+	DeleteQuarantinedFork(db, forks[1].ID())
+	if HasQuarantinedFork(db, forks[1].ID()) || len(readQuarantinedForkIndex(db)) != QuarantinedForksToKeep-1 {
+		t.Fatal("fork not fully deleted")
+	}
+}
@@ -138,6 +138,13 @@ var (
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
 	SyncCommitteeKey      = []byte("committee-") // bigEndian64(syncPeriod) -> serialized committee
 
+	// CHANGE(immutable): Side chains rejected by the reorg invariant are kept for inspection
+	quarantinedForkPrefix      = []byte("immutable-quarantine-")       // quarantinedForkPrefix + side head hash -> RLP(QuarantinedFork)
+	quarantinedForkIndexPrefix = []byte("immutable-quarantine-index-") // quarantinedForkIndexPrefix + bigEndian64(time) + side head hash -> nil
+
+	// CHANGE(immutable): Evidence of clique signers sealing two blocks at the same height
+	cliqueEquivocationPrefix = []byte("immutable-equivocation-") // cliqueEquivocationPrefix + num (uint64 big endian) + signer + hash -> RLP(clique.Equivocation)
+
 	preimageCounter    = metrics.NewRegisteredCounter("db/preimage/total", nil)
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
@@ -114,6 +114,12 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 	if err != nil {
 		return nil, err
 	}
+	// CHANGE(immutable): Let the tracer refuse the transaction before its state changes are finalised
+	if vetoer, ok := evm.Config.Tracer.(ExecutionVetoer); ok {
+		if err := vetoer.Veto(); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update the state with pending changes.
 	var root []byte
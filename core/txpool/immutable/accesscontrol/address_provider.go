@@ -16,7 +16,18 @@
 
 package accesscontrol
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
 
 // AddressProvider is an interface for providing a list of Ethereum addresses.
 // Implementations of this interface should return a map where the keys are
@@ -30,3 +41,102 @@ type AddressProvider interface {
 	// Provide a list of hex addressses
 	Provide() map[common.Address]struct{}
 }
+
+// ReloadableProvider is an AddressProvider whose backing source can change at
+// runtime, such as a file on disk. Reload swaps the provided set atomically so
+// that concurrent callers of Provide never observe a partially loaded list.
+type ReloadableProvider interface {
+	AddressProvider
+
+	// Reload re-reads the source, returning whether the content changed. On
+	// error the previously loaded addresses are kept.
+	Reload() (bool, error)
+	// Status reports the currently loaded state of the provider
+	Status() txpool.AccessListStatus
+}
+
+// ChainReader provides access to the node's own chain state, used by providers
+// reading their lists from on-chain registries.
+type ChainReader interface {
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+
+	// StateAt returns a state database for a given root hash.
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// polledProvider is implemented by providers whose source cannot be watched
+// and must instead be reloaded periodically.
+type polledProvider interface {
+	pollInterval() time.Duration
+}
+
+// fileProvider is implemented by providers backed by a local file that can be
+// watched for changes.
+type fileProvider interface {
+	path() string
+}
+
+// Supported URI schemes of access-control list sources.
+const (
+	schemeFile     = "file"
+	schemeContract = "contract"
+	schemeHTTP     = "http"
+	schemeHTTPS    = "https"
+)
+
+// newProvider creates the address provider for a list source. Sources without
+// a scheme are plain file paths. File sources ending in .json are parsed as a
+// JSON manifest, others as comma separated values.
+//
+// Supported sources:
+//   - /path/to/list.txt, file:///path/to/list.txt: CSV file
+//   - /path/to/list.json, file:///path/to/list.json: JSON manifest
+//   - contract://0x...?slot=0&interval=15s: address[] stored in a registry contract
+//   - http(s)://host/path#interval=1m: CSV or JSON manifest served over HTTP
+//
+// Remote sources that fail to load at startup refuse to start, unless they set
+// required=false to begin empty and be retried on their poll interval instead.
+// Remote allowlists default to required=false: an empty allowlist already
+// rejects every sender, whereas an empty blocklist would let anyone through.
+func newProvider(source string, isAnAllowList bool, chain ChainReader) (AddressProvider, error) {
+	path, isFile, err := LocalPath(source)
+	if err != nil {
+		return nil, err
+	}
+	if isFile {
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			return newJSONProvider(source, path)
+		}
+		return newCSVProvider(path)
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access-control list source %q: %w", source, err)
+	}
+	switch u.Scheme {
+	case schemeContract:
+		return newContractProvider(source, u, !isAnAllowList, chain)
+	case schemeHTTP, schemeHTTPS:
+		return newHTTPProvider(source, u, !isAnAllowList)
+	default:
+		return nil, fmt.Errorf("unsupported access-control list scheme %q in %q", u.Scheme, source)
+	}
+}
+
+// LocalPath returns the local file path of a list source, and whether the
+// source refers to a local file at all.
+func LocalPath(source string) (string, bool, error) {
+	if !strings.Contains(source, "://") {
+		return source, true, nil
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid access-control list source %q: %w", source, err)
+	}
+	if u.Scheme != schemeFile {
+		return "", false, nil
+	}
+	// Accept both file:///abs/path and file://relative/path
+	return filepath.FromSlash(u.Host + u.Path), true, nil
+}
@@ -0,0 +1,459 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// defaultAuditPageSize is the number of records returned by a query that
+	// doesn't specify a limit.
+	defaultAuditPageSize = 100
+
+	// maxAuditPageSize bounds the number of records returned by a query.
+	maxAuditPageSize = 1000
+
+	// auditBackupTimeFormat is the timestamp format of rotated audit files,
+	// chosen to sort lexicographically in chronological order.
+	auditBackupTimeFormat = "20060102T150405.000000000"
+
+	// auditDedupWindow is the time during which repeated rejections of the
+	// same transaction are recorded only once, so that peers re-announcing a
+	// blocked transaction can't amplify it into audit log writes.
+	auditDedupWindow = time.Minute
+
+	// maxAuditDedupEntries bounds the number of recently recorded rejections
+	// remembered for deduplication.
+	maxAuditDedupEntries = 16384
+)
+
+var (
+	// auditFailureCounter counts the records that couldn't be written to the
+	// audit log, so that a broken audit trail is visible in the metrics.
+	auditFailureCounter = metrics.NewRegisteredCounter("txpool/accesscontrol/audit/failures", nil)
+
+	// auditDuplicateCounter counts the rejections not recorded because the
+	// same rejection was recorded within auditDedupWindow.
+	auditDuplicateCounter = metrics.NewRegisteredCounter("txpool/accesscontrol/audit/duplicates", nil)
+)
+
+// auditKey identifies a rejection for deduplication.
+type auditKey struct {
+	hash   common.Hash
+	source string
+	action string
+}
+
+// AuditLog is a durable, append-only JSONL log of the transactions dropped by
+// the access controllers. Every record is synced to disk before Record returns,
+// so that no rejection is lost on a crash. Once the active file exceeds the
+// size limit it is renamed with a timestamp suffix and a new file started,
+// keeping a bounded number of rotated files.
+type AuditLog struct {
+	path       string
+	maxSize    int64 // Size in bytes after which the active file is rotated
+	maxBackups int   // Number of rotated files kept, 0 keeps all of them
+
+	mu      sync.Mutex
+	file    *os.File // Active file, nil if it couldn't be reopened or the log is closed
+	size    int64
+	lastErr error // Error of the last failed write, nil once a record is written again
+	closed  bool
+
+	recent map[auditKey]time.Time // Time at which recent rejections were recorded
+	pruned time.Time              // Time at which expired entries were last dropped from recent
+	now    func() time.Time       // Overridden in tests
+}
+
+// NewAuditLog opens or creates the audit log at the given path. The active
+// file is rotated once it exceeds maxSizeMB megabytes.
+func NewAuditLog(path string, maxSizeMB int, maxBackups int) (*AuditLog, error) {
+	if maxSizeMB <= 0 {
+		return nil, fmt.Errorf("invalid audit log size limit %d", maxSizeMB)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	l := &AuditLog{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+		recent:     make(map[auditKey]time.Time),
+		now:        time.Now,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *AuditLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Record appends a rejection to the log and syncs it to disk. The record is
+// timestamped while holding the lock, so that records are written in time
+// order. A rejection of the same transaction by the same list and with the
+// same action as one recorded within auditDedupWindow is skipped.
+//
+// If the active file was lost to a failed rotation, it is reopened first.
+// Failures are counted in the txpool/accesscontrol/audit/failures metric and
+// reported by Err until a record is written again.
+func (l *AuditLog) Record(r txpool.AccessControlRejection) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return errors.New("audit log closed")
+	}
+	r.Time = l.now()
+	if l.duplicate(r) {
+		auditDuplicateCounter.Inc(1)
+		return nil
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if err := l.write(line); err != nil {
+		auditFailureCounter.Inc(1)
+		l.lastErr = err
+		return err
+	}
+	l.lastErr = nil
+	l.recent[auditKey{r.Hash, r.Source, r.Action}] = r.Time
+	return nil
+}
+
+// duplicate reports whether the same rejection was recorded within
+// auditDedupWindow, dropping expired entries once per window.
+//
+// The caller must hold l.mu.
+func (l *AuditLog) duplicate(r txpool.AccessControlRejection) bool {
+	if r.Time.Sub(l.pruned) >= auditDedupWindow || len(l.recent) >= maxAuditDedupEntries {
+		for key, recorded := range l.recent {
+			if r.Time.Sub(recorded) >= auditDedupWindow {
+				delete(l.recent, key)
+			}
+		}
+		// Under a flood of distinct rejections, forget them all rather than
+		// growing without bound; it only costs a few repeated records.
+		if len(l.recent) >= maxAuditDedupEntries {
+			l.recent = make(map[auditKey]time.Time)
+		}
+		l.pruned = r.Time
+	}
+	recorded, ok := l.recent[auditKey{r.Hash, r.Source, r.Action}]
+	return ok && r.Time.Sub(recorded) < auditDedupWindow
+}
+
+// write appends a line to the active file, reopening or rotating it as needed,
+// and syncs it to disk.
+//
+// The caller must hold l.mu.
+func (l *AuditLog) write(line []byte) error {
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return fmt.Errorf("failed to reopen audit log: %w", err)
+		}
+	}
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+// Err returns the error of the last record that couldn't be written, or nil if
+// the last record was written successfully.
+func (l *AuditLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lastErr
+}
+
+// rotate renames the active file with a timestamp suffix, starts a new one and
+// deletes the rotated files exceeding the backup limit.
+//
+// The caller must hold l.mu.
+func (l *AuditLog) rotate() error {
+	if err := l.closeFile(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(l.path)
+	backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(l.path, ext), time.Now().UTC().Format(auditBackupTimeFormat), ext)
+	if err := os.Rename(l.path, backup); err != nil {
+		return err
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+	if l.maxBackups > 0 {
+		backups, err := l.backups()
+		if err != nil {
+			log.Warn("Failed to list rotated ACL audit logs", "err", err)
+		}
+		for len(backups) > l.maxBackups {
+			if err := os.Remove(backups[0]); err != nil {
+				log.Warn("Failed to remove rotated ACL audit log", "file", backups[0], "err", err)
+			}
+			backups = backups[1:]
+		}
+	}
+	return nil
+}
+
+// backups returns the rotated files of the log in chronological order.
+func (l *AuditLog) backups() ([]string, error) {
+	ext := filepath.Ext(l.path)
+	files, err := filepath.Glob(strings.TrimSuffix(l.path, ext) + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Query returns the records within the query's time range, in chronological
+// order, starting after the query's cursor.
+func (l *AuditLog) Query(q txpool.AccessControlRejectionQuery) (*txpool.AccessControlRejectionPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultAuditPageSize
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+	cursorTime, cursorSkip, err := parseAuditCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	files, err := l.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	var (
+		page = &txpool.AccessControlRejectionPage{Rejections: []txpool.AccessControlRejection{}}
+		// Number of records seen with the same timestamp as the last one, so
+		// that the cursor can resume within records sharing a timestamp.
+		lastTime time.Time
+		sameTime int
+	)
+	if err := l.Err(); err != nil {
+		page.WriteError = err.Error()
+	}
+	for _, file := range files {
+		done, err := scanAuditFile(file, func(r txpool.AccessControlRejection) bool {
+			if r.Time.Equal(lastTime) {
+				sameTime++
+			} else {
+				lastTime, sameTime = r.Time, 1
+			}
+			if q.From != nil && r.Time.Before(*q.From) {
+				return true
+			}
+			if q.To != nil && !r.Time.Before(*q.To) {
+				return false
+			}
+			if r.Time.UnixNano() < cursorTime || (r.Time.UnixNano() == cursorTime && sameTime <= cursorSkip) {
+				return true
+			}
+			if len(page.Rejections) == limit {
+				last := page.Rejections[limit-1].Time
+				skip := 0
+				for i := limit - 1; i >= 0 && page.Rejections[i].Time.Equal(last); i-- {
+					skip++
+				}
+				if last.UnixNano() == cursorTime {
+					skip += cursorSkip
+				}
+				page.Next = fmt.Sprintf("%d:%d", last.UnixNano(), skip)
+				return false
+			}
+			page.Rejections = append(page.Rejections, r)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+	return page, nil
+}
+
+// snapshot opens the rotated files and the active file of the log, in
+// chronological order. The active file is limited to the records written so
+// far, so the files can be scanned without holding the lock while records are
+// appended and files rotated: rotations rename and delete files, which doesn't
+// affect open ones.
+func (l *AuditLog) snapshot() ([]io.ReadCloser, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	paths, err := l.backups()
+	if err != nil {
+		return nil, err
+	}
+	var files []io.ReadCloser
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			for _, file := range files {
+				file.Close()
+			}
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if file, err := os.Open(l.path); err == nil {
+		files = append(files, struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(file, l.size), file})
+	} else if !errors.Is(err, os.ErrNotExist) {
+		for _, file := range files {
+			file.Close()
+		}
+		return nil, err
+	}
+	return files, nil
+}
+
+// scanAuditFile calls fn for every record of the file until it returns false,
+// in which case scanAuditFile reports done. Malformed lines, such as a line
+// truncated by a crash, are skipped.
+func scanAuditFile(file io.Reader, fn func(r txpool.AccessControlRejection) bool) (bool, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r txpool.AccessControlRejection
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if !fn(r) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// parseAuditCursor decodes a cursor of the form <unixnano>:<skip>, where skip
+// is the number of records with that exact timestamp already returned.
+func parseAuditCursor(cursor string) (int64, int, error) {
+	if cursor == "" {
+		return 0, 0, nil
+	}
+	timePart, skipPart, ok := strings.Cut(cursor, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	t, err := strconv.ParseInt(timePart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	skip, err := strconv.Atoi(skipPart)
+	if err != nil || skip < 0 {
+		return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return t, skip, nil
+}
+
+// Close closes the active file of the log, later records are refused.
+func (l *AuditLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	if l.file == nil {
+		return nil
+	}
+	return l.closeFile()
+}
+
+// closeFile closes the active file, which has been synced by every write.
+//
+// The caller must hold l.mu.
+func (l *AuditLog) closeFile() error {
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// RejectionCounter returns the counter of transactions dropped because of the
+// given list, registered as txpool/accesscontrol/rejections/<list file name>.
+func RejectionCounter(source string) metrics.Counter {
+	return metrics.GetOrRegisterCounter("txpool/accesscontrol/rejections/"+rejectionLabel(source), nil)
+}
+
+// rejectionLabel derives the metric label of a list source: the file name of
+// local lists, the sanitized URI otherwise. Rejections by an allowlist not
+// containing the sender, which match no list, are labeled "unlisted".
+func rejectionLabel(source string) string {
+	if source == "" {
+		return "unlisted"
+	}
+	label := source
+	if path, isFile, err := LocalPath(source); err == nil && isFile {
+		label = filepath.Base(path)
+	}
+	return strings.NewReplacer("/", "_", ":", "_", "?", "_", "#", "_", "=", "_", "&", "_").Replace(label)
+}
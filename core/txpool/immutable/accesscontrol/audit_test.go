@@ -0,0 +1,228 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableAuditLog_RotateAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "rejections.jsonl")
+	auditLog, err := NewAuditLog(path, 1, 2)
+	require.NoError(t, err)
+
+	// Write enough records to rotate the 1MB file several times, with groups
+	// of records sharing a timestamp to exercise the cursor
+	var (
+		base    = time.Unix(1700000000, 0)
+		total   = 12000
+		sources = []string{"a.txt", "b.txt"}
+	)
+	for i := 0; i < total; i++ {
+		auditLog.now = func() time.Time { return base.Add(time.Duration(i/3) * time.Second) }
+		require.NoError(t, auditLog.Record(txpool.AccessControlRejection{
+			Hash:   common.BigToHash(big.NewInt(int64(i))),
+			Sender: common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A"),
+			Source: sources[i%2],
+			Action: txpool.AccessControlRejected,
+		}))
+	}
+	backups, err := auditLog.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2, "old rotated files should be deleted")
+
+	// Page through the retained records and check they are chronological and
+	// without duplicates
+	from := base.Add(3000 * time.Second)
+	to := base.Add(3500 * time.Second)
+	var (
+		query = txpool.AccessControlRejectionQuery{From: &from, To: &to, Limit: 100}
+		seen  int
+		last  time.Time
+	)
+	for {
+		page, err := auditLog.Query(query)
+		require.NoError(t, err)
+		for _, r := range page.Rejections {
+			require.False(t, r.Time.Before(last))
+			require.False(t, r.Time.Before(from))
+			require.True(t, r.Time.Before(to))
+			last = r.Time
+		}
+		seen += len(page.Rejections)
+		if page.Next == "" {
+			break
+		}
+		query.Cursor = page.Next
+	}
+	require.Equal(t, 1500, seen)
+
+	require.NoError(t, auditLog.Close())
+	_, err = auditLog.Query(txpool.AccessControlRejectionQuery{Cursor: "bogus"})
+	require.Error(t, err)
+}
+
+func TestImmutableAuditLog_Durable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rejections.jsonl")
+	auditLog, err := NewAuditLog(path, 1, 0)
+	require.NoError(t, err)
+
+	record := txpool.AccessControlRejection{
+		Sender: common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A"),
+		Action: txpool.AccessControlRejected,
+	}
+	// Records appended while querying must neither block nor corrupt queries
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 200; i++ {
+			distinct := record
+			distinct.Hash = common.BigToHash(big.NewInt(int64(i)))
+			require.NoError(t, auditLog.Record(distinct))
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		page, err := auditLog.Query(txpool.AccessControlRejectionQuery{Limit: maxAuditPageSize})
+		require.NoError(t, err)
+		for _, r := range page.Rejections {
+			require.Equal(t, record.Sender, r.Sender)
+		}
+	}
+	wg.Wait()
+
+	// Records are on disk as soon as they are recorded
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 200, bytes.Count(data, []byte("\n")))
+
+	// A lost active file that can't be reopened fails the records and is
+	// reported by queries, until it can be reopened again
+	auditLog.mu.Lock()
+	require.NoError(t, auditLog.closeFile())
+	auditLog.mu.Unlock()
+	require.NoError(t, os.Rename(path, path+".moved"))
+	require.NoError(t, os.Mkdir(path, 0755))
+
+	require.ErrorContains(t, auditLog.Record(record), "failed to reopen audit log")
+	require.NoError(t, os.Remove(path))
+	page, err := auditLog.Query(txpool.AccessControlRejectionQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Contains(t, page.WriteError, "failed to reopen audit log")
+
+	require.NoError(t, auditLog.Record(record))
+	require.NoError(t, auditLog.Err())
+	page, err = auditLog.Query(txpool.AccessControlRejectionQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Empty(t, page.WriteError)
+	require.Len(t, page.Rejections, 1)
+
+	require.NoError(t, auditLog.Close())
+	require.NoError(t, auditLog.Close())
+	require.Error(t, auditLog.Record(record))
+}
+
+func TestImmutableAuditLog_Dedup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rejections.jsonl")
+	auditLog, err := NewAuditLog(path, 1, 0)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	now := time.Unix(1700000000, 0)
+	auditLog.now = func() time.Time { return now }
+
+	blocked := txpool.AccessControlRejection{
+		Hash:   common.HexToHash("0x01"),
+		Sender: common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A"),
+		Source: "a.txt",
+		Action: txpool.AccessControlRejected,
+	}
+	evicted := blocked
+	evicted.Action = txpool.AccessControlEvicted
+
+	// Re-announcements of the same transaction are recorded once per window,
+	// a different action on it is recorded separately
+	for i := 0; i < 10; i++ {
+		require.NoError(t, auditLog.Record(blocked))
+	}
+	require.NoError(t, auditLog.Record(evicted))
+	now = now.Add(auditDedupWindow - time.Second)
+	require.NoError(t, auditLog.Record(blocked))
+	now = now.Add(time.Second)
+	require.NoError(t, auditLog.Record(blocked))
+
+	page, err := auditLog.Query(txpool.AccessControlRejectionQuery{})
+	require.NoError(t, err)
+	require.Len(t, page.Rejections, 3)
+	require.Equal(t, txpool.AccessControlEvicted, page.Rejections[1].Action)
+	require.Equal(t, now.Unix(), page.Rejections[2].Time.Unix())
+}
+
+func TestImmutableAuditLog_TimeOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rejections.jsonl")
+	auditLog, err := NewAuditLog(path, 1, 0)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	// Timestamps given by callers are replaced by the time the record is
+	// written, so that concurrent rejections are stored in time order
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				require.NoError(t, auditLog.Record(txpool.AccessControlRejection{
+					Time:   time.Unix(int64(1000-j), 0),
+					Hash:   common.BigToHash(big.NewInt(int64(i*50 + j))),
+					Action: txpool.AccessControlRejected,
+				}))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	page, err := auditLog.Query(txpool.AccessControlRejectionQuery{Limit: maxAuditPageSize})
+	require.NoError(t, err)
+	require.Len(t, page.Rejections, 400)
+	for i := 1; i < len(page.Rejections); i++ {
+		require.False(t, page.Rejections[i].Time.Before(page.Rejections[i-1].Time))
+	}
+}
+
+func TestImmutableRejectionLabel(t *testing.T) {
+	tests := map[string]string{
+		"":                         "unlisted",
+		"/etc/acl/sdn.txt":         "sdn.txt",
+		"file:///etc/acl/sdn.json": "sdn.json",
+		"https://acl.example/list": "https___acl.example_list",
+		"contract://0x01?slot=1":   "contract___0x01_slot_1",
+	}
+	for source, want := range tests {
+		require.Equal(t, want, rejectionLabel(source), source)
+	}
+}
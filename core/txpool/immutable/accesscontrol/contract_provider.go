@@ -0,0 +1,162 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// defaultContractPollInterval is how often a registry contract is re-read
+	// if the source doesn't specify an interval.
+	defaultContractPollInterval = 15 * time.Second
+
+	// maxContractEntries bounds the number of addresses read from a registry,
+	// protecting the node against a corrupted or hostile array length.
+	maxContractEntries = 1 << 16
+)
+
+// ContractProvider provides the addresses stored in an on-chain registry
+// contract, read directly from the node's own state at the head block.
+//
+// The registry is expected to keep its entries in a dynamic address[] array
+// at the given storage slot (slot 0 by default), e.g.
+//
+//	contract Registry { address[] public entries; ... }
+//
+// The source is specified as contract://0x...?slot=0&interval=15s. Setting
+// required overrides whether an unreadable registry refuses to start.
+type ContractProvider struct {
+	source   string
+	registry common.Address
+	slot     common.Hash
+	interval time.Duration
+	chain    ChainReader
+	set      entrySet
+}
+
+func newContractProvider(source string, u *url.URL, required bool, chain ChainReader) (*ContractProvider, error) {
+	if chain == nil {
+		return nil, fmt.Errorf("contract access-control list %q requires chain access", source)
+	}
+	if !common.IsHexAddress(u.Host) {
+		return nil, fmt.Errorf("invalid registry address %q in %q", u.Host, source)
+	}
+	p := &ContractProvider{
+		source:   source,
+		registry: common.HexToAddress(u.Host),
+		interval: defaultContractPollInterval,
+		chain:    chain,
+	}
+	params := u.Query()
+	if v := params.Get("slot"); v != "" {
+		slot, err := strconv.ParseUint(v, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid storage slot %q in %q", v, source)
+		}
+		p.slot = common.BigToHash(new(big.Int).SetUint64(slot))
+	}
+	if v := params.Get("interval"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid poll interval %q in %q", v, source)
+		}
+		p.interval = interval
+	}
+	if v := params.Get("required"); v != "" {
+		var err error
+		if required, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid required option %q in %q", v, source)
+		}
+	}
+	// An unreadable registry fails unless it isn't required, in which case it
+	// starts out empty and is retried on the poll schedule.
+	if _, err := p.Reload(); err != nil && required {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *ContractProvider) Provide() map[common.Address]struct{} {
+	return p.set.provide()
+}
+
+// Reload re-reads the registry at the current head, keeping the previous
+// entries on error.
+func (p *ContractProvider) Reload() (bool, error) {
+	entries, hash, err := p.read()
+	changed, err := p.set.update(entries, hash, err)
+	if err != nil {
+		log.Warn("Failed to read ACL registry, keeping previous contents", "registry", p.registry, "err", err)
+	} else if changed {
+		log.Info("Loaded ACL registry", "registry", p.registry, "entries", len(entries), "hash", hash)
+	}
+	return changed, err
+}
+
+func (p *ContractProvider) read() ([]entry, common.Hash, error) {
+	head := p.chain.CurrentBlock()
+	if head == nil {
+		return nil, common.Hash{}, errors.New("chain head unavailable")
+	}
+	statedb, err := p.chain.StateAt(head.Root)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("state unavailable at block %d: %w", head.Number, err)
+	}
+	if statedb.GetCodeSize(p.registry) == 0 {
+		return nil, common.Hash{}, fmt.Errorf("no registry contract deployed at %s", p.registry)
+	}
+	// Dynamic arrays store their length at the slot itself and their elements
+	// consecutively from keccak256(slot), one address per slot.
+	length := statedb.GetState(p.registry, p.slot).Big()
+	if !length.IsUint64() || length.Uint64() > maxContractEntries {
+		return nil, common.Hash{}, fmt.Errorf("registry length %v exceeds limit %d", length, maxContractEntries)
+	}
+	var (
+		n       = length.Uint64()
+		base    = crypto.Keccak256Hash(p.slot.Bytes()).Big()
+		entries = make([]entry, 0, n)
+		raw     = make([]byte, 0, n*common.AddressLength)
+	)
+	for i := uint64(0); i < n; i++ {
+		slot := common.BigToHash(new(big.Int).Add(base, new(big.Int).SetUint64(i)))
+		addr := common.BytesToAddress(statedb.GetState(p.registry, slot).Bytes())
+		entries = append(entries, entry{Address: addr})
+		raw = append(raw, addr.Bytes()...)
+	}
+	return entries, crypto.Keccak256Hash(raw), nil
+}
+
+// Status reports the currently loaded state of the registry.
+func (p *ContractProvider) Status() txpool.AccessListStatus {
+	return p.set.status(p.source)
+}
+
+// pollInterval returns how often the registry should be re-read.
+func (p *ContractProvider) pollInterval() time.Duration {
+	return p.interval
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// testChain is a ChainReader serving a single mutable state.
+type testChain struct {
+	statedb *state.StateDB
+}
+
+func (c *testChain) CurrentBlock() *types.Header {
+	return &types.Header{Number: big.NewInt(1)}
+}
+
+func (c *testChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return c.statedb, nil
+}
+
+// setRegistry stores the addresses as a dynamic address[] at the given slot.
+func setRegistry(statedb *state.StateDB, registry common.Address, slot uint64, addrs []common.Address) {
+	slotHash := common.BigToHash(new(big.Int).SetUint64(slot))
+	statedb.SetState(registry, slotHash, common.BigToHash(big.NewInt(int64(len(addrs)))))
+
+	base := crypto.Keccak256Hash(slotHash.Bytes()).Big()
+	for i, addr := range addrs {
+		statedb.SetState(registry, common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i)))), common.BytesToHash(addr.Bytes()))
+	}
+}
+
+func TestImmutableContractProvider(t *testing.T) {
+	var (
+		registry = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		first    = common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+		second   = common.HexToAddress("0x7F19720A857F834887FC9A7bC0a0fBe7Fc7f8102")
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	chain := &testChain{statedb: statedb}
+
+	// No contract deployed yet: blocklists fail closed unless they aren't
+	// required, allowlists start empty unless they are required
+	_, err := newProvider("contract://"+registry.Hex(), false, chain)
+	require.ErrorContains(t, err, "no registry contract")
+	_, err = newProvider("contract://"+registry.Hex()+"?required=true", true, chain)
+	require.ErrorContains(t, err, "no registry contract")
+
+	pending, err := newProvider("contract://"+registry.Hex()+"?slot=3&required=false", false, chain)
+	require.NoError(t, err)
+	require.Empty(t, pending.Provide())
+	require.Contains(t, pending.(*ContractProvider).Status().LastError, "no registry contract")
+
+	statedb.SetCode(registry, []byte{0x00})
+	setRegistry(statedb, registry, 3, []common.Address{first})
+
+	provider, err := newProvider("contract://"+registry.Hex()+"?slot=3&interval=1s", false, chain)
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]struct{}{first: {}}, provider.Provide())
+
+	reloadable := provider.(ReloadableProvider)
+	changed, err := reloadable.Reload()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	setRegistry(statedb, registry, 3, []common.Address{first, second})
+	changed, err = reloadable.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, provider.Provide(), 2)
+	require.Contains(t, provider.Provide(), second)
+
+	// The list that was unavailable at startup is picked up once it can be read
+	changed, err = pending.(ReloadableProvider).Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Len(t, pending.Provide(), 2)
+
+	// A corrupted length keeps the previous contents
+	statedb.SetState(registry, common.BigToHash(big.NewInt(3)), common.BigToHash(big.NewInt(maxContractEntries+1)))
+	_, err = reloadable.Reload()
+	require.Error(t, err)
+	require.Len(t, provider.Provide(), 2)
+}
@@ -17,10 +17,16 @@
 package accesscontrol
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 type Controller struct {
@@ -28,24 +34,36 @@ type Controller struct {
 	providers map[string]AddressProvider
 	// isAnAllowList indicates that it is an allowlist otherwise the inverse is a blocklist
 	isAnAllowList bool
+
+	reloadFeed  event.Feed
+	reloadScope event.SubscriptionScope
+	reloadLock  sync.Mutex // Serializes reloads triggered by the watcher and by RPC
+
+	watcher *watcher       // Filesystem watcher reloading file backed providers, nil if none
+	quit    chan struct{}  // Closed to stop the pollers, nil if not watching
+	wg      sync.WaitGroup // Tracks the pollers
 }
 
-// New initializes an access controller with blocklists specified by file paths.
+// New initializes an access controller with lists specified by their sources.
 //
 // Parameters:
-//   - filePaths: A slice of strings containing file paths to blocklist
-//     files, usually an sdn file that comes in the format of txt as comma separated values
+//   - sources: A slice of strings containing the sources of the lists. Plain
+//     file paths are usually an sdn file that comes in the format of txt as
+//     comma separated values, other backends are selected by URI scheme, see
+//     newProvider for the supported formats
 //   - isAnAllowList: Indicates if the controller is an allow controller or
 //     a block controller.
-func New(filePaths []string, isAnAllowList bool) (*Controller, error) {
-	providers := make(map[string]AddressProvider, len(filePaths))
+//   - chain: Access to the node's chain state, required by on-chain registry
+//     lists and may otherwise be nil.
+func New(sources []string, isAnAllowList bool, chain ChainReader) (*Controller, error) {
+	providers := make(map[string]AddressProvider, len(sources))
 
-	for _, filename := range filePaths {
-		csvProvider, err := newCSVProvider(filename)
+	for _, source := range sources {
+		provider, err := newProvider(source, isAnAllowList, chain)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't initialize access controller provider: %w", err)
 		}
-		providers[filename] = csvProvider
+		providers[source] = provider
 	}
 
 	return &Controller{
@@ -59,18 +77,175 @@ func (c *Controller) IsBlocklist() bool {
 }
 
 func (c *Controller) IsAllowed(addr common.Address, tx *types.Transaction) bool {
-	for _, list := range c.providers {
+	allowed, _ := c.Match(addr, tx)
+	return allowed
+}
+
+// Match returns whether the transaction is allowed, and the source of the list
+// that matched the sender or, for blocklists, the recipient.
+func (c *Controller) Match(addr common.Address, tx *types.Transaction) (bool, string) {
+	for source, list := range c.providers {
 		addresses := list.Provide()
 		if _, exist := addresses[addr]; exist {
-			return c.isAnAllowList
+			return c.isAnAllowList, source
 		}
 		if c.IsBlocklist() && tx.To() != nil {
 			if _, exist := addresses[*tx.To()]; exist {
-				return c.isAnAllowList
+				return c.isAnAllowList, source
 			}
 		}
 	}
 
 	// If the address is not in the list and it's not an allow list, return true
-	return !c.isAnAllowList
+	return !c.isAnAllowList, ""
+}
+
+// Watch starts a filesystem watcher that reloads the file backed providers
+// whenever their files change on disk, and a poller for each provider whose
+// source can only be polled. It is a no-op if already watching.
+func (c *Controller) Watch() error {
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	if c.quit != nil {
+		return nil
+	}
+	var paths []string
+	for _, provider := range c.providers {
+		if file, ok := provider.(fileProvider); ok {
+			paths = append(paths, file.path())
+		}
+	}
+	if len(paths) > 0 {
+		w, err := newWatcher(paths, func() { c.reload(isFileProvider) })
+		if err != nil {
+			return err
+		}
+		c.watcher = w
+	}
+	c.quit = make(chan struct{})
+	for source, provider := range c.providers {
+		if polled, ok := provider.(polledProvider); ok {
+			c.wg.Add(1)
+			go c.poll(source, polled.pollInterval(), c.quit)
+		}
+	}
+	return nil
+}
+
+// poll periodically reloads a single provider until the controller is closed.
+func (c *Controller) poll(source string, interval time.Duration, quit chan struct{}) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+			c.reload(func(s string, _ AddressProvider) bool { return s == source })
+		}
+	}
+}
+
+// isFileProvider is a reload filter selecting the file backed providers.
+func isFileProvider(_ string, provider AddressProvider) bool {
+	_, ok := provider.(fileProvider)
+	return ok
+}
+
+// Reload re-reads every reloadable provider and notifies subscribers if any
+// of them changed content. Providers that fail to reload keep their previous
+// contents; the returned error joins all individual failures.
+func (c *Controller) Reload() error {
+	return c.reload(func(string, AddressProvider) bool { return true })
+}
+
+// reload re-reads the reloadable providers selected by the filter.
+func (c *Controller) reload(filter func(source string, provider AddressProvider) bool) error {
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	var (
+		changed []string
+		errs    []error
+	)
+	for source, provider := range c.providers {
+		reloadable, ok := provider.(ReloadableProvider)
+		if !ok || !filter(source, provider) {
+			continue
+		}
+		updated, err := reloadable.Reload()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			continue
+		}
+		if updated {
+			changed = append(changed, source)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		c.reloadFeed.Send(txpool.AccessControlReloadEvent{Sources: changed})
+	}
+	return errors.Join(errs...)
+}
+
+// Status reports the currently loaded state of every list in the controller.
+func (c *Controller) Status() []txpool.AccessListStatus {
+	statuses := make([]txpool.AccessListStatus, 0, len(c.providers))
+	for source, provider := range c.providers {
+		var status txpool.AccessListStatus
+		if reloadable, ok := provider.(ReloadableProvider); ok {
+			status = reloadable.Status()
+			status.Source = source
+		} else {
+			status = txpool.AccessListStatus{Source: source, Size: len(provider.Provide())}
+		}
+		status.IsBlocklist = c.IsBlocklist()
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Source < statuses[j].Source })
+	return statuses
+}
+
+// SubscribeReloadEvent registers a subscription for list content changes.
+func (c *Controller) SubscribeReloadEvent(ch chan<- txpool.AccessControlReloadEvent) event.Subscription {
+	return c.reloadScope.Track(c.reloadFeed.Subscribe(ch))
+}
+
+// Close stops the filesystem watcher and pollers, if running, and terminates
+// all reload subscriptions.
+func (c *Controller) Close() error {
+	c.reloadLock.Lock()
+	w, quit := c.watcher, c.quit
+	c.watcher, c.quit = nil, nil
+	c.reloadLock.Unlock()
+
+	if w != nil {
+		w.close()
+	}
+	if quit != nil {
+		close(quit)
+		c.wg.Wait()
+	}
+	c.reloadScope.Close()
+	return nil
+}
+
+// IsTargetAllowed returns whether execution may reach the given account and, if
+// not, the source of the list that forbids it. Allowlists only restrict the
+// senders of transactions, so every target is allowed by them.
+func (c *Controller) IsTargetAllowed(target common.Address) (bool, string) {
+	if !c.IsBlocklist() {
+		return true, ""
+	}
+	for source, list := range c.providers {
+		if _, exist := list.Provide()[target]; exist {
+			return false, source
+		}
+	}
+	return true, ""
 }
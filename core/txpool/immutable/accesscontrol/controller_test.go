@@ -19,9 +19,13 @@ package accesscontrol
 import (
 	"crypto/ecdsa"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -161,3 +165,54 @@ func TestImmutableAccessControl_Controller_IsAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestImmutableAccessControl_Controller_WatchReload(t *testing.T) {
+	blockedKey, _ := crypto.GenerateKey()
+	blocked := crypto.PubkeyToAddress(blockedKey.PublicKey)
+	initial := common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte(initial.Hex()), 0644); err != nil {
+		t.Fatalf("Failed to write blocklist: %v", err)
+	}
+	controller, err := New([]string{path}, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to create controller: %v", err)
+	}
+	defer controller.Close()
+
+	if err := controller.Watch(); err != nil {
+		t.Fatalf("Failed to watch blocklist: %v", err)
+	}
+	events := make(chan txpool.AccessControlReloadEvent, 1)
+	sub := controller.SubscribeReloadEvent(events)
+	defer sub.Unsubscribe()
+
+	tx := transaction(0, 100000, blockedKey)
+	if !controller.IsAllowed(blocked, tx) {
+		t.Fatalf("Sender blocked before being added to the blocklist")
+	}
+	// Replace the file through a rename, as config management tools do
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(initial.Hex()+","+blocked.Hex()), 0644); err != nil {
+		t.Fatalf("Failed to write blocklist: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("Failed to replace blocklist: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if len(ev.Sources) != 1 || ev.Sources[0] != path {
+			t.Fatalf("Unexpected reload sources: %v", ev.Sources)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Blocklist was not reloaded")
+	}
+	if controller.IsAllowed(blocked, tx) {
+		t.Fatalf("Sender allowed after being added to the blocklist")
+	}
+	status := controller.Status()
+	if len(status) != 1 || status[0].Size != 2 || !status[0].IsBlocklist {
+		t.Fatalf("Unexpected status after reload: %+v", status)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+)
+
+// entry is a single address of an access-control list, optionally expiring.
+type entry struct {
+	Address   common.Address `json:"address"`
+	ExpiresAt *time.Time     `json:"expiresAt,omitempty"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// entrySet holds the loaded entries of a reloadable provider and the derived
+// set of currently active addresses. Expired entries are dropped lazily the
+// first time the set is consulted after their expiry.
+type entrySet struct {
+	mu         sync.RWMutex
+	entries    []entry
+	addresses  map[common.Address]struct{} // Active addresses, recomputed on expiry
+	nextExpiry time.Time                   // Earliest future expiry, zero if none
+	hash       common.Hash                 // Hash of the raw source content
+	loadedAt   time.Time                   // Time the current entries were loaded
+	lastErr    error                       // Error of the most recent load attempt, if any
+}
+
+// provide returns the currently active addresses.
+func (s *entrySet) provide() map[common.Address]struct{} {
+	now := time.Now()
+
+	s.mu.RLock()
+	if s.nextExpiry.IsZero() || now.Before(s.nextExpiry) {
+		defer s.mu.RUnlock()
+		return s.addresses
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.nextExpiry.IsZero() && !now.Before(s.nextExpiry) {
+		s.activate(now)
+	}
+	return s.addresses
+}
+
+// update swaps in newly loaded entries, returning whether the content changed.
+// If err is non-nil the previous entries are kept.
+func (s *entrySet) update(entries []entry, hash common.Hash, err error) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastErr = err
+	if err != nil {
+		return false, err
+	}
+	if hash == s.hash && s.addresses != nil {
+		return false, nil
+	}
+	s.entries, s.hash, s.loadedAt = entries, hash, time.Now()
+	s.activate(s.loadedAt)
+	return true, nil
+}
+
+// activate recomputes the active addresses and the next expiry as of now.
+//
+// The caller must hold s.mu.
+func (s *entrySet) activate(now time.Time) {
+	addresses := make(map[common.Address]struct{}, len(s.entries))
+	nextExpiry := time.Time{}
+	for _, e := range s.entries {
+		if e.ExpiresAt != nil {
+			if !now.Before(*e.ExpiresAt) {
+				continue
+			}
+			if nextExpiry.IsZero() || e.ExpiresAt.Before(nextExpiry) {
+				nextExpiry = *e.ExpiresAt
+			}
+		}
+		addresses[e.Address] = struct{}{}
+	}
+	s.addresses, s.nextExpiry = addresses, nextExpiry
+}
+
+// status reports the currently loaded state of the set.
+func (s *entrySet) status(source string) txpool.AccessListStatus {
+	addresses := s.provide()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := txpool.AccessListStatus{
+		Source:   source,
+		Size:     len(addresses),
+		Hash:     s.hash,
+		LoadedAt: s.loadedAt,
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	return status
+}
@@ -0,0 +1,159 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// defaultHTTPPollInterval is how often an HTTP feed is polled if the
+	// source doesn't specify an interval.
+	defaultHTTPPollInterval = 5 * time.Minute
+
+	// httpFetchTimeout bounds a single fetch of an HTTP feed.
+	httpFetchTimeout = 30 * time.Second
+
+	// maxHTTPFeedSize bounds the size of an HTTP feed response.
+	maxHTTPFeedSize = 16 * 1024 * 1024
+)
+
+// HTTPProvider provides the addresses served by an HTTP endpoint, either as a
+// JSON manifest or as comma separated values, polled on a schedule.
+//
+// The poll interval is configured through the URL fragment, which is never
+// sent to the server, e.g. https://example.com/blocklist.json#interval=1m.
+// A feed that can't be fetched at startup fails, unless it isn't required, in
+// which case it is served empty and retried on the poll schedule. The fragment
+// can override this with required=true or required=false.
+type HTTPProvider struct {
+	source   string
+	endpoint string
+	interval time.Duration
+	client   *http.Client
+	set      entrySet
+}
+
+func newHTTPProvider(source string, u *url.URL, required bool) (*HTTPProvider, error) {
+	interval := defaultHTTPPollInterval
+	if u.Fragment != "" {
+		params, err := url.ParseQuery(u.Fragment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid options in %q: %w", source, err)
+		}
+		if v := params.Get("interval"); v != "" {
+			if interval, err = time.ParseDuration(v); err != nil || interval <= 0 {
+				return nil, fmt.Errorf("invalid poll interval %q in %q", v, source)
+			}
+		}
+		if v := params.Get("required"); v != "" {
+			if required, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid required option %q in %q", v, source)
+			}
+		}
+	}
+	endpoint := *u
+	endpoint.Fragment = ""
+
+	p := &HTTPProvider{
+		source:   source,
+		endpoint: endpoint.String(),
+		interval: interval,
+		client:   &http.Client{Timeout: httpFetchTimeout},
+	}
+	if _, err := p.Reload(); err != nil && required {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *HTTPProvider) Provide() map[common.Address]struct{} {
+	return p.set.provide()
+}
+
+// Reload fetches the feed, keeping the previous entries on error.
+func (p *HTTPProvider) Reload() (bool, error) {
+	entries, hash, err := p.fetch()
+	changed, err := p.set.update(entries, hash, err)
+	if err != nil {
+		log.Warn("Failed to fetch ACL feed, keeping previous contents", "url", p.endpoint, "err", err)
+	} else if changed {
+		log.Info("Loaded ACL feed", "url", p.endpoint, "entries", len(entries), "hash", hash)
+	}
+	return changed, err
+}
+
+func (p *HTTPProvider) fetch() ([]entry, common.Hash, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), httpFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint, nil)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, common.Hash{}, fmt.Errorf("unexpected status %s", res.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxHTTPFeedSize+1))
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	if len(data) > maxHTTPFeedSize {
+		return nil, common.Hash{}, fmt.Errorf("feed exceeds %d bytes", maxHTTPFeedSize)
+	}
+	// Serve JSON manifests and plain comma separated lists from the same scheme
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseManifest(data)
+	}
+	addresses, err := parseCSV(data)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	entries := make([]entry, 0, len(addresses))
+	for addr := range addresses {
+		entries = append(entries, entry{Address: addr})
+	}
+	return entries, sha256.Sum256(data), nil
+}
+
+// Status reports the currently loaded state of the feed.
+func (p *HTTPProvider) Status() txpool.AccessListStatus {
+	return p.set.status(p.source)
+}
+
+// pollInterval returns how often the feed should be fetched.
+func (p *HTTPProvider) pollInterval() time.Duration {
+	return p.interval
+}
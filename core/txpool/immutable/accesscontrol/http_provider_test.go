@@ -0,0 +1,115 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableHTTPProvider_Poll(t *testing.T) {
+	var (
+		first  = common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+		second = common.HexToAddress("0x7F19720A857F834887FC9A7bC0a0fBe7Fc7f8102")
+		body   atomic.Value
+		status atomic.Int32
+	)
+	body.Store(first.Hex())
+	status.Store(http.StatusOK)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(body.Load().(string)))
+	}))
+	defer server.Close()
+
+	controller, err := New([]string{server.URL + "/blocklist#interval=50ms"}, false, nil)
+	require.NoError(t, err)
+	defer controller.Close()
+	require.Contains(t, controller.providers[server.URL+"/blocklist#interval=50ms"].Provide(), first)
+
+	events := make(chan txpool.AccessControlReloadEvent, 1)
+	sub := controller.SubscribeReloadEvent(events)
+	defer sub.Unsubscribe()
+	require.NoError(t, controller.Watch())
+
+	// A failing endpoint keeps the previous contents
+	status.Store(http.StatusInternalServerError)
+	require.Eventually(t, func() bool {
+		return controller.Status()[0].LastError != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, controller.Status()[0].Size)
+
+	// Switching to a JSON manifest is picked up on the next poll
+	body.Store(`{"entries": [{"address": "` + first.Hex() + `"}, {"address": "` + second.Hex() + `"}]}`)
+	status.Store(http.StatusOK)
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("HTTP feed was not reloaded")
+	}
+	st := controller.Status()[0]
+	require.Equal(t, 2, st.Size)
+	require.Empty(t, st.LastError)
+}
+
+func TestImmutableHTTPProvider_InvalidInterval(t *testing.T) {
+	_, err := newProvider("http://127.0.0.1:1/blocklist#interval=soon", false, nil)
+	require.ErrorContains(t, err, "invalid poll interval")
+}
+
+func TestImmutableHTTPProvider_UnavailableAtStartup(t *testing.T) {
+	var (
+		addr   = common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+		status atomic.Int32
+	)
+	status.Store(http.StatusServiceUnavailable)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(addr.Hex()))
+	}))
+	defer server.Close()
+
+	// Blocklists fail closed
+	_, err := New([]string{server.URL + "/blocklist"}, false, nil)
+	require.ErrorContains(t, err, "unexpected status")
+
+	// Unless they aren't required, then they start empty and are retried on
+	// the poll schedule
+	source := server.URL + "/blocklist#interval=50ms&required=false"
+	controller, err := New([]string{source}, false, nil)
+	require.NoError(t, err)
+	defer controller.Close()
+	require.Empty(t, controller.providers[source].Provide())
+	require.NotEmpty(t, controller.Status()[0].LastError)
+	require.NoError(t, controller.Watch())
+
+	status.Store(http.StatusOK)
+	require.Eventually(t, func() bool {
+		_, ok := controller.providers[source].Provide()[addr]
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, controller.Status()[0].LastError)
+}
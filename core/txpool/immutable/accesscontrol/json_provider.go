@@ -0,0 +1,107 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// manifest is the JSON format of an access-control list with per-entry expiry:
+//
+//	{
+//	  "entries": [
+//	    {"address": "0x...", "expiresAt": "2025-01-01T00:00:00Z", "reason": "..."},
+//	    {"address": "0x..."}
+//	  ]
+//	}
+type manifest struct {
+	Entries []entry `json:"entries"`
+}
+
+// parseManifest decodes a JSON manifest, rejecting entries with a zero address
+// which usually indicate a malformed address string.
+func parseManifest(data []byte) ([]entry, common.Hash, error) {
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, common.Hash{}, fmt.Errorf("invalid access-control manifest: %w", err)
+	}
+	if m.Entries == nil {
+		return nil, common.Hash{}, fmt.Errorf("access-control manifest has no entries field")
+	}
+	for i, e := range m.Entries {
+		if e.Address == (common.Address{}) {
+			return nil, common.Hash{}, fmt.Errorf("access-control manifest entry %d has no address", i)
+		}
+	}
+	return m.Entries, sha256.Sum256(data), nil
+}
+
+// JSONProvider provides the addresses of a JSON manifest file, dropping
+// entries once they expire.
+type JSONProvider struct {
+	source   string
+	filePath string
+	set      entrySet
+}
+
+func newJSONProvider(source string, filePath string) (*JSONProvider, error) {
+	p := &JSONProvider{source: source, filePath: filePath}
+	if _, err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *JSONProvider) Provide() map[common.Address]struct{} {
+	return p.set.provide()
+}
+
+// Reload re-reads the manifest file, keeping the previous entries on error.
+func (p *JSONProvider) Reload() (bool, error) {
+	data, err := os.ReadFile(p.filePath)
+	var (
+		entries []entry
+		hash    common.Hash
+	)
+	if err == nil {
+		entries, hash, err = parseManifest(data)
+	}
+	changed, err := p.set.update(entries, hash, err)
+	if err != nil {
+		log.Warn("Failed to load ACL manifest, keeping previous contents", "filepath", p.filePath, "err", err)
+	} else if changed {
+		log.Info("Loaded ACL manifest", "filepath", p.filePath, "entries", len(entries), "hash", hash)
+	}
+	return changed, err
+}
+
+// Status reports the currently loaded state of the manifest.
+func (p *JSONProvider) Status() txpool.AccessListStatus {
+	return p.set.status(p.source)
+}
+
+// path returns the local file backing the provider, used for watching it.
+func (p *JSONProvider) path() string {
+	return p.filePath
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImmutableJSONProvider_Expiry(t *testing.T) {
+	var (
+		permanent = common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+		expiring  = common.HexToAddress("0x7F19720A857F834887FC9A7bC0a0fBe7Fc7f8102")
+		expired   = common.HexToAddress("0x1da5821544e25c636c1417ba96ade4cf6d2f9b5a")
+		now       = time.Now()
+	)
+	manifest := fmt.Sprintf(`{"entries": [
+		{"address": "%s"},
+		{"address": "%s", "expiresAt": "%s", "reason": "temporary"},
+		{"address": "%s", "expiresAt": "%s"}
+	]}`, permanent.Hex(), expiring.Hex(), now.Add(300*time.Millisecond).Format(time.RFC3339Nano),
+		expired.Hex(), now.Add(-time.Hour).Format(time.RFC3339Nano))
+
+	path := filepath.Join(t.TempDir(), "blocklist.json")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0644))
+
+	provider, err := newProvider("file://"+path, false, nil)
+	require.NoError(t, err)
+	require.IsType(t, &JSONProvider{}, provider)
+
+	addresses := provider.Provide()
+	require.Len(t, addresses, 2)
+	require.Contains(t, addresses, permanent)
+	require.Contains(t, addresses, expiring)
+
+	require.Eventually(t, func() bool {
+		_, ok := provider.Provide()[expiring]
+		return !ok
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, 1, provider.(ReloadableProvider).Status().Size)
+}
+
+func TestImmutableJSONProvider_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed":      `{"entries": [`,
+		"missingEntries": `{}`,
+		"missingAddress": `{"entries": [{"reason": "no address"}]}`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "blocklist.json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+			_, err := newProvider(path, false, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestImmutableNewProvider_Schemes(t *testing.T) {
+	tests := []struct {
+		source  string
+		path    string
+		isFile  bool
+		wantErr bool
+	}{
+		{source: "testdata/blocklist.txt", path: "testdata/blocklist.txt", isFile: true},
+		{source: "file://testdata/blocklist.txt", path: "testdata/blocklist.txt", isFile: true},
+		{source: "file:///etc/blocklist.txt", path: "/etc/blocklist.txt", isFile: true},
+		{source: "contract://0x72a5843cc08275C8171E582972Aa4fDa8C397B2A", isFile: false},
+		{source: "https://example.com/blocklist.json", isFile: false},
+		{source: "ftp://%zz", wantErr: true},
+	}
+	for _, test := range tests {
+		path, isFile, err := LocalPath(test.source)
+		if test.wantErr {
+			require.Error(t, err, test.source)
+			continue
+		}
+		require.NoError(t, err, test.source)
+		require.Equal(t, test.isFile, isFile, test.source)
+		require.Equal(t, filepath.FromSlash(test.path), path, test.source)
+	}
+	_, err := newProvider("ftp://example.com/blocklist.txt", false, nil)
+	require.ErrorContains(t, err, "unsupported")
+
+	_, err = newProvider("contract://0x72a5843cc08275C8171E582972Aa4fDa8C397B2A", false, nil)
+	require.ErrorContains(t, err, "requires chain access")
+}
@@ -17,45 +17,64 @@
 package accesscontrol
 
 import (
+	"crypto/sha256"
 	"errors"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
 )
 
 type CSVProvider struct {
 	filePath string
 
+	mu sync.RWMutex
 	// Contains a set of blockchain addresses, struct{} is used here as value to optimize memory footprint
 	addresses map[common.Address]struct{}
+	hash      common.Hash // SHA256 of the file content the addresses were parsed from
+	loadedAt  time.Time   // Time the current addresses were loaded
+	lastErr   error       // Error of the most recent reload attempt, if any
 }
 
 func newCSVProvider(filePath string) (*CSVProvider, error) {
-	addresses, err := load(filePath)
+	addresses, hash, err := load(filePath)
 	if err != nil {
 		return nil, err
 	}
+	log.Info("Loaded ACL file", "filepath", filePath, "addresses", len(addresses), "hash", hash)
 
 	return &CSVProvider{
 		filePath:  filePath,
 		addresses: addresses,
+		hash:      hash,
+		loadedAt:  time.Now(),
 	}, nil
 }
 
-func load(filePath string) (map[common.Address]struct{}, error) {
-	addresses := make(map[common.Address]struct{})
-
-	log.Info("Loading ACL file", "filepath", filePath)
+func load(filePath string) (map[common.Address]struct{}, common.Hash, error) {
 	byteValue, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, err
+		return nil, common.Hash{}, err
+	}
+	log.Debug("Read ACL file", "filepath", filePath, "content", string(byteValue))
+	addresses, err := parseCSV(byteValue)
+	if err != nil {
+		return nil, common.Hash{}, err
 	}
-	log.Info("Loaded ACL file", "filepath", filePath, "content", string(byteValue))
+	return addresses, sha256.Sum256(byteValue), nil
+}
+
+// parseCSV parses a comma separated list of hex addresses.
+func parseCSV(data []byte) (map[common.Address]struct{}, error) {
+	addresses := make(map[common.Address]struct{})
+
 	// Split the file content by comma to get individual Ethereum addresses
-	ethAddresses := strings.Split(string(byteValue), ",")
+	ethAddresses := strings.Split(string(data), ",")
 	for _, ethAddress := range ethAddresses {
 		ethAddress = strings.TrimSpace(ethAddress) // Just to be sure there's no leading or trailing whitespace
 		if common.IsHexAddress(ethAddress) {
@@ -66,10 +85,72 @@ func load(filePath string) (map[common.Address]struct{}, error) {
 	if len(addresses) == 0 {
 		return nil, errors.New("file is empty or does not contain any valid addresses")
 	}
-
 	return addresses, nil
 }
 
 func (s *CSVProvider) Provide() map[common.Address]struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.addresses
 }
+
+// Reload re-reads the CSV file and swaps in the new address set if the file
+// content changed. A file that fails to load leaves the current set in place.
+func (s *CSVProvider) Reload() (bool, error) {
+	addresses, hash, err := load(s.filePath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastErr = err
+	if err != nil {
+		log.Warn("Failed to reload ACL file, keeping previous contents", "filepath", s.filePath, "err", err)
+		return false, err
+	}
+	if hash == s.hash {
+		return false, nil
+	}
+	added, removed := diffAddresses(s.addresses, addresses)
+	s.addresses, s.hash, s.loadedAt = addresses, hash, time.Now()
+	log.Info("Reloaded ACL file", "filepath", s.filePath, "addresses", len(addresses), "added", added, "removed", removed, "hash", hash)
+	return true, nil
+}
+
+// diffAddresses returns the number of addresses added to and removed from the
+// previous set.
+func diffAddresses(prev, next map[common.Address]struct{}) (added int, removed int) {
+	for addr := range next {
+		if _, ok := prev[addr]; !ok {
+			added++
+		}
+	}
+	for addr := range prev {
+		if _, ok := next[addr]; !ok {
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// Status reports the currently loaded state of the CSV file.
+func (s *CSVProvider) Status() txpool.AccessListStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := txpool.AccessListStatus{
+		Source:   s.filePath,
+		Size:     len(s.addresses),
+		Hash:     s.hash,
+		LoadedAt: s.loadedAt,
+	}
+	if s.lastErr != nil {
+		status.LastError = s.lastErr.Error()
+	}
+	return status
+}
+
+// path returns the local file backing the provider, used for watching it.
+func (s *CSVProvider) path() string {
+	return s.filePath
+}
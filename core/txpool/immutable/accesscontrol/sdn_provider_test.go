@@ -17,6 +17,8 @@
 package accesscontrol
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -65,3 +67,54 @@ func TestImmutableCSVProvider_load(t *testing.T) {
 		})
 	}
 }
+
+func TestImmutableCSVProvider_Reload(t *testing.T) {
+	first := common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+	second := common.HexToAddress("0x7F19720A857F834887FC9A7bC0a0fBe7Fc7f8102")
+
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte(first.Hex()), 0644))
+
+	provider, err := newCSVProvider(path)
+	require.NoError(t, err)
+	initial := provider.Status()
+
+	// Unchanged content should not be reported as a change
+	changed, err := provider.Reload()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// New content should be swapped in with a new hash
+	require.NoError(t, os.WriteFile(path, []byte(first.Hex()+","+second.Hex()), 0644))
+	changed, err = provider.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Contains(t, provider.Provide(), second)
+	require.Equal(t, 2, provider.Status().Size)
+	require.NotEqual(t, initial.Hash, provider.Status().Hash)
+
+	// Corrupted content should keep the previous addresses
+	require.NoError(t, os.WriteFile(path, []byte("gibberish"), 0644))
+	changed, err = provider.Reload()
+	require.Error(t, err)
+	require.False(t, changed)
+	require.Len(t, provider.Provide(), 2)
+	require.NotEmpty(t, provider.Status().LastError)
+}
+
+func TestImmutableCSVProvider_diffAddresses(t *testing.T) {
+	first := common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+	second := common.HexToAddress("0x7F19720A857F834887FC9A7bC0a0fBe7Fc7f8102")
+	third := common.HexToAddress("0x1da5821544e25c636c1417ba96ade4cf6d2f9b5a")
+
+	prev := map[common.Address]struct{}{first: {}, second: {}}
+	next := map[common.Address]struct{}{second: {}, third: {}}
+
+	added, removed := diffAddresses(prev, next)
+	require.Equal(t, 1, added)
+	require.Equal(t, 1, removed)
+
+	added, removed = diffAddresses(nil, next)
+	require.Equal(t, 2, added)
+	require.Equal(t, 0, removed)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// TargetTracer is an EVM logger checking every account reached during the
+// execution of a transaction: the transaction recipient, the targets of all
+// CALL, CALLCODE, DELEGATECALL and STATICCALL frames (including plain value
+// transfers), created contracts and SELFDESTRUCT beneficiaries.
+//
+// Execution is aborted as soon as a target is rejected by the check.
+type TargetTracer struct {
+	check func(target txpool.CallTarget) error
+
+	env   *vm.EVM
+	depth int
+	err   error // First rejection, if any
+}
+
+// NewTargetTracer creates a tracer calling check for every account reached.
+func NewTargetTracer(check func(target txpool.CallTarget) error) *TargetTracer {
+	return &TargetTracer{check: check}
+}
+
+// Err returns the first rejection encountered during execution, if any.
+func (t *TargetTracer) Err() error {
+	return t.err
+}
+
+// Veto implements core.ExecutionVetoer, refusing transactions whose execution
+// reached a rejected target.
+func (t *TargetTracer) Veto() error {
+	return t.err
+}
+
+func (t *TargetTracer) visit(op vm.OpCode, to common.Address) {
+	if t.err != nil {
+		return
+	}
+	if err := t.check(txpool.CallTarget{Address: to, Op: op.String(), Depth: t.depth}); err != nil {
+		t.err = err
+		if t.env != nil {
+			t.env.Cancel()
+		}
+	}
+}
+
+func (t *TargetTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *TargetTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *TargetTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	if create {
+		t.visit(vm.CREATE, to)
+	} else {
+		t.visit(vm.CALL, to)
+	}
+}
+
+func (t *TargetTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *TargetTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.depth++
+	t.visit(typ, to)
+}
+
+func (t *TargetTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.depth--
+}
+
+func (t *TargetTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *TargetTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
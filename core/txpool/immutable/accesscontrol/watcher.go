@@ -0,0 +1,113 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accesscontrol
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is the delay between a filesystem event and the reload it
+// triggers, so that editors and config map updates writing a file in several
+// steps only cause a single reload.
+const reloadDebounce = 500 * time.Millisecond
+
+// watcher monitors the directories containing the access-control files and
+// invokes a callback when any of them changes. Directories are watched rather
+// than the files themselves, as files replaced through a rename (or a symlink
+// swap, as done by Kubernetes config maps) would otherwise drop the watch.
+type watcher struct {
+	fsw    *fsnotify.Watcher
+	reload func()
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+func newWatcher(paths []string, reload func()) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	dirs := make(map[string]struct{})
+	for _, path := range paths {
+		dirs[filepath.Dir(path)] = struct{}{}
+	}
+	for dir := range dirs {
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+		log.Info("Watching ACL directory for changes", "dir", dir)
+	}
+	w := &watcher{
+		fsw:    fsw,
+		reload: reload,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go w.loop()
+	return w, nil
+}
+
+func (w *watcher) loop() {
+	defer close(w.done)
+	defer w.fsw.Close()
+
+	var (
+		triggered = false
+		debounce  = time.NewTimer(0)
+	)
+	// Ignore initial trigger
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-w.quit:
+			return
+		case _, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// Reloading is cheap and unchanged files are skipped by their hash,
+			// so rather than matching event names, reload on any event.
+			if !triggered {
+				debounce.Reset(reloadDebounce)
+				triggered = true
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Warn("ACL filesystem watcher error", "err", err)
+		case <-debounce.C:
+			w.reload()
+			triggered = false
+		}
+	}
+}
+
+func (w *watcher) close() {
+	close(w.quit)
+	<-w.done
+}
@@ -17,8 +17,13 @@
 package txpool
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 )
 
 // AccessController defines access control for Ethereum addresses with relation to allowlists and blocklists.
@@ -28,3 +33,210 @@ type AccessController interface {
 	// IsBlocklist returns a bool indicating whether the controller is a blocklist type or is an allowlist type
 	IsBlocklist() bool
 }
+
+// ReloadableAccessController is an AccessController whose lists can be refreshed
+// while the node is running.
+type ReloadableAccessController interface {
+	AccessController
+
+	// Reload re-reads every list backing the controller. Lists that fail to load
+	// keep serving their previous contents.
+	Reload() error
+	// Status reports the currently loaded state of every list
+	Status() []AccessListStatus
+	// SubscribeReloadEvent notifies the subscriber whenever a list changes content
+	SubscribeReloadEvent(ch chan<- AccessControlReloadEvent) event.Subscription
+	// Close stops any background watchers owned by the controller
+	Close() error
+}
+
+// AccessListStatus describes the currently loaded state of a single access-control list.
+type AccessListStatus struct {
+	Source      string      `json:"source"`      // File path or URI the list was loaded from
+	IsBlocklist bool        `json:"isBlocklist"` // Whether the list blocks or allows its addresses
+	Size        int         `json:"size"`        // Number of addresses currently in the list
+	Hash        common.Hash `json:"hash"`        // SHA256 of the raw list contents
+	LoadedAt    time.Time   `json:"loadedAt"`    // Time the current contents were loaded
+	LastError   string      `json:"lastError,omitempty"`
+}
+
+// AccessControlReloadEvent is posted when one or more access-control lists change content.
+type AccessControlReloadEvent struct {
+	Sources []string
+}
+
+// accessControlledSubPool is implemented by subpools that enforce access-control
+// lists which can be reloaded at runtime.
+type accessControlledSubPool interface {
+	ReloadAccessControl() error
+	AccessControlStatus() []AccessListStatus
+}
+
+// ReloadAccessControl forces every access-controlled subpool to re-read its
+// lists and returns the resulting list statuses.
+func (p *TxPool) ReloadAccessControl() ([]AccessListStatus, error) {
+	var errs []error
+	for _, subpool := range p.subpools {
+		if controlled, ok := subpool.(accessControlledSubPool); ok {
+			errs = append(errs, controlled.ReloadAccessControl())
+		}
+	}
+	return p.AccessControlStatus(), errors.Join(errs...)
+}
+
+// AccessControlStatus reports the currently loaded state of every list used by
+// the access-controlled subpools.
+func (p *TxPool) AccessControlStatus() []AccessListStatus {
+	statuses := []AccessListStatus{}
+	for _, subpool := range p.subpools {
+		if controlled, ok := subpool.(accessControlledSubPool); ok {
+			statuses = append(statuses, controlled.AccessControlStatus()...)
+		}
+	}
+	return statuses
+}
+
+// TargetAccessController is an AccessController that can also vet the accounts
+// reached while executing a transaction, such as the targets of internal calls
+// made through routers or multicall contracts.
+type TargetAccessController interface {
+	AccessController
+
+	// IsTargetAllowed returns whether execution may reach the given account and,
+	// if not, the source of the list that forbids it.
+	IsTargetAllowed(target common.Address) (bool, string)
+}
+
+// CallTarget is an account reached while executing a transaction.
+type CallTarget struct {
+	Address common.Address `json:"address"`
+	Op      string         `json:"op"`    // CALL, DELEGATECALL, CREATE, SELFDESTRUCT, etc.
+	Depth   int            `json:"depth"` // Call depth, 0 being the transaction itself
+}
+
+// UnauthorizedError is the structured rejection of a transaction that reaches a
+// blocked account during execution. It matches ErrTxIsUnauthorized through
+// errors.Is and exposes its details as JSON-RPC error data.
+type UnauthorizedError struct {
+	Sender common.Address `json:"sender"`
+	Target CallTarget     `json:"target"`
+	Source string         `json:"source"` // List the target was found in
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("%v: %s reaches blocked address %s at depth %d", ErrTxIsUnauthorized, e.Target.Op, e.Target.Address, e.Target.Depth)
+}
+
+// Unwrap returns ErrTxIsUnauthorized.
+func (e *UnauthorizedError) Unwrap() error {
+	return ErrTxIsUnauthorized
+}
+
+// ErrorData implements rpc.DataError, returning the rejection details to the
+// submitter of the transaction.
+func (e *UnauthorizedError) ErrorData() interface{} {
+	return e
+}
+
+// targetCheckingSubPool is implemented by subpools able to vet the accounts
+// reached during transaction execution.
+type targetCheckingSubPool interface {
+	CheckCallTarget(sender common.Address, target CallTarget) error
+}
+
+// CheckCallTarget returns an *UnauthorizedError if any subpool's access control
+// forbids executions by the sender from reaching the target.
+func (p *TxPool) CheckCallTarget(sender common.Address, target CallTarget) error {
+	for _, subpool := range p.subpools {
+		if checker, ok := subpool.(targetCheckingSubPool); ok {
+			if err := checker.CheckCallTarget(sender, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// transactionCheckingSubPool is implemented by subpools enforcing access control
+// on the sender and recipient of transactions.
+type transactionCheckingSubPool interface {
+	CheckTransactionAccess(sender common.Address, tx *types.Transaction) error
+}
+
+// CheckTransactionAccess returns ErrTxIsUnauthorized if any subpool's access
+// control forbids the transaction, regardless of which subpool would handle
+// its type. It implements core.AccessPolicy, allowing the same lists to be
+// enforced on imported blocks.
+func (p *TxPool) CheckTransactionAccess(sender common.Address, tx *types.Transaction) error {
+	for _, subpool := range p.subpools {
+		if checker, ok := subpool.(transactionCheckingSubPool); ok {
+			if err := checker.CheckTransactionAccess(sender, tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MatchingAccessController is an AccessController that can report which list
+// decided the fate of a transaction.
+type MatchingAccessController interface {
+	AccessController
+
+	// Match returns whether the transaction is allowed, and the source of the
+	// list that matched the sender or recipient, if any.
+	Match(sender common.Address, tx *types.Transaction) (bool, string)
+}
+
+// AccessControlRejection is the audit record of a transaction dropped by the
+// access controllers of a pool.
+type AccessControlRejection struct {
+	Time      time.Time       `json:"time"`
+	Hash      common.Hash     `json:"hash"`
+	Sender    common.Address  `json:"sender"`
+	Recipient *common.Address `json:"recipient"`
+	Source    string          `json:"source"` // List that matched, empty if rejected by an allowlist not containing the sender
+	Action    string          `json:"action"` // Either "rejected" on admission or "evicted" after a list reload
+	Target    *CallTarget     `json:"target,omitempty"`
+}
+
+// Actions taken on transactions violating the access control.
+const (
+	AccessControlRejected = "rejected"
+	AccessControlEvicted  = "evicted"
+)
+
+// AccessControlRejectionQuery selects a page of audit records by time range.
+type AccessControlRejectionQuery struct {
+	From   *time.Time `json:"from,omitempty"`   // Inclusive lower bound, unbounded if nil
+	To     *time.Time `json:"to,omitempty"`     // Exclusive upper bound, unbounded if nil
+	Limit  int        `json:"limit,omitempty"`  // Maximum number of records in the page
+	Cursor string     `json:"cursor,omitempty"` // Continuation of a previous page
+}
+
+// AccessControlRejectionPage is a page of audit records in chronological order.
+type AccessControlRejectionPage struct {
+	Rejections []AccessControlRejection `json:"rejections"`
+	Next       string                   `json:"next,omitempty"`       // Cursor of the next page, empty if none
+	WriteError string                   `json:"writeError,omitempty"` // Error of the last record that couldn't be written, if still failing
+}
+
+// auditedSubPool is implemented by subpools keeping an audit log of the
+// transactions dropped by their access controllers.
+type auditedSubPool interface {
+	AccessControlRejections(query AccessControlRejectionQuery) (*AccessControlRejectionPage, error)
+}
+
+// AccessControlRejections retrieves a page of the access-control audit log.
+func (p *TxPool) AccessControlRejections(query AccessControlRejectionQuery) (*AccessControlRejectionPage, error) {
+	for _, subpool := range p.subpools {
+		if audited, ok := subpool.(auditedSubPool); ok {
+			return audited.AccessControlRejections(query)
+		}
+	}
+	return nil, ErrAuditLogDisabled
+}
+
+// ErrAuditLogDisabled is returned when querying the access-control audit log
+// of a pool that doesn't keep one.
+var ErrAuditLogDisabled = errors.New("access-control audit log is disabled")
@@ -24,6 +24,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -383,3 +384,171 @@ func TestImmutableRebroadcasting(t *testing.T) {
 		t.Fatalf("pool internal state corrupted: %v", err)
 	}
 }
+
+func TestImmutableAccessControlReloadEvictsUnauthorized(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	allowedKey, _ := crypto.GenerateKey()
+	blockedKey, _ := crypto.GenerateKey()
+	initial := common.HexToAddress("0x72a5843cc08275C8171E582972Aa4fDa8C397B2A")
+
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte(initial.Hex()), 0644); err != nil {
+		t.Fatal("Failed to write blocklist")
+	}
+	config := testTxPoolConfig
+	config.BlockListFilePaths = []string{blocklist}
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	for _, key := range []*ecdsa.PrivateKey{allowedKey, blockedKey} {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+		// One executable and one gapped transaction per account
+		if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+			t.Fatalf("Failed to add pending transaction: %v", err)
+		}
+		if err := pool.addRemoteSync(transaction(2, 100000, key)); err != nil {
+			t.Fatalf("Failed to add queued transaction: %v", err)
+		}
+	}
+	if pending, queued := pool.Stats(); pending != 2 || queued != 2 {
+		t.Fatalf("Unexpected pool stats before reload: pending %d, queued %d", pending, queued)
+	}
+	blocked := crypto.PubkeyToAddress(blockedKey.PublicKey)
+	if err := os.WriteFile(blocklist, []byte(initial.Hex()+","+blocked.Hex()), 0644); err != nil {
+		t.Fatal("Failed to update blocklist")
+	}
+	if err := pool.ReloadAccessControl(); err != nil {
+		t.Fatalf("Failed to reload access control: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		pending, queued := pool.Stats()
+		if pending == 1 && queued == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Unauthorized transactions not evicted: pending %d, queued %d", pending, queued)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p, q := pool.ContentFrom(blocked); len(p)+len(q) != 0 {
+		t.Fatalf("Blocked sender still has pooled transactions: pending %d, queued %d", len(p), len(q))
+	}
+	status := pool.AccessControlStatus()
+	if len(status) != 1 || status[0].Size != 2 {
+		t.Fatalf("Unexpected access control status: %+v", status)
+	}
+}
+
+// executionTestChain is a testBlockChain whose head can be used to set up an
+// EVM block context.
+type executionTestChain struct {
+	*testBlockChain
+}
+
+func (bc *executionTestChain) CurrentBlock() *types.Header {
+	head := bc.testBlockChain.CurrentBlock()
+	head.Difficulty = new(big.Int)
+	return head
+}
+
+func TestImmutableExecutionAccessControl(t *testing.T) {
+	var (
+		blockedKey, _ = crypto.GenerateKey()
+		senderKey, _  = crypto.GenerateKey()
+		blocked       = crypto.PubkeyToAddress(blockedKey.PublicKey)
+		router        = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		passthrough   = common.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	// callCode forwards the call value to the given address
+	callCode := func(to common.Address) []byte {
+		code := []byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x34, 0x73}
+		code = append(code, to.Bytes()...)
+		return append(code, 0x5a, 0xf1, 0x00) // GAS CALL STOP
+	}
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(router, callCode(blocked))
+	statedb.SetCode(passthrough, callCode(common.HexToAddress("0x1")))
+	blockchain := &executionTestChain{newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))}
+
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte(blocked.Hex()), 0644); err != nil {
+		t.Fatal("Failed to write blocklist")
+	}
+	config := testTxPoolConfig
+	config.BlockListFilePaths = []string{blocklist}
+	config.ExecutionAccessControl = true
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	testAddBalance(pool, sender, big.NewInt(1000000000))
+
+	// A call through the router reaches the blocked address
+	err := pool.addRemoteSync(pricedTransactionTo(0, 100000, big.NewInt(1), senderKey, router))
+	if !errors.Is(err, txpool.ErrTxIsUnauthorized) {
+		t.Fatalf("Expected unauthorized error, got: %v", err)
+	}
+	var unauthorized *txpool.UnauthorizedError
+	if !errors.As(err, &unauthorized) {
+		t.Fatalf("Expected structured rejection, got: %T", err)
+	}
+	if unauthorized.Sender != sender || unauthorized.Target.Address != blocked || unauthorized.Target.Op != "CALL" ||
+		unauthorized.Target.Depth != 1 || unauthorized.Source != blocklist {
+		t.Fatalf("Unexpected rejection details: %+v", unauthorized)
+	}
+	// A call through a contract not reaching any blocked address is accepted
+	if err := pool.addRemoteSync(pricedTransactionTo(0, 100000, big.NewInt(1), senderKey, passthrough)); err != nil {
+		t.Fatalf("Failed to add allowed transaction: %v", err)
+	}
+}
+
+func TestImmutableAccessControlAuditLog(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	blockedKey, _ := crypto.GenerateKey()
+	blocked := crypto.PubkeyToAddress(blockedKey.PublicKey)
+
+	dir := t.TempDir()
+	blocklist := filepath.Join(dir, "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte(blocked.Hex()), 0644); err != nil {
+		t.Fatal("Failed to write blocklist")
+	}
+	config := testTxPoolConfig
+	config.BlockListFilePaths = []string{blocklist}
+	config.AuditLog = filepath.Join(dir, "audit.jsonl")
+	config.AuditLogMaxSize = 1
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	start := time.Now()
+	tx := transaction(0, 100000, blockedKey)
+	if err := pool.FilterWithError(tx); err != txpool.ErrTxIsUnauthorized {
+		t.Fatalf("Transaction was not blocked: %v", err)
+	}
+	page, err := pool.AccessControlRejections(txpool.AccessControlRejectionQuery{From: &start})
+	if err != nil {
+		t.Fatalf("Failed to query audit log: %v", err)
+	}
+	if len(page.Rejections) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(page.Rejections))
+	}
+	record := page.Rejections[0]
+	if record.Hash != tx.Hash() || record.Sender != blocked || record.Source != blocklist || record.Action != txpool.AccessControlRejected {
+		t.Fatalf("Unexpected audit record: %+v", record)
+	}
+	// Records are filtered by time range
+	page, err = pool.AccessControlRejections(txpool.AccessControlRejectionQuery{To: &start})
+	if err != nil || len(page.Rejections) != 0 {
+		t.Fatalf("Unexpected audit records before the rejection: %v, %v", page, err)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	gomath "math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/immutable/accesscontrol"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// accessControlEvictionMeter counts the transactions evicted after an
+// access-control list reload made their sender or recipient unauthorized.
+var accessControlEvictionMeter = metrics.NewRegisteredMeter("txpool/accesscontrol/evicted", nil)
+
+// accessControlExecutionRejectMeter counts the transactions rejected because
+// their simulated execution reached a blocked account.
+var accessControlExecutionRejectMeter = metrics.NewRegisteredMeter("txpool/accesscontrol/execution/rejected", nil)
+
+// ReloadAccessControl forces every reloadable access controller of the pool to
+// re-read its lists. Transactions made unauthorized by the new lists are
+// evicted asynchronously by the pool's event loop.
+func (pool *LegacyPool) ReloadAccessControl() error {
+	var errs []error
+	for _, controller := range pool.accessControllers {
+		if reloadable, ok := controller.(txpool.ReloadableAccessController); ok {
+			errs = append(errs, reloadable.Reload())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AccessControlStatus reports the currently loaded state of every list used by
+// the pool's access controllers.
+func (pool *LegacyPool) AccessControlStatus() []txpool.AccessListStatus {
+	statuses := []txpool.AccessListStatus{}
+	for _, controller := range pool.accessControllers {
+		if reloadable, ok := controller.(txpool.ReloadableAccessController); ok {
+			statuses = append(statuses, reloadable.Status()...)
+		}
+	}
+	return statuses
+}
+
+// subscribeAccessControlReloads subscribes the given channel to the reload
+// events of every reloadable access controller of the pool.
+func (pool *LegacyPool) subscribeAccessControlReloads(ch chan<- txpool.AccessControlReloadEvent) []event.Subscription {
+	var subs []event.Subscription
+	for _, controller := range pool.accessControllers {
+		if reloadable, ok := controller.(txpool.ReloadableAccessController); ok {
+			subs = append(subs, reloadable.SubscribeReloadEvent(ch))
+		}
+	}
+	return subs
+}
+
+// closeAccessControllers stops the background watchers of the access controllers
+// and closes the audit log.
+func (pool *LegacyPool) closeAccessControllers() {
+	for _, controller := range pool.accessControllers {
+		if reloadable, ok := controller.(txpool.ReloadableAccessController); ok {
+			reloadable.Close()
+		}
+	}
+	if pool.auditLog != nil {
+		if err := pool.auditLog.Close(); err != nil {
+			log.Warn("Failed to close access control audit log", "err", err)
+		}
+	}
+}
+
+// matchAccessControl checks the transaction against every access controller,
+// returning whether it is allowed and, if not, the source of the list that
+// rejected it.
+func (pool *LegacyPool) matchAccessControl(from common.Address, tx *types.Transaction) (bool, string) {
+	for _, controller := range pool.accessControllers {
+		if matcher, ok := controller.(txpool.MatchingAccessController); ok {
+			if allowed, source := matcher.Match(from, tx); !allowed {
+				return false, source
+			}
+			continue
+		}
+		if !controller.IsAllowed(from, tx) {
+			return false, ""
+		}
+	}
+	return true, ""
+}
+
+// recordRejection counts a transaction dropped by the access controllers against
+// the list that matched it, and appends it to the audit log if enabled.
+func (pool *LegacyPool) recordRejection(tx *types.Transaction, from common.Address, source string, action string, target *txpool.CallTarget) {
+	accesscontrol.RejectionCounter(source).Inc(1)
+
+	if pool.auditLog == nil {
+		return
+	}
+	err := pool.auditLog.Record(txpool.AccessControlRejection{
+		Hash:      tx.Hash(),
+		Sender:    from,
+		Recipient: tx.To(),
+		Source:    source,
+		Action:    action,
+		Target:    target,
+	})
+	if err != nil {
+		log.Error("Failed to write access control audit log", "tx", tx.Hash(), "err", err)
+	}
+}
+
+// AccessControlRejections retrieves a page of the access control audit log.
+func (pool *LegacyPool) AccessControlRejections(query txpool.AccessControlRejectionQuery) (*txpool.AccessControlRejectionPage, error) {
+	if pool.auditLog == nil {
+		return nil, txpool.ErrAuditLogDisabled
+	}
+	return pool.auditLog.Query(query)
+}
+
+// evictUnauthorized re-evaluates every pending and queued transaction against
+// the access controllers and drops the ones no longer allowed.
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) evictUnauthorized(sources []string) {
+	var drop []common.Hash
+	collect := func(accounts map[common.Address]*list) {
+		for from, txs := range accounts {
+			for _, tx := range txs.Flatten() {
+				if allowed, source := pool.matchAccessControl(from, tx); !allowed {
+					drop = append(drop, tx.Hash())
+					pool.recordRejection(tx, from, source, txpool.AccessControlEvicted, nil)
+				}
+			}
+		}
+	}
+	collect(pool.pending)
+	collect(pool.queue)
+
+	for _, hash := range drop {
+		pool.removeTx(hash, true, true)
+	}
+	if len(drop) > 0 {
+		accessControlEvictionMeter.Mark(int64(len(drop)))
+		log.Info("Evicted unauthorized transactions after access-control reload", "sources", sources, "evicted", len(drop))
+	}
+}
+
+// CheckCallTarget returns an *txpool.UnauthorizedError if any of the pool's
+// access controllers forbids executions by the sender from reaching the target.
+func (pool *LegacyPool) CheckCallTarget(sender common.Address, target txpool.CallTarget) error {
+	for _, controller := range pool.accessControllers {
+		checker, ok := controller.(txpool.TargetAccessController)
+		if !ok {
+			continue
+		}
+		if allowed, source := checker.IsTargetAllowed(target.Address); !allowed {
+			return &txpool.UnauthorizedError{Sender: sender, Target: target, Source: source}
+		}
+	}
+	return nil
+}
+
+// checkExecution simulates the transaction on top of the pool's current state
+// and rejects it if its execution reaches an account forbidden by the access
+// controllers. Execution failures other than access violations are left to
+// the regular validation rules, as the transaction might only become
+// executable later on (e.g. nonce gaps).
+//
+// The caller must hold pool.mu.
+func (pool *LegacyPool) checkExecution(tx *types.Transaction, from common.Address) error {
+	if !pool.config.ExecutionAccessControl || len(pool.accessControllers) == 0 {
+		return nil
+	}
+	head := pool.currentHead.Load()
+	msg, err := core.TransactionToMessage(tx, pool.signer, head.BaseFee)
+	if err != nil {
+		return nil
+	}
+	msg.SkipAccountChecks = true
+
+	tracer := accesscontrol.NewTargetTracer(func(target txpool.CallTarget) error {
+		return pool.CheckCallTarget(from, target)
+	})
+	var (
+		statedb  = pool.currentState.Copy()
+		blockCtx = core.NewEVMBlockContext(head, &poolChainContext{pool.chain}, &head.Coinbase, pool.chainconfig)
+	)
+	// Pooled transactions may be priced below the current base fee, waiting for
+	// it to drop, so don't let fee checks cut the simulation short.
+	blockCtx.BaseFee = new(big.Int)
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, pool.chainconfig, vm.Config{Tracer: tracer, NoBaseFee: true})
+	core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(gomath.MaxUint64))
+
+	if err := tracer.Err(); err != nil {
+		accessControlExecutionRejectMeter.Mark(1)
+		var unauthorized *txpool.UnauthorizedError
+		if errors.As(err, &unauthorized) {
+			pool.recordRejection(tx, from, unauthorized.Source, txpool.AccessControlRejected, &unauthorized.Target)
+		}
+		return err
+	}
+	return nil
+}
+
+// poolChainContext adapts the pool's chain to the core.ChainContext needed to
+// simulate transactions. The consensus engine is never consulted, as the block
+// author is always given explicitly.
+type poolChainContext struct {
+	chain BlockChain
+}
+
+func (c *poolChainContext) Engine() consensus.Engine {
+	return nil
+}
+
+func (c *poolChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if block := c.chain.GetBlock(hash, number); block != nil {
+		return block.Header()
+	}
+	return nil
+}
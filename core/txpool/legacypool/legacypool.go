@@ -139,6 +139,12 @@ type Config struct {
 
 	// CHANGE(immutable): Added access controllers filepaths for blocklist
 	BlockListFilePaths []string `toml:",omitempty"`
+	// CHANGE(immutable): Simulate transactions to check the accounts they reach against the access controllers
+	ExecutionAccessControl bool `toml:",omitempty"`
+	// CHANGE(immutable): Audit log of the transactions dropped by the access controllers
+	AuditLog           string `toml:",omitempty"` // JSONL file of the audit log, disabled if empty
+	AuditLogMaxSize    int    `toml:",omitempty"` // Size in megabytes after which the audit log is rotated
+	AuditLogMaxBackups int    `toml:",omitempty"` // Number of rotated audit log files kept, 0 keeps all
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -158,6 +164,9 @@ var DefaultConfig = Config{
 
 	// CHANGE(immutable): Added access controllers filepaths for blocklist
 	BlockListFilePaths: []string{},
+	// CHANGE(immutable): Added access control audit log rotation
+	AuditLogMaxSize:    100,
+	AuditLogMaxBackups: 10,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -196,14 +205,23 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
-	// CHANGE(immutable): Ensure each file in BlockListFiles exists
-	for _, filePath := range config.BlockListFilePaths {
-		if err := txpool.EnsureFileCanBeRead(filePath); err != nil {
+	// CHANGE(immutable): Ensure each file in BlockListFiles exists, remote sources are checked on load
+	for _, source := range config.BlockListFilePaths {
+		filePath, isFile, err := accesscontrol.LocalPath(source)
+		if err == nil && isFile {
+			err = txpool.EnsureFileCanBeRead(filePath)
+		}
+		if err != nil {
 			log.Warn("Sanitizing invalid txpool block list filepaths", err.Error(), "provided", conf.BlockListFilePaths, "updated", DefaultConfig.BlockListFilePaths)
 			conf.BlockListFilePaths = DefaultConfig.BlockListFilePaths
 			break
 		}
 	}
+	// CHANGE(immutable): Ensure the audit log rotates
+	if conf.AuditLog != "" && conf.AuditLogMaxSize < 1 {
+		log.Warn("Sanitizing invalid txpool audit log size", "provided", conf.AuditLogMaxSize, "updated", DefaultConfig.AuditLogMaxSize)
+		conf.AuditLogMaxSize = DefaultConfig.AuditLogMaxSize
+	}
 	return conf
 }
 
@@ -249,6 +267,7 @@ type LegacyPool struct {
 
 	// CHANGE(immutable): Added a list of access controllers to legacy pool
 	accessControllers []txpool.AccessController // List of access controllers that determines whether a sender is allowed to perform a tx
+	auditLog          *accesscontrol.AuditLog   // Audit log of the transactions dropped by the access controllers, nil if disabled
 }
 
 type txpoolResetRequest struct {
@@ -316,6 +335,19 @@ func (pool *LegacyPool) FilterWithError(tx *types.Transaction) error {
 		return txpool.ErrInvalidSender
 	}
 
+	if allowed, source := pool.matchAccessControl(from, tx); !allowed {
+		log.Warn("Transaction is not allowed by access control", "from", from, "to", tx.To(), "tx", tx.Hash(), "list", source)
+		pool.recordRejection(tx, from, source, txpool.AccessControlRejected, nil)
+		return txpool.ErrTxIsUnauthorized
+	}
+	return nil
+}
+
+// CheckTransactionAccess
+// CHANGE(immutable):
+// Check the sender and recipient of a transaction against the access controllers,
+// regardless of the transaction type
+func (pool *LegacyPool) CheckTransactionAccess(from common.Address, tx *types.Transaction) error {
 	if len(pool.accessControllers) == 0 {
 		// No access controllers, allow the transaction to pass
 		return nil
@@ -324,7 +356,7 @@ func (pool *LegacyPool) FilterWithError(tx *types.Transaction) error {
 	// Check for every access controllers that this transaction is allowed to go through
 	for _, accessControl := range pool.accessControllers {
 		if !accessControl.IsAllowed(from, tx) {
-			log.Warn("Transaction is not allowed by access control",
+			log.Debug("Transaction is not allowed by access control",
 				"from", from, "to", tx.To(), "tx", tx.Hash(), "isBlockList", accessControl.IsBlocklist())
 			// If any access control doesn't allow
 			return txpool.ErrTxIsUnauthorized
@@ -376,14 +408,26 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 	// CHANGE(immutable): Initialize accessControllers on pool based on pool acls config
 	acls := []txpool.AccessController{}
 	if len(pool.config.BlockListFilePaths) > 0 {
-		blockListACL, err := accesscontrol.New(pool.config.BlockListFilePaths, false)
+		blockListACL, err := accesscontrol.New(pool.config.BlockListFilePaths, false, pool.chain)
 		if err != nil {
 			return err
 		}
+		// CHANGE(immutable): Reload the blocklists when their files change on disk
+		if err := blockListACL.Watch(); err != nil {
+			log.Warn("Failed to watch txpool block list files, reload via RPC only", "err", err)
+		}
 		acls = append(acls, blockListACL)
 	}
 	pool.accessControllers = acls
 
+	// CHANGE(immutable): Open the access control audit log
+	if pool.config.AuditLog != "" {
+		pool.auditLog, err = accesscontrol.NewAuditLog(pool.config.AuditLog, pool.config.AuditLogMaxSize, pool.config.AuditLogMaxBackups)
+		if err != nil {
+			return err
+		}
+	}
+
 	pool.wg.Add(1)
 	go pool.loop()
 	return nil
@@ -407,6 +451,12 @@ func (pool *LegacyPool) loop() {
 	defer evict.Stop()
 	defer journal.Stop()
 
+	// CHANGE(immutable): Track access-control list reloads to evict newly unauthorized transactions
+	aclReloadCh := make(chan txpool.AccessControlReloadEvent, 1)
+	for _, sub := range pool.subscribeAccessControlReloads(aclReloadCh) {
+		defer sub.Unsubscribe()
+	}
+
 	// Notify tests that the init phase is done
 	close(pool.initDoneCh)
 	for {
@@ -446,6 +496,12 @@ func (pool *LegacyPool) loop() {
 			}
 			pool.mu.Unlock()
 
+		// CHANGE(immutable): Handle access-control list reloads
+		case ev := <-aclReloadCh:
+			pool.mu.Lock()
+			pool.evictUnauthorized(ev.Sources)
+			pool.mu.Unlock()
+
 		// Handle local transaction journal rotation
 		case <-journal.C:
 			if pool.journal != nil {
@@ -465,6 +521,9 @@ func (pool *LegacyPool) Close() error {
 	close(pool.reorgShutdownCh)
 	pool.wg.Wait()
 
+	// CHANGE(immutable): Stop watching access-control list files
+	pool.closeAccessControllers()
+
 	if pool.journal != nil {
 		pool.journal.close()
 	}
@@ -750,6 +809,13 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 	// already validated by this point
 	from, _ := types.Sender(pool.signer, tx)
 
+	// CHANGE(immutable): Reject transactions whose execution reaches a blocked account
+	if err := pool.checkExecution(tx, from); err != nil {
+		log.Warn("Transaction execution is not allowed by access control", "from", from, "tx", hash, "err", err)
+		invalidTxMeter.Mark(1)
+		return false, err
+	}
+
 	// If the address is not yet known, request exclusivity to track the account
 	// only by this subpool until all transactions are evicted
 	var (
@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
@@ -297,12 +298,19 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
 	// CHANGE(immutable): Handle RPC forwarding.
-	if b.eth.rpcProxyClient != nil {
+	if b.eth.rpcProxy != nil {
 		data, err := signedTx.MarshalBinary()
 		if err != nil {
 			return fmt.Errorf("failed to marshall binary for rpc proxy forwarding: %s", err.Error())
 		}
-		return b.eth.rpcProxyClient.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+		if err := b.eth.rpcProxy.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data)); err != nil {
+			return err
+		}
+		// Serve the transaction locally until the block including it arrives
+		if err := b.eth.shadowPool.add(signedTx); err != nil {
+			log.Warn("Failed to track forwarded transaction", "hash", signedTx.Hash(), "err", err)
+		}
+		return nil
 	}
 
 	return b.eth.txPool.Add([]*types.Transaction{signedTx}, true, false)[0]
@@ -322,7 +330,11 @@ func (b *EthAPIBackend) GetPoolTransactions() (types.Transactions, error) {
 }
 
 func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
-	return b.eth.txPool.Get(hash)
+	// CHANGE(immutable): Serve transactions forwarded to the Immutable RPC.
+	if tx := b.eth.txPool.Get(hash); tx != nil {
+		return tx
+	}
+	return b.eth.shadowPool.get(hash)
 }
 
 // GetTransaction retrieves the lookup along with the transaction itself associate
@@ -347,7 +359,8 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 }
 
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
-	return b.eth.txPool.Nonce(addr), nil
+	// CHANGE(immutable): Account for transactions forwarded to the Immutable RPC.
+	return b.eth.shadowPool.pendingNonce(addr, b.eth.txPool.Nonce(addr)), nil
 }
 
 func (b *EthAPIBackend) Stats() (runnable int, blocked int) {
@@ -380,8 +393,8 @@ func (b *EthAPIBackend) SyncProgress() ethereum.SyncProgress {
 }
 
 func (b *EthAPIBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
-	// CHANGE(immutable): Record metric for tipcap
-	tipcap, err := b.gpo.SuggestTipCap(ctx)
+	// CHANGE(immutable): Record metric for tipcap and use the Immutable fee strategy
+	tipcap, err := b.gpo.SuggestImmutableTipCap(ctx)
 	if err != nil {
 		return nil, err
 	}
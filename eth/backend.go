@@ -104,8 +104,16 @@ type Ethereum struct {
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 
-	// CHANGE(immutable): Add RPC client for forwarding to Immutable RPC.
-	rpcProxyClient *rpc.Client
+	// CHANGE(immutable): Add RPC upstream pool for forwarding to Immutable RPC,
+	// and the forwarded transactions awaiting inclusion.
+	rpcProxy   *rpc.ProxyPool
+	shadowPool *shadowPool
+
+	// CHANGE(immutable): Detects signers sealing conflicting blocks
+	equivocations *equivocationWatcher
+
+	// CHANGE(immutable): Publishes clique signer liveness metrics
+	signerHealth *signerHealthExporter
 }
 
 // New creates a new Ethereum object (including the
@@ -122,6 +130,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
 	}
+	// CHANGE(immutable): Sanitize the signer health metrics window
+	if config.SignerHealthWindow == 0 || config.SignerHealthWindow > clique.MaxHealthRange {
+		log.Warn("Sanitizing invalid signer health window", "provided", config.SignerHealthWindow, "updated", ethconfig.Defaults.SignerHealthWindow)
+		config.SignerHealthWindow = ethconfig.Defaults.SignerHealthWindow
+	}
 	if config.NoPruning && config.TrieDirtyCache > 0 {
 		if config.SnapshotCache > 0 {
 			config.TrieCleanCache += config.TrieDirtyCache * 3 / 5
@@ -225,6 +238,8 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.OverrideShanghai != nil {
 		overrides.OverrideShanghai = config.OverrideShanghai
 	}
+	// CHANGE(immutable): Add fork schedule overrides
+	overrides.OverrideForks = config.OverrideForks
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, config.Genesis, &overrides, eth.engine, vmConfig, eth.shouldPreserve, &config.TransactionHistory)
 	if err != nil {
 		return nil, err
@@ -239,12 +254,22 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
+	// CHANGE(immutable): Resolve the access control audit log relative to the datadir
+	if config.TxPool.AuditLog != "" {
+		config.TxPool.AuditLog = stack.ResolvePath(config.TxPool.AuditLog)
+	}
 	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
 
 	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
 	if err != nil {
 		return nil, err
 	}
+	// CHANGE(immutable): Enforce the txpool access-control lists on imported blocks
+	accessPolicyMode, err := core.ParseAccessPolicyMode(config.BlockAccessPolicy)
+	if err != nil {
+		return nil, err
+	}
+	eth.blockchain.SetAccessPolicy(eth.txPool, accessPolicyMode, config.BlockAccessPolicyFrom)
 	// Permit the downloader to use the trie cache allowance during fast sync
 	cacheLimit := cacheConfig.TrieCleanLimit + cacheConfig.TrieDirtyLimit + cacheConfig.SnapshotLimit
 	if eth.handler, err = newHandler(&handlerConfig{
@@ -261,6 +286,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		GossipDefault: config.GossipDefault,
 		// CHANGE(immutable): disable txpool gossip configuration
 		DisableTxPoolGossip: config.DisableTxPoolGossip,
+		// CHANGE(immutable): per-role gossip policies
+		Gossip: config.Gossip,
+		// CHANGE(immutable): block fetcher catch-up mode
+		FetcherCatchUp: config.FetcherCatchUp,
+		// CHANGE(immutable): peer registry signed by a boot node
+		PeerRegistry:        config.PeerRegistry,
+		PeerRegistrySigners: stack.Config().P2P.BootstrapNodes,
 	}); err != nil {
 		return nil, err
 	}
@@ -289,15 +321,19 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 
-	// CHANGE(immutable): Handle immutable RPC client.
-	if config.RPCProxyURL != "" {
+	// CHANGE(immutable): Handle immutable RPC upstreams.
+	if config.RPCProxy.Enabled {
+		if len(config.RPCProxy.Upstreams) == 0 {
+			return nil, errors.New("RPC proxy enabled without upstreams")
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		client, err := rpc.DialContext(ctx, config.RPCProxyURL)
+		pool, err := rpc.NewProxyPool(ctx, config.RPCProxy)
 		if err != nil {
 			return nil, err
 		}
-		eth.rpcProxyClient = client
+		eth.rpcProxy = pool
+		eth.shadowPool = newShadowPool(eth.blockchain)
 	}
 
 	// Start the RPC service
@@ -359,6 +395,14 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
+		}, {
+			// CHANGE(immutable): Expose the txpool access-control status
+			Namespace: "txpool",
+			Service:   NewTxPoolAccessControlAPI(s),
+		}, {
+			// CHANGE(immutable): Expose the Immutable fee estimates
+			Namespace: "immutable",
+			Service:   NewImmutableAPI(s),
 		}}...)
 }
 
@@ -539,6 +583,12 @@ func (s *Ethereum) Start() error {
 	// Regularly update shutdown marker
 	s.shutdownTracker.Start()
 
+	// CHANGE(immutable): Watch imported blocks for equivocating signers
+	s.equivocations = newEquivocationWatcher(s.engine, s.blockchain)
+
+	// CHANGE(immutable): Export clique signer health metrics
+	s.signerHealth = newSignerHealthExporter(s.engine, s.blockchain, s.config.SignerHealthWindow)
+
 	// Figure out a max peers count based on the server limits
 	maxPeers := s.p2pServer.MaxPeers
 	if s.config.LightServ > 0 {
@@ -565,6 +615,10 @@ func (s *Ethereum) Stop() error {
 	close(s.closeBloomHandler)
 	s.txPool.Close()
 	s.miner.Close()
+	// CHANGE(immutable): Stop the clique watchers and the shadow pool before the chain
+	s.equivocations.stop()
+	s.signerHealth.stop()
+	s.shadowPool.stop()
 	s.blockchain.Stop()
 	s.engine.Close()
 
@@ -574,9 +628,9 @@ func (s *Ethereum) Stop() error {
 	s.chainDb.Close()
 	s.eventMux.Stop()
 
-	// CHANGE(immutable): Handle shutdown of client.
-	if s.rpcProxyClient != nil {
-		s.rpcProxyClient.Close()
+	// CHANGE(immutable): Handle shutdown of the upstream pool.
+	if s.rpcProxy != nil {
+		s.rpcProxy.Close()
 	}
 
 	return nil
@@ -1508,6 +1508,13 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	for i, result := range results {
 		blocks[i] = types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
 	}
+	// CHANGE(immutable): Attribute downloaded blocks to the sync master peer
+	if recorder, ok := d.blockchain.(blockOriginRecorder); ok {
+		d.cancelLock.RLock()
+		peer := d.cancelPeer
+		d.cancelLock.RUnlock()
+		recorder.RecordBlockOrigin("downloader", peer, blocks)
+	}
 	// Downloaded blocks are always regarded as trusted after the
 	// transition. Because the downloaded chain is guided by the
 	// consensus-layer.
@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // FullNodeGPO contains default gasprice oracle settings for full node.
@@ -67,6 +68,12 @@ var Defaults = Config{
 	RPCEVMTimeout:      5 * time.Second,
 	GPO:                FullNodeGPO,
 	RPCTxFeeCap:        1, // 1 ether
+	// CHANGE(immutable): Default max filter block range
+	FilterMaxBlockRange: 5000,
+	// CHANGE(immutable): Default retries of forwarded calls
+	RPCProxy: rpc.ProxyConfig{Retries: 2},
+	// CHANGE(immutable): Default number of blocks of the signer health metrics
+	SignerHealthWindow: 1024,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -128,6 +135,11 @@ type Config struct {
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
 
+	// CHANGE(immutable): Limits of eth_getLogs queries: the maximum block range
+	// and the maximum number of logs returned (0 = unlimited).
+	FilterMaxBlockRange int64 `toml:",omitempty"`
+	FilterMaxLogs       int   `toml:",omitempty"`
+
 	// Mining options
 	Miner miner.Config
 
@@ -164,14 +176,35 @@ type Config struct {
 	OverridePrevrandao *uint64 `toml:",omitempty"`
 	OverrideShanghai   *uint64 `toml:",omitempty"`
 
+	// CHANGE(immutable): Fork schedule overrides, fork times by name.
+	OverrideForks map[string]uint64 `toml:",omitempty"`
+
 	// CHANGE(immutable): Add gossip configuration.
 	GossipDefault bool `toml:",omitempty"`
 
 	// CHANGE(immutable): Disable txpool gossip configuration.
 	DisableTxPoolGossip bool `toml:",omitempty"`
 
+	// CHANGE(immutable): Per-role transaction gossip policies.
+	Gossip GossipConfig `toml:",omitempty"`
+
+	// CHANGE(immutable): Block fetcher catch-up mode ("off", "auto" or "on").
+	FetcherCatchUp string `toml:",omitempty"`
+
+	// CHANGE(immutable): URL of the boot node's public registry RPC, whose validators are prioritized.
+	PeerRegistry string `toml:",omitempty"`
+
 	// CHANGE(immutable): Proxy to Immutable RPC configuration.
-	RPCProxyURL string `toml:",omitempty"`
+	RPCProxy rpc.ProxyConfig `toml:",omitempty"`
+
+	// CHANGE(immutable): Enforcement of the txpool access-control lists on imported blocks ("", "log" or "reject").
+	BlockAccessPolicy string `toml:",omitempty"`
+
+	// CHANGE(immutable): First block checked against the access-control lists, defaults to blocks sealed after startup.
+	BlockAccessPolicyFrom *uint64 `toml:",omitempty"`
+
+	// CHANGE(immutable): Number of blocks the clique signer health metrics are computed over.
+	SignerHealthWindow uint64 `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
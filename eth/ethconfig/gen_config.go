@@ -12,6 +12,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // MarshalTOML marshals as TOML.
@@ -45,6 +46,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		SnapshotCache           int
 		Preimages               bool
 		FilterLogCacheSize      int
+		FilterMaxBlockRange     int64 `toml:",omitempty"`
+		FilterMaxLogs           int   `toml:",omitempty"`
 		Miner                   miner.Config
 		TxPool                  legacypool.Config
 		BlobPool                blobpool.Config
@@ -54,8 +57,20 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		RPCGasCap               uint64
 		RPCEVMTimeout           time.Duration
 		RPCTxFeeCap             float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64           `toml:",omitempty"`
+		OverrideVerkle          *uint64           `toml:",omitempty"`
+		OverridePrevrandao      *uint64           `toml:",omitempty"`
+		OverrideShanghai        *uint64           `toml:",omitempty"`
+		OverrideForks           map[string]uint64 `toml:",omitempty"`
+		GossipDefault           bool              `toml:",omitempty"`
+		DisableTxPoolGossip     bool              `toml:",omitempty"`
+		Gossip                  GossipConfig      `toml:",omitempty"`
+		FetcherCatchUp          string            `toml:",omitempty"`
+		PeerRegistry            string            `toml:",omitempty"`
+		RPCProxy                rpc.ProxyConfig   `toml:",omitempty"`
+		BlockAccessPolicy       string            `toml:",omitempty"`
+		BlockAccessPolicyFrom   *uint64           `toml:",omitempty"`
+		SignerHealthWindow      uint64            `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -86,6 +101,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.SnapshotCache = c.SnapshotCache
 	enc.Preimages = c.Preimages
 	enc.FilterLogCacheSize = c.FilterLogCacheSize
+	enc.FilterMaxBlockRange = c.FilterMaxBlockRange
+	enc.FilterMaxLogs = c.FilterMaxLogs
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
 	enc.BlobPool = c.BlobPool
@@ -97,6 +114,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
 	enc.OverrideCancun = c.OverrideCancun
 	enc.OverrideVerkle = c.OverrideVerkle
+	enc.OverridePrevrandao = c.OverridePrevrandao
+	enc.OverrideShanghai = c.OverrideShanghai
+	enc.OverrideForks = c.OverrideForks
+	enc.GossipDefault = c.GossipDefault
+	enc.DisableTxPoolGossip = c.DisableTxPoolGossip
+	enc.Gossip = c.Gossip
+	enc.FetcherCatchUp = c.FetcherCatchUp
+	enc.PeerRegistry = c.PeerRegistry
+	enc.RPCProxy = c.RPCProxy
+	enc.BlockAccessPolicy = c.BlockAccessPolicy
+	enc.BlockAccessPolicyFrom = c.BlockAccessPolicyFrom
+	enc.SignerHealthWindow = c.SignerHealthWindow
 	return &enc, nil
 }
 
@@ -131,6 +160,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		SnapshotCache           *int
 		Preimages               *bool
 		FilterLogCacheSize      *int
+		FilterMaxBlockRange     *int64 `toml:",omitempty"`
+		FilterMaxLogs           *int   `toml:",omitempty"`
 		Miner                   *miner.Config
 		TxPool                  *legacypool.Config
 		BlobPool                *blobpool.Config
@@ -140,8 +171,20 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		RPCGasCap               *uint64
 		RPCEVMTimeout           *time.Duration
 		RPCTxFeeCap             *float64
-		OverrideCancun          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		OverrideCancun          *uint64           `toml:",omitempty"`
+		OverrideVerkle          *uint64           `toml:",omitempty"`
+		OverridePrevrandao      *uint64           `toml:",omitempty"`
+		OverrideShanghai        *uint64           `toml:",omitempty"`
+		OverrideForks           map[string]uint64 `toml:",omitempty"`
+		GossipDefault           *bool             `toml:",omitempty"`
+		DisableTxPoolGossip     *bool             `toml:",omitempty"`
+		Gossip                  *GossipConfig     `toml:",omitempty"`
+		FetcherCatchUp          *string           `toml:",omitempty"`
+		PeerRegistry            *string           `toml:",omitempty"`
+		RPCProxy                *rpc.ProxyConfig  `toml:",omitempty"`
+		BlockAccessPolicy       *string           `toml:",omitempty"`
+		BlockAccessPolicyFrom   *uint64           `toml:",omitempty"`
+		SignerHealthWindow      *uint64           `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -231,6 +274,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.FilterLogCacheSize != nil {
 		c.FilterLogCacheSize = *dec.FilterLogCacheSize
 	}
+	if dec.FilterMaxBlockRange != nil {
+		c.FilterMaxBlockRange = *dec.FilterMaxBlockRange
+	}
+	if dec.FilterMaxLogs != nil {
+		c.FilterMaxLogs = *dec.FilterMaxLogs
+	}
 	if dec.Miner != nil {
 		c.Miner = *dec.Miner
 	}
@@ -264,5 +313,41 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.OverrideVerkle != nil {
 		c.OverrideVerkle = dec.OverrideVerkle
 	}
+	if dec.OverridePrevrandao != nil {
+		c.OverridePrevrandao = dec.OverridePrevrandao
+	}
+	if dec.OverrideShanghai != nil {
+		c.OverrideShanghai = dec.OverrideShanghai
+	}
+	if dec.OverrideForks != nil {
+		c.OverrideForks = dec.OverrideForks
+	}
+	if dec.GossipDefault != nil {
+		c.GossipDefault = *dec.GossipDefault
+	}
+	if dec.DisableTxPoolGossip != nil {
+		c.DisableTxPoolGossip = *dec.DisableTxPoolGossip
+	}
+	if dec.Gossip != nil {
+		c.Gossip = *dec.Gossip
+	}
+	if dec.FetcherCatchUp != nil {
+		c.FetcherCatchUp = *dec.FetcherCatchUp
+	}
+	if dec.PeerRegistry != nil {
+		c.PeerRegistry = *dec.PeerRegistry
+	}
+	if dec.RPCProxy != nil {
+		c.RPCProxy = *dec.RPCProxy
+	}
+	if dec.BlockAccessPolicy != nil {
+		c.BlockAccessPolicy = *dec.BlockAccessPolicy
+	}
+	if dec.BlockAccessPolicyFrom != nil {
+		c.BlockAccessPolicyFrom = dec.BlockAccessPolicyFrom
+	}
+	if dec.SignerHealthWindow != nil {
+		c.SignerHealthWindow = *dec.SignerHealthWindow
+	}
 	return nil
 }
@@ -0,0 +1,50 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests that the generated TOML marshaller covers every field of Config, so
+// that a field added without running go generate is not silently ignored in
+// config files.
+func TestImmutableGenConfigFields(t *testing.T) {
+	enc, err := Defaults.MarshalTOML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		want = reflect.TypeOf(Config{})
+		have = reflect.TypeOf(enc).Elem()
+	)
+	for i := 0; i < want.NumField(); i++ {
+		field := want.Field(i)
+		generated, ok := have.FieldByName(field.Name)
+		if !ok {
+			t.Errorf("field %s missing from gen_config.go, run go generate", field.Name)
+			continue
+		}
+		if generated.Tag != field.Tag {
+			t.Errorf("field %s has tag %q in gen_config.go, want %q", field.Name, generated.Tag, field.Tag)
+		}
+	}
+	if have.NumField() != want.NumField() {
+		t.Errorf("gen_config.go has %d fields, Config has %d, run go generate", have.NumField(), want.NumField())
+	}
+}
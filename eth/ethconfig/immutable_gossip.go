@@ -0,0 +1,36 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethconfig
+
+// GossipConfig assigns transaction gossip policies to peer roles. The role of
+// a peer is taken from the allowlists below, which hold enode URLs or IDs, or
+// from the validators of the peer registry, which are re-evaluated whenever the
+// registry changes. Static and trusted peers may also claim a role with the
+// "imx-role" entry of their ENR. Remaining inbound peers have the "inbound" role
+// and all other peers the "default" role.
+//
+// Policies maps roles to one of "full" (send all transactions), "sqrt" (send to
+// the square root of the peers, announce to the rest), "announce" or "none".
+// Unless overridden, validators get full transactions, partners announcements
+// and inbound peers nothing once any allowlist or policy is set.
+type GossipConfig struct {
+	Validators []string          `toml:",omitempty"`
+	Boot       []string          `toml:",omitempty"`
+	RPC        []string          `toml:",omitempty"`
+	Partners   []string          `toml:",omitempty"`
+	Policies   map[string]string `toml:",omitempty"`
+}
@@ -20,13 +20,14 @@ package fetcher
 import (
 	"errors"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/eth/fetcher/long"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -47,12 +48,11 @@ const (
 	hashLimit    = 256 // Maximum number of unique blocks or headers a peer may have announced
 	// CHANGE(immutable): Set blockLimit to 256 as all blocks are coming from a handful of peers
 	blockLimit = 256 // Maximum number of unique blocks a peer may have delivered
+	// CHANGE(immutable): Set maxQueueDist to 256, the distance is lifted in catch-up mode
+	maxQueueDist = 256 // Maximum allowed distance from the chain head to queue
 )
 
 var (
-	// CHANGE(immutable): Set maxQueueDist to a large number if env var is set
-	maxQueueDist = long.BlockFetchDistance() // Maximum allowed distance from the chain head to queue
-
 	blockAnnounceInMeter   = metrics.NewRegisteredMeter("eth/fetcher/block/announces/in", nil)
 	blockAnnounceOutTimer  = metrics.NewRegisteredTimer("eth/fetcher/block/announces/out", nil)
 	blockAnnounceDropMeter = metrics.NewRegisteredMeter("eth/fetcher/block/announces/drop", nil)
@@ -139,6 +139,7 @@ type bodyFilterTask struct {
 // blockOrHeaderInject represents a schedules import operation.
 type blockOrHeaderInject struct {
 	origin string
+	index  int // CHANGE(immutable): Index in the import queue, -1 once popped
 
 	header *types.Header // Used for light mode fetcher which only cares about header.
 	block  *types.Block  // Used for normal mode fetcher which imports full block.
@@ -197,6 +198,15 @@ type BlockFetcher struct {
 	insertChain    chainInsertFn      // Injects a batch of blocks into the chain
 	dropPeer       peerDropFn         // Drops a peer for misbehaving
 
+	// CHANGE(immutable): Optional callback attributing imported blocks to their peer
+	recordOrigin blockOriginFn
+
+	// CHANGE(immutable): Catch-up mode lifting the queue distance limit
+	catchUpMode   atomic.Value              // Configured catch-up mode (CatchUpOff, CatchUpAuto or CatchUpOn)
+	catchUpActive atomic.Bool               // Whether the queue distance limit is currently lifted
+	bestLock      sync.Mutex                // Protects the best numbers of the peers
+	bestNumbers   map[string]peerBestNumber // Highest block numbers announced or delivered by each live peer
+
 	// Testing hooks
 	announceChangeHook func(common.Hash, bool)           // Method to call upon adding or deleting a hash from the blockAnnounce list
 	queueChangeHook    func(common.Hash, bool)           // Method to call upon adding or deleting a block from the import queue
@@ -207,7 +217,7 @@ type BlockFetcher struct {
 
 // NewBlockFetcher creates a block fetcher to retrieve blocks based on hash announcements.
 func NewBlockFetcher(light bool, getHeader HeaderRetrievalFn, getBlock blockRetrievalFn, verifyHeader headerVerifierFn, broadcastBlock blockBroadcasterFn, chainHeight chainHeightFn, insertHeaders headersInsertFn, insertChain chainInsertFn, dropPeer peerDropFn) *BlockFetcher {
-	return &BlockFetcher{
+	f := &BlockFetcher{
 		light:          light,
 		notify:         make(chan *blockAnnounce),
 		inject:         make(chan *blockOrHeaderInject),
@@ -220,7 +230,7 @@ func NewBlockFetcher(light bool, getHeader HeaderRetrievalFn, getBlock blockRetr
 		fetching:       make(map[common.Hash]*blockAnnounce),
 		fetched:        make(map[common.Hash][]*blockAnnounce),
 		completing:     make(map[common.Hash]*blockAnnounce),
-		queue:          prque.New[int64, *blockOrHeaderInject](nil),
+		queue:          prque.New[int64, *blockOrHeaderInject](setQueueIndex), // CHANGE(immutable): Track indexes to evict queued blocks
 		queues:         make(map[string]int),
 		queued:         make(map[common.Hash]*blockOrHeaderInject),
 		getHeader:      getHeader,
@@ -231,7 +241,12 @@ func NewBlockFetcher(light bool, getHeader HeaderRetrievalFn, getBlock blockRetr
 		insertHeaders:  insertHeaders,
 		insertChain:    insertChain,
 		dropPeer:       dropPeer,
+		// CHANGE(immutable): Best numbers of the peers for the catch-up mode
+		bestNumbers: make(map[string]peerBestNumber),
 	}
+	// CHANGE(immutable): Catch-up mode is off by default
+	f.catchUpMode.Store(CatchUpOff)
+	return f
 }
 
 // Start boots up the announcement based synchroniser, accepting and processing
@@ -358,6 +373,8 @@ func (f *BlockFetcher) loop() {
 		}
 		// Import any queued blocks that could potentially fit
 		height := f.chainHeight()
+		// CHANGE(immutable): Switch the catch-up mode as the chain progresses
+		f.updateCatchUp(height)
 		for !f.queue.Empty() {
 			op := f.queue.PopItem()
 			hash := op.hash()
@@ -403,8 +420,10 @@ func (f *BlockFetcher) loop() {
 			if notification.number == 0 {
 				break
 			}
+			// CHANGE(immutable): Track the best announced number for the catch-up mode
+			f.trackBestNumber(notification.origin, notification.number)
 			// If we have a valid block number, check that it's potentially useful
-			if dist := int64(notification.number) - int64(f.chainHeight()); dist < -maxUncleDist || dist > int64(maxQueueDist) {
+			if dist := int64(notification.number) - int64(f.chainHeight()); dist < -maxUncleDist || dist > f.queueDist() {
 				log.Debug("Peer discarded announcement", "peer", notification.origin, "number", notification.number, "hash", notification.hash, "distance", dist)
 				blockAnnounceDropMeter.Mark(1)
 				break
@@ -811,14 +830,25 @@ func (f *BlockFetcher) enqueue(peer string, header *types.Header, block *types.B
 		f.forgetHash(hash)
 		return
 	}
+	// CHANGE(immutable): Track the best delivered number for the catch-up mode
+	f.trackBestNumber(peer, number)
 	// Discard any past or too distant blocks
-	if dist := int64(number) - int64(f.chainHeight()); dist < -maxUncleDist || dist > int64(maxQueueDist) {
+	if dist := int64(number) - int64(f.chainHeight()); dist < -maxUncleDist || dist > f.queueDist() {
 		// CHANGE(immutable): Changed to warning log as we only expect blocks from trusted peers that should be giving us valid blocks
 		log.Warn("Discarded delivered header or block, too far away", "peer", peer, "number", number, "hash", hash, "distance", dist)
 		blockBroadcastDropMeter.Mark(1)
 		f.forgetHash(hash)
 		return
 	}
+	// CHANGE(immutable): Bound the memory used by the queue, which the catch-up mode
+	// does not bound through the distance. Lower blocks are needed first, so make
+	// room by evicting the highest queued block if it is above the new one.
+	if _, ok := f.queued[hash]; !ok && f.queue.Size() >= maxQueueSize && !f.evictHighest(number) {
+		log.Warn("Discarded delivered header or block, queue full", "peer", peer, "number", number, "hash", hash, "limit", maxQueueSize)
+		blockBroadcastDropMeter.Mark(1)
+		f.forgetHash(hash)
+		return
+	}
 	// Schedule the block for future importing
 	if _, ok := f.queued[hash]; !ok {
 		op := &blockOrHeaderInject{origin: peer}
@@ -893,6 +923,10 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 			block = block.WithWithdrawals(make([]*types.Withdrawal, 0))
 		}
 
+		// CHANGE(immutable): Attribute the block to its peer before verification and import
+		if f.recordOrigin != nil {
+			f.recordOrigin(peer, types.Blocks{block})
+		}
 		// Quickly validate the header and propagate the block if it passes
 		switch err := f.verifyHeader(block.Header()); err {
 		case nil:
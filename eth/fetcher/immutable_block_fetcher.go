@@ -0,0 +1,200 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// Catch-up modes of the block fetcher. In catch-up mode, blocks are queued
+// regardless of their distance from the chain head.
+const (
+	CatchUpOff  = "off"  // Never lift the queue distance limit
+	CatchUpAuto = "auto" // Lift the limit while the head is far behind the best announced block
+	CatchUpOn   = "on"   // Always lift the limit
+)
+
+// LongRangeSyncEnvVar is the deprecated env var which used to lift the queue
+// distance limit, it maps to the CatchUpOn mode.
+const LongRangeSyncEnvVar = "GETH_FLAG_IMMUTABLE_LONG_RANGE_SYNC"
+
+// maxQueueSize is the maximum number of blocks queued for import, bounding the
+// memory used while catching up.
+const maxQueueSize = 4096
+
+// bestNumberTTL is the time after which a peer's best announced number is
+// ignored unless the peer announces or delivers it again. It stops a single
+// bogus announcement from holding the catch-up mode on.
+const bestNumberTTL = time.Minute
+
+var catchUpGauge = metrics.NewRegisteredGauge("eth/fetcher/block/catchup", nil)
+
+// CatchUpStatus is the catch-up state of the block fetcher.
+type CatchUpStatus struct {
+	Mode       string `json:"mode"`
+	Active     bool   `json:"active"`
+	Head       uint64 `json:"head"`
+	BestNumber uint64 `json:"bestNumber"` // Highest block number recently announced or delivered by live peers
+}
+
+// peerBestNumber is the highest block number announced or delivered by a peer.
+type peerBestNumber struct {
+	number uint64
+	time   time.Time // Time the number was last announced or delivered
+}
+
+// blockOriginFn is a callback type for attributing blocks to the peer which
+// propagated them.
+type blockOriginFn func(peer string, blocks types.Blocks)
+
+// SetOriginRecorder installs a callback which is invoked with the originating
+// peer of every propagated block before it is verified and imported. It must be called
+// before the fetcher is started.
+func (f *BlockFetcher) SetOriginRecorder(record func(peer string, blocks types.Blocks)) {
+	f.recordOrigin = record
+}
+
+// SetCatchUpMode sets the catch-up mode of the fetcher.
+func (f *BlockFetcher) SetCatchUpMode(mode string) error {
+	switch mode {
+	case CatchUpOff, CatchUpAuto, CatchUpOn:
+	default:
+		return fmt.Errorf("invalid catch-up mode %q, must be %s, %s or %s", mode, CatchUpOff, CatchUpAuto, CatchUpOn)
+	}
+	f.catchUpMode.Store(mode)
+	f.updateCatchUp(f.chainHeight())
+	return nil
+}
+
+// CatchUpStatus returns the catch-up state of the fetcher.
+func (f *BlockFetcher) CatchUpStatus() CatchUpStatus {
+	return CatchUpStatus{
+		Mode:       f.catchUpMode.Load().(string),
+		Active:     f.catchUpActive.Load(),
+		Head:       f.chainHeight(),
+		BestNumber: f.bestNumber(),
+	}
+}
+
+// trackBestNumber records a block number announced or delivered by a peer,
+// switching the catch-up mode on if it is far ahead.
+func (f *BlockFetcher) trackBestNumber(peer string, number uint64) {
+	f.bestLock.Lock()
+	if best, ok := f.bestNumbers[peer]; !ok || number >= best.number || time.Since(best.time) > bestNumberTTL {
+		f.bestNumbers[peer] = peerBestNumber{number: number, time: time.Now()}
+	}
+	f.bestLock.Unlock()
+
+	f.updateCatchUp(f.chainHeight())
+}
+
+// ForgetPeer removes the best number of a disconnected peer, switching the
+// catch-up mode off if no remaining peer is far ahead.
+func (f *BlockFetcher) ForgetPeer(peer string) {
+	f.bestLock.Lock()
+	delete(f.bestNumbers, peer)
+	f.bestLock.Unlock()
+
+	f.updateCatchUp(f.chainHeight())
+}
+
+// bestNumber returns the highest block number recently announced or delivered
+// by the live peers, pruning expired numbers.
+func (f *BlockFetcher) bestNumber() uint64 {
+	f.bestLock.Lock()
+	defer f.bestLock.Unlock()
+
+	var best uint64
+	for peer, number := range f.bestNumbers {
+		if time.Since(number.time) > bestNumberTTL {
+			delete(f.bestNumbers, peer)
+			continue
+		}
+		if number.number > best {
+			best = number.number
+		}
+	}
+	return best
+}
+
+// updateCatchUp switches the catch-up mode on or off for the given chain
+// height. In auto mode, it is on while the best announced block is more than
+// maxQueueDist blocks ahead of the chain.
+func (f *BlockFetcher) updateCatchUp(height uint64) {
+	var (
+		active bool
+		best   = f.bestNumber()
+	)
+	switch f.catchUpMode.Load().(string) {
+	case CatchUpOn:
+		active = true
+	case CatchUpAuto:
+		active = best > height+maxQueueDist
+	}
+	if f.catchUpActive.Swap(active) != active {
+		if active {
+			log.Info("Block fetcher catching up", "head", height, "best", best)
+			catchUpGauge.Update(1)
+		} else {
+			log.Info("Block fetcher caught up", "head", height, "best", best)
+			catchUpGauge.Update(0)
+		}
+	}
+}
+
+// queueDist returns the maximum distance from the chain head of the blocks
+// to queue.
+func (f *BlockFetcher) queueDist() int64 {
+	if f.catchUpActive.Load() {
+		return math.MaxInt64
+	}
+	return maxQueueDist
+}
+
+// setQueueIndex records the index of an import operation in the queue.
+func setQueueIndex(op *blockOrHeaderInject, index int) {
+	op.index = index
+}
+
+// evictHighest removes the highest block from the full import queue to make
+// room for the given block number, if the queued block is above it.
+func (f *BlockFetcher) evictHighest(number uint64) bool {
+	var victim *blockOrHeaderInject
+	for _, op := range f.queued {
+		if op.index >= 0 && (victim == nil || op.number() > victim.number()) {
+			victim = op
+		}
+	}
+	if victim == nil || victim.number() <= number {
+		return false
+	}
+	f.queue.Remove(victim.index)
+	hash := victim.hash()
+	log.Debug("Evicted queued header or block, queue full", "peer", victim.origin, "number", victim.number(), "hash", hash, "limit", maxQueueSize)
+	blockBroadcastDropMeter.Mark(1)
+	f.forgetBlock(hash)
+	if f.queueChangeHook != nil {
+		f.queueChangeHook(hash, false)
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package fetcher
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the catch-up mode queues distant blocks while the chain is far
+// behind, and switches back once it is within the queue distance.
+func TestImmutableCatchUpMode(t *testing.T) {
+	hashes, blocks := makeChain(3*maxQueueDist, 0, genesis)
+	head := hashes[len(hashes)/2]
+	far := hashes[len(hashes)/2-maxQueueDist-1]
+
+	tester := newTester(false)
+	defer tester.fetcher.Stop()
+
+	tester.lock.Lock()
+	tester.hashes = []common.Hash{head}
+	tester.blocks = map[common.Hash]*types.Block{head: blocks[head]}
+	tester.lock.Unlock()
+
+	if err := tester.fetcher.SetCatchUpMode("fast"); err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+	if err := tester.fetcher.SetCatchUpMode(CatchUpAuto); err != nil {
+		t.Fatal(err)
+	}
+	// A block beyond the queue distance switches the catch-up mode on and is queued
+	queued := make(chan common.Hash, 1)
+	tester.fetcher.queueChangeHook = func(hash common.Hash, added bool) {
+		if added {
+			select {
+			case queued <- hash:
+			default:
+			}
+		}
+	}
+	tester.fetcher.Enqueue("far", blocks[far])
+	select {
+	case <-queued:
+	case <-time.After(time.Second):
+		t.Fatal("fetcher did not queue distant block while catching up")
+	}
+	status := tester.fetcher.CatchUpStatus()
+	if !status.Active || status.BestNumber != blocks[far].NumberU64() {
+		t.Fatalf("unexpected catch-up status %+v", status)
+	}
+	// The mode switches off once the head is within the queue distance
+	closer := hashes[len(hashes)/2-1]
+	tester.lock.Lock()
+	tester.hashes = append(tester.hashes, closer)
+	tester.blocks[closer] = blocks[closer]
+	tester.lock.Unlock()
+
+	tester.fetcher.updateCatchUp(tester.chainHeight())
+	if tester.fetcher.CatchUpStatus().Active {
+		t.Fatal("catch-up mode still active within the queue distance")
+	}
+	// The mode can be forced on
+	if err := tester.fetcher.SetCatchUpMode(CatchUpOn); err != nil {
+		t.Fatal(err)
+	}
+	if status := tester.fetcher.CatchUpStatus(); !status.Active || status.Mode != CatchUpOn {
+		t.Fatalf("unexpected catch-up status %+v", status)
+	}
+}
+
+// Tests that the best number of a disconnected peer no longer holds the
+// catch-up mode on.
+func TestImmutableCatchUpMode_ForgetPeer(t *testing.T) {
+	tester := newTester(false)
+	defer tester.fetcher.Stop()
+
+	if err := tester.fetcher.SetCatchUpMode(CatchUpAuto); err != nil {
+		t.Fatal(err)
+	}
+	tester.fetcher.trackBestNumber("near", 1)
+	tester.fetcher.trackBestNumber("far", 10*maxQueueDist)
+	if status := tester.fetcher.CatchUpStatus(); !status.Active || status.BestNumber != 10*maxQueueDist {
+		t.Fatalf("unexpected catch-up status %+v", status)
+	}
+	tester.fetcher.ForgetPeer("far")
+	if status := tester.fetcher.CatchUpStatus(); status.Active || status.BestNumber != 1 {
+		t.Fatalf("unexpected catch-up status after dropping peer %+v", status)
+	}
+	// Numbers which are not announced again expire
+	tester.fetcher.bestLock.Lock()
+	tester.fetcher.bestNumbers["far"] = peerBestNumber{number: 10 * maxQueueDist, time: time.Now().Add(-2 * bestNumberTTL)}
+	tester.fetcher.bestLock.Unlock()
+
+	if status := tester.fetcher.CatchUpStatus(); status.BestNumber != 1 {
+		t.Fatalf("expired best number counted: %+v", status)
+	}
+}
+
+// Tests that a full import queue evicts its highest blocks in favour of lower
+// ones, which are needed first.
+func TestImmutableQueueFull_EvictHighest(t *testing.T) {
+	tester := &fetcherTester{
+		hashes:  []common.Hash{genesis.Hash()},
+		headers: map[common.Hash]*types.Header{genesis.Hash(): genesis.Header()},
+		blocks:  map[common.Hash]*types.Block{genesis.Hash(): genesis},
+		drops:   make(map[string]bool),
+	}
+	// The fetcher is not started, the queue is filled directly
+	tester.fetcher = NewBlockFetcher(true, tester.getHeader, tester.getBlock, tester.verifyHeader, tester.broadcastBlock, tester.chainHeight, tester.insertHeaders, tester.insertChain, tester.dropPeer)
+	fetcher := tester.fetcher
+	if err := fetcher.SetCatchUpMode(CatchUpOn); err != nil {
+		t.Fatal(err)
+	}
+	header := func(number uint64) *types.Header {
+		return &types.Header{ParentHash: genesis.Hash(), Number: new(big.Int).SetUint64(number)}
+	}
+	for i := 0; i < maxQueueSize; i++ {
+		fetcher.enqueue(fmt.Sprintf("peer-%d", i/blockLimit), header(uint64(100+i)), nil)
+	}
+	if size := fetcher.queue.Size(); size != maxQueueSize {
+		t.Fatalf("queue size mismatch: have %d, want %d", size, maxQueueSize)
+	}
+	// A lower block evicts the highest queued one
+	low, highest := header(10), header(uint64(100+maxQueueSize-1))
+	fetcher.enqueue("low", low, nil)
+	if _, ok := fetcher.queued[low.Hash()]; !ok {
+		t.Fatal("lower block not queued")
+	}
+	if _, ok := fetcher.queued[highest.Hash()]; ok {
+		t.Fatal("highest block not evicted")
+	}
+	if size := fetcher.queue.Size(); size != maxQueueSize {
+		t.Fatalf("queue size mismatch: have %d, want %d", size, maxQueueSize)
+	}
+	// A higher block is discarded
+	high := header(uint64(100 + maxQueueSize))
+	fetcher.enqueue("high", high, nil)
+	if _, ok := fetcher.queued[high.Hash()]; ok {
+		t.Fatal("higher block queued")
+	}
+	// The queue still pops in order
+	if op := fetcher.queue.PopItem(); op.number() != 10 {
+		t.Fatalf("unexpected first queued block %d", op.number())
+	}
+}
@@ -28,8 +28,8 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// CHANGE(immutable): Add a max filter range for logs queried using getLogs
-const maxFilterBlockRange = 5000
+// CHANGE(immutable): Add a default max filter range for logs queried using getLogs
+const defaultMaxFilterBlockRange = 5000
 
 // Filter can be used to retrieve and filter logs.
 type Filter struct {
@@ -161,31 +161,26 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		return nil, err
 	}
 
-	// CHANGE(immutable): Add a max filter block range
-	// TODO: Change this to a CLI flag
-	if f.end-f.begin > maxFilterBlockRange {
-		return nil, fmt.Errorf("exceeded maximum block range: %d", maxFilterBlockRange)
+	// CHANGE(immutable): Add a configurable max filter block range
+	if f.end-f.begin > f.sys.cfg.MaxBlockRange {
+		return nil, fmt.Errorf("exceeded maximum block range: %d", f.sys.cfg.MaxBlockRange)
 	}
 
-	logChan, errChan := f.rangeLogsAsync(ctx)
-	var logs []*types.Log
-	for {
-		select {
-		case log := <-logChan:
-			logs = append(logs, log)
-		case err := <-errChan:
-			if err != nil {
-				// if an error occurs during extraction, we do return the extracted data
-				return logs, err
-			}
-			// Append the pending ones
-			if endPending {
-				pendingLogs := f.pendingLogs()
-				logs = append(logs, pendingLogs...)
-			}
-			return logs, nil
-		}
+	// CHANGE(immutable): Stop the query once it exceeds the max number of logs
+	logs, complete, err := f.rangeLogsLimited(ctx, f.sys.cfg.MaxLogs, nil)
+	if err != nil {
+		// if an error occurs during extraction, we do return the extracted data
+		return logs, err
+	}
+	// Append the pending ones
+	if endPending {
+		pendingLogs := f.pendingLogs()
+		logs = append(logs, pendingLogs...)
 	}
+	if !complete || (f.sys.cfg.MaxLogs > 0 && len(logs) > f.sys.cfg.MaxLogs) {
+		return nil, fmt.Errorf("exceeded maximum number of logs: %d", f.sys.cfg.MaxLogs)
+	}
+	return logs, nil
 }
 
 // rangeLogsAsync retrieves block-range logs that match the filter criteria asynchronously,
@@ -43,6 +43,10 @@ import (
 type Config struct {
 	LogCacheSize int           // maximum number of cached blocks (default: 32)
 	Timeout      time.Duration // how long filters stay active (default: 5min)
+
+	// CHANGE(immutable): Limits of eth_getLogs queries
+	MaxBlockRange int64 // maximum block range of a log query (default: 5000)
+	MaxLogs       int   // maximum number of logs returned by a log query (default: unlimited)
 }
 
 func (cfg Config) withDefaults() Config {
@@ -52,6 +56,18 @@ func (cfg Config) withDefaults() Config {
 	if cfg.LogCacheSize == 0 {
 		cfg.LogCacheSize = 32
 	}
+	// CHANGE(immutable): Default max filter block range, also replacing invalid
+	// limits set through the config file, which would reject every query.
+	if cfg.MaxBlockRange < 0 {
+		log.Warn("Sanitizing invalid max filter block range", "provided", cfg.MaxBlockRange, "updated", defaultMaxFilterBlockRange)
+	}
+	if cfg.MaxBlockRange <= 0 {
+		cfg.MaxBlockRange = defaultMaxFilterBlockRange
+	}
+	if cfg.MaxLogs < 0 {
+		log.Warn("Sanitizing invalid max filter logs", "provided", cfg.MaxLogs, "updated", 0)
+		cfg.MaxLogs = 0
+	}
 	return cfg
 }
 
@@ -0,0 +1,169 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultLogsPageSize is the page size of eth_getLogsPaged if the node does not
+// limit the number of logs of a query.
+const defaultLogsPageSize = 1000
+
+var errInvalidLogCursor = errors.New("log cursor outside of the block range")
+
+// LogCursor is the position a paginated log query resumes from: the logs of
+// BlockNumber starting at LogIndex.
+type LogCursor struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+}
+
+// LogsPage is a page of logs returned by eth_getLogsPaged. Cursor is nil once
+// the whole block range has been scanned.
+type LogsPage struct {
+	Logs   []*types.Log `json:"logs"`
+	Cursor *LogCursor   `json:"cursor"`
+}
+
+// GetLogsPaged returns a page of the logs matching the given criteria, starting
+// at the cursor returned with the previous page, if any. A page holds at most
+// limit logs, capped to the node's maximum number of logs, and every page scans
+// at most the node's maximum block range. Pages may be empty while the cursor
+// moves through blocks without matching logs.
+//
+// The block range must not include pending logs. Callers should use explicit
+// block numbers for the pagination to be deterministic.
+func (api *FilterAPI) GetLogsPaged(ctx context.Context, crit FilterCriteria, cursor *LogCursor, limit *hexutil.Uint) (*LogsPage, error) {
+	if len(crit.Topics) > maxTopics {
+		return nil, errExceedMaxTopics
+	}
+	if crit.BlockHash != nil {
+		return nil, errors.New("paginated log queries do not support block hashes")
+	}
+	from, err := api.resolveBlockNumber(ctx, crit.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.resolveBlockNumber(ctx, crit.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errInvalidBlockRange
+	}
+	start, index := from, uint(0)
+	if cursor != nil {
+		if int64(cursor.BlockNumber) < from || int64(cursor.BlockNumber) > to {
+			return nil, errInvalidLogCursor
+		}
+		start, index = int64(cursor.BlockNumber), uint(cursor.LogIndex)
+	}
+	size := api.sys.cfg.MaxLogs
+	if size == 0 {
+		size = defaultLogsPageSize
+	}
+	if limit != nil && int(*limit) > 0 && int(*limit) < size {
+		size = int(*limit)
+	}
+	end := to
+	if end-start > api.sys.cfg.MaxBlockRange {
+		end = start + api.sys.cfg.MaxBlockRange
+	}
+	filter := api.sys.NewRangeFilter(start, end, crit.Addresses, crit.Topics)
+	logs, complete, err := filter.rangeLogsLimited(ctx, size, func(log *types.Log) bool {
+		return log.BlockNumber != uint64(start) || log.Index >= index
+	})
+	if err != nil {
+		return nil, err
+	}
+	page := &LogsPage{Logs: returnLogs(logs)}
+	switch {
+	case !complete:
+		last := logs[len(logs)-1]
+		page.Cursor = &LogCursor{BlockNumber: hexutil.Uint64(last.BlockNumber), LogIndex: hexutil.Uint(last.Index + 1)}
+	case end < to:
+		page.Cursor = &LogCursor{BlockNumber: hexutil.Uint64(end + 1)}
+	}
+	return page, nil
+}
+
+// resolveBlockNumber resolves a block number of a log query, defaulting to the
+// latest block.
+func (api *FilterAPI) resolveBlockNumber(ctx context.Context, number *big.Int) (int64, error) {
+	if number == nil {
+		number = big.NewInt(rpc.LatestBlockNumber.Int64())
+	}
+	switch n := rpc.BlockNumber(number.Int64()); n {
+	case rpc.PendingBlockNumber:
+		return 0, errors.New("paginated log queries do not support pending logs")
+	case rpc.LatestBlockNumber, rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		header, err := api.sys.backend.HeaderByNumber(ctx, n)
+		if err != nil {
+			return 0, err
+		}
+		if header == nil {
+			return 0, fmt.Errorf("%s header not found", n)
+		}
+		return header.Number.Int64(), nil
+	default:
+		if n < 0 {
+			return 0, fmt.Errorf("invalid block number %d", n)
+		}
+		return int64(n), nil
+	}
+}
+
+// rangeLogsLimited gathers the logs of the range filter accepted by keep, if
+// set, stopping once more than limit logs are found (no limit if zero). It
+// reports whether the whole range was scanned.
+func (f *Filter) rangeLogsLimited(ctx context.Context, limit int, keep func(*types.Log) bool) ([]*types.Log, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logChan, errChan := f.rangeLogsAsync(ctx)
+	var logs []*types.Log
+	for {
+		select {
+		case log := <-logChan:
+			if keep != nil && !keep(log) {
+				continue
+			}
+			if limit > 0 && len(logs) == limit {
+				// Abort the query, draining the logs it is delivering
+				cancel()
+				for {
+					select {
+					case <-logChan:
+					case <-errChan:
+						return logs, false, nil
+					}
+				}
+			}
+			logs = append(logs, log)
+		case err := <-errChan:
+			return logs, err == nil, err
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+func TestImmutableGetLogsLimits(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		_, sys = newTestFilterSystem(t, db, Config{MaxBlockRange: 10, MaxLogs: 3})
+		api    = NewFilterAPI(sys, false)
+		addr   = common.Address{0xfe}
+
+		gspec = &core.Genesis{
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Config:  params.TestChainConfig,
+		}
+	)
+	// Blocks 2, 5 and 25 hold two logs each
+	_, chain, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 30, func(i int, gen *core.BlockGen) {
+		switch i + 1 {
+		case 2, 5, 25:
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr}, {Address: addr}}
+			receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+		}
+	})
+	gspec.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	crit := func(from, to int64) FilterCriteria {
+		return FilterCriteria{FromBlock: big.NewInt(from), ToBlock: big.NewInt(to), Addresses: []common.Address{addr}}
+	}
+	// eth_getLogs enforces the configured limits
+	if _, err := api.GetLogs(context.Background(), crit(0, 29)); err == nil {
+		t.Error("expected block range error")
+	}
+	if _, err := api.GetLogs(context.Background(), crit(0, 10)); err == nil {
+		t.Error("expected max logs error")
+	}
+	if logs, err := api.GetLogs(context.Background(), crit(20, 29)); err != nil || len(logs) != 2 {
+		t.Errorf("expected 2 logs, got %d: %v", len(logs), err)
+	}
+	// eth_getLogsPaged streams the whole range in pages
+	for _, limit := range []uint{0, 1, 2, 5} {
+		var (
+			logs   []*types.Log
+			cursor *LogCursor
+			pages  int
+		)
+		size := hexutil.Uint(limit)
+		for {
+			page, err := api.GetLogsPaged(context.Background(), crit(0, 29), cursor, &size)
+			if err != nil {
+				t.Fatalf("limit %d: %v", limit, err)
+			}
+			if len(page.Logs) > 3 || (limit > 0 && len(page.Logs) > int(limit)) {
+				t.Fatalf("limit %d: page of %d logs", limit, len(page.Logs))
+			}
+			logs = append(logs, page.Logs...)
+			if pages++; page.Cursor == nil || pages > 30 {
+				break
+			}
+			cursor = page.Cursor
+		}
+		want := []struct{ number, index uint64 }{{2, 0}, {2, 1}, {5, 0}, {5, 1}, {25, 0}, {25, 1}}
+		if len(logs) != len(want) {
+			t.Fatalf("limit %d: expected %d logs, got %d", limit, len(want), len(logs))
+		}
+		for i, log := range logs {
+			if log.BlockNumber != want[i].number || uint64(log.Index) != want[i].index {
+				t.Errorf("limit %d: log %d: expected %d/%d, got %d/%d", limit, i, want[i].number, want[i].index, log.BlockNumber, log.Index)
+			}
+		}
+	}
+	if _, err := api.GetLogsPaged(context.Background(), crit(0, 29), &LogCursor{BlockNumber: 30}, nil); err != errInvalidLogCursor {
+		t.Errorf("expected %v, got %v", errInvalidLogCursor, err)
+	}
+}
+
+func TestImmutableConfigDefaults_InvalidLimits(t *testing.T) {
+	for _, cfg := range []Config{{}, {MaxBlockRange: -1, MaxLogs: -1}} {
+		cfg = cfg.withDefaults()
+		if cfg.MaxBlockRange != defaultMaxFilterBlockRange {
+			t.Errorf("max block range mismatch: have %d, want %d", cfg.MaxBlockRange, defaultMaxFilterBlockRange)
+		}
+		if cfg.MaxLogs != 0 {
+			t.Errorf("max logs mismatch: have %d, want 0", cfg.MaxLogs)
+		}
+	}
+	if cfg := (Config{MaxBlockRange: 10, MaxLogs: 3}).withDefaults(); cfg.MaxBlockRange != 10 || cfg.MaxLogs != 3 {
+		t.Errorf("valid limits replaced: %+v", cfg)
+	}
+}
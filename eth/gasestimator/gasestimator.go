@@ -30,8 +30,18 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// CHANGE(immutable): tracer returns the tracer creating a span per EVM
+// execution of an estimation from the current global tracer provider.
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/ethereum/go-ethereum/eth/gasestimator")
+}
+
 // Options are the contextual parameters to execute the requested call.
 //
 // Whilst it would be possible to pass a blockchain object that aggregates all
@@ -192,7 +202,15 @@ func execute(ctx context.Context, call *core.Message, opts *Options, gasLimit ui
 
 	// Execute the call and separate execution faults caused by a lack of gas or
 	// other non-fixable conditions
+	// CHANGE(immutable): trace every EVM execution of the estimation
+	ctx, span := tracer().Start(ctx, "evm.execute", trace.WithAttributes(attribute.Int64("gas.limit", int64(gasLimit))))
 	result, err := run(ctx, call, opts)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("gas.used", int64(result.UsedGas)), attribute.Bool("failed", result.Failed()))
+	}
+	span.End()
 	if err != nil {
 		if errors.Is(err, core.ErrIntrinsicGas) {
 			return true, nil, nil // Special case, raise gas limit
@@ -165,6 +165,9 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd rpc.BlockNum
 		case rpc.PendingBlockNumber:
 			if pendingBlock, pendingReceipts = oracle.backend.PendingBlockAndReceipts(); pendingBlock != nil {
 				resolved = pendingBlock.Header()
+			} else if pendingBlock, pendingReceipts = oracle.poolPendingBlock(headBlock); pendingBlock != nil {
+				// CHANGE(immutable): Estimate the pending block from the pool on Immutable networks
+				resolved = pendingBlock.Header()
 			} else {
 				// Pending block not supported by backend, process only until latest block.
 				resolved = headBlock
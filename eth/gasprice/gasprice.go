@@ -75,6 +75,9 @@ type Oracle struct {
 	maxHeaderHistory, maxBlockHistory uint64
 
 	historyCache *lru.Cache[cacheKey, processedFees]
+
+	// CHANGE(immutable): Per-head cache of the Immutable fee strategy
+	immutableFees immutableFeeCache
 }
 
 // NewOracle returns a new gasprice oracle which can recommend suitable
@@ -0,0 +1,335 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// DefaultFeeEstimateBlocks is the default number of blocks a fee estimate
+	// keeps a transaction includable for.
+	DefaultFeeEstimateBlocks = 5
+
+	// maxFeeEstimateBlocks is the maximum number of blocks of a fee estimate.
+	maxFeeEstimateBlocks = 64
+
+	// maxPendingSamples is the maximum number of pending pool transactions
+	// sampled by fee estimates.
+	maxPendingSamples = 1024
+)
+
+// Percentiles of the sampled tips paid by the fee estimate tiers.
+const (
+	slowTipPercentile     = 25
+	standardTipPercentile = 50
+	fastTipPercentile     = 90
+)
+
+// txPoolContentBackend is implemented by oracle backends with a transaction
+// pool, whose pending transactions are sampled by fee estimates.
+type txPoolContentBackend interface {
+	TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
+}
+
+// immutableFeeCache caches, per chain head, the pending pool transactions
+// sampled by fee estimates and the tip suggested on Immutable networks, so
+// that the pool is copied at most once per block.
+type immutableFeeCache struct {
+	lock sync.Mutex
+
+	poolHead common.Hash
+	poolTxs  []*types.Transaction
+
+	tipHead common.Hash
+	tip     *big.Int
+}
+
+// FeeTier is the fees of a fee estimate tier.
+type FeeTier struct {
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas"`
+}
+
+// FeeEstimate is the fees of a transaction sent on top of a block, tiered by
+// the percentile of recent tips the transaction pays. The max fee per gas of
+// every tier covers the base fee of the next blocks even if they are full.
+type FeeEstimate struct {
+	BlockNumber    hexutil.Uint64 `json:"blockNumber"`
+	BaseFees       []*hexutil.Big `json:"baseFees"` // Max base fees of the next blocks
+	MinedSamples   hexutil.Uint   `json:"minedSamples"`
+	PendingSamples hexutil.Uint   `json:"pendingSamples"`
+	Slow           *FeeTier       `json:"slow"`
+	Standard       *FeeTier       `json:"standard"`
+	Fast           *FeeTier       `json:"fast"`
+}
+
+// FeeEstimate estimates the fees of a transaction to stay includable for the
+// given number of blocks. Unlike SuggestTipCap, which samples the cheapest
+// transactions of recent blocks, tips are sampled from all the transactions
+// of the recent blocks and of the pending pool. Base fees are predicted with
+// the chain's base fee change denominator.
+func (oracle *Oracle) FeeEstimate(ctx context.Context, blocks int) (*FeeEstimate, error) {
+	if blocks < 1 || blocks > maxFeeEstimateBlocks {
+		return nil, fmt.Errorf("fee estimate blocks must be in [1, %d]", maxFeeEstimateBlocks)
+	}
+	head, _ := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return nil, errors.New("latest header not found")
+	}
+	if head.BaseFee == nil {
+		return nil, errors.New("fee estimates require London")
+	}
+	baseFees := predictBaseFees(oracle.backend, head, blocks)
+
+	mined, err := oracle.minedTips(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	pending := oracle.pendingTips(head, baseFees[0])
+	tips := append(mined, pending...)
+	slices.SortFunc(tips, func(a, b *big.Int) int { return a.Cmp(b) })
+
+	// Without any sample, fall back to the tip cap suggestion
+	var fallback *big.Int
+	if len(tips) == 0 {
+		if fallback, err = oracle.SuggestTipCap(ctx); err != nil {
+			return nil, err
+		}
+	}
+	tier := func(percentile int) *FeeTier {
+		tip := fallback
+		if len(tips) > 0 {
+			tip = tips[(len(tips)-1)*percentile/100]
+		}
+		tip = oracle.clampTip(tip)
+		return &FeeTier{
+			MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+			MaxFeePerGas:         (*hexutil.Big)(new(big.Int).Add(baseFees[blocks-1], tip)),
+		}
+	}
+	estimate := &FeeEstimate{
+		BlockNumber:    hexutil.Uint64(head.Number.Uint64()),
+		BaseFees:       make([]*hexutil.Big, len(baseFees)),
+		MinedSamples:   hexutil.Uint(len(mined)),
+		PendingSamples: hexutil.Uint(len(pending)),
+		Slow:           tier(slowTipPercentile),
+		Standard:       tier(standardTipPercentile),
+		Fast:           tier(fastTipPercentile),
+	}
+	for i, baseFee := range baseFees {
+		estimate.BaseFees[i] = (*hexutil.Big)(baseFee)
+	}
+	return estimate, nil
+}
+
+// predictBaseFees returns the base fees of the blocks following head. The
+// base fee of the next block is known, the later ones are the highest they can
+// be, i.e. assuming every block is full.
+func predictBaseFees(backend OracleBackend, head *types.Header, blocks int) []*big.Int {
+	config := backend.ChainConfig()
+	baseFees := []*big.Int{eip1559.CalcBaseFee(config, head)}
+
+	parent := &types.Header{Number: new(big.Int).Add(head.Number, common.Big1), GasLimit: head.GasLimit, GasUsed: head.GasLimit}
+	for len(baseFees) < blocks {
+		parent.BaseFee = baseFees[len(baseFees)-1]
+		baseFees = append(baseFees, eip1559.CalcBaseFee(config, parent))
+		parent.Number = new(big.Int).Add(parent.Number, common.Big1)
+	}
+	return baseFees
+}
+
+// minedTips returns the tips paid by the transactions of the recent blocks,
+// ignoring those sent by the block's miner.
+func (oracle *Oracle) minedTips(ctx context.Context, head *types.Header) ([]*big.Int, error) {
+	var tips []*big.Int
+	for i := 0; i < oracle.checkBlocks && uint64(i) <= head.Number.Uint64(); i++ {
+		block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(head.Number.Uint64()-uint64(i)))
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		signer := types.MakeSigner(oracle.backend.ChainConfig(), block.Number(), block.Time())
+		for _, tx := range block.Transactions() {
+			tip, err := tx.EffectiveGasTip(block.BaseFee())
+			if err != nil || tip.Cmp(oracle.ignorePrice) < 0 {
+				continue
+			}
+			if sender, err := types.Sender(signer, tx); err == nil && sender != block.Coinbase() {
+				tips = append(tips, tip)
+			}
+		}
+	}
+	return tips, nil
+}
+
+// pendingTxs returns a sample of at most maxPendingSamples pending pool
+// transactions, taken once per head, and whether the backend has a pool.
+func (oracle *Oracle) pendingTxs(head *types.Header) ([]*types.Transaction, bool) {
+	pool, ok := oracle.backend.(txPoolContentBackend)
+	if !ok {
+		return nil, false
+	}
+	cache := &oracle.immutableFees
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if cache.poolHead == head.Hash() {
+		return cache.poolTxs, true
+	}
+	var txs []*types.Transaction
+	pending, _ := pool.TxPoolContent()
+	for _, list := range pending {
+		if len(txs)+len(list) > maxPendingSamples {
+			list = list[:maxPendingSamples-len(txs)]
+		}
+		txs = append(txs, list...)
+		if len(txs) == maxPendingSamples {
+			break
+		}
+	}
+	cache.poolHead, cache.poolTxs = head.Hash(), txs
+	return txs, true
+}
+
+// pendingTips returns the tips the sampled pending pool transactions would pay
+// at the given base fee, ignoring those not includable at it.
+func (oracle *Oracle) pendingTips(head *types.Header, baseFee *big.Int) []*big.Int {
+	txs, _ := oracle.pendingTxs(head)
+
+	var tips []*big.Int
+	for _, tx := range txs {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil || tip.Cmp(oracle.ignorePrice) < 0 {
+			continue
+		}
+		tips = append(tips, tip)
+	}
+	return tips
+}
+
+// poolPendingBlock estimates, on Immutable networks, the block following head
+// from the sampled pending pool transactions, for fee history requests ending
+// with the pending block when the backend doesn't build one. The transactions
+// includable at the next base fee are added in decreasing tip order until the
+// gas limit, each assumed to use all of its gas.
+func (oracle *Oracle) poolPendingBlock(head *types.Header) (*types.Block, types.Receipts) {
+	if !oracle.backend.ChainConfig().IsImmutableZKEVM() || head.BaseFee == nil {
+		return nil, nil
+	}
+	pending, ok := oracle.pendingTxs(head)
+	if !ok {
+		return nil, nil
+	}
+	type tippedTx struct {
+		tx  *types.Transaction
+		tip *big.Int
+	}
+	var (
+		baseFee = eip1559.CalcBaseFee(oracle.backend.ChainConfig(), head)
+		tipped  []tippedTx
+	)
+	for _, tx := range pending {
+		if tip, err := tx.EffectiveGasTip(baseFee); err == nil {
+			tipped = append(tipped, tippedTx{tx, tip})
+		}
+	}
+	slices.SortStableFunc(tipped, func(a, b tippedTx) int { return b.tip.Cmp(a.tip) })
+
+	var (
+		txs      types.Transactions
+		receipts types.Receipts
+		gasUsed  uint64
+	)
+	for _, t := range tipped {
+		if gasUsed+t.tx.Gas() > head.GasLimit {
+			continue
+		}
+		gasUsed += t.tx.Gas()
+		txs = append(txs, t.tx)
+		receipts = append(receipts, &types.Receipt{GasUsed: t.tx.Gas()})
+	}
+	header := &types.Header{
+		ParentHash: head.Hash(),
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		GasLimit:   head.GasLimit,
+		GasUsed:    gasUsed,
+		BaseFee:    baseFee,
+		Time:       head.Time,
+	}
+	return types.NewBlockWithHeader(header).WithBody(txs, nil), receipts
+}
+
+// SuggestImmutableTipCap returns the tip of the standard tier of the default
+// fee estimate on Immutable networks, cached per head, and the regular tip
+// cap suggestion elsewhere.
+func (oracle *Oracle) SuggestImmutableTipCap(ctx context.Context) (*big.Int, error) {
+	if !oracle.backend.ChainConfig().IsImmutableZKEVM() {
+		return oracle.SuggestTipCap(ctx)
+	}
+	head, _ := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return nil, errors.New("latest header not found")
+	}
+	cache := &oracle.immutableFees
+	cache.lock.Lock()
+	if cache.tipHead == head.Hash() {
+		tip := new(big.Int).Set(cache.tip)
+		cache.lock.Unlock()
+		return tip, nil
+	}
+	cache.lock.Unlock()
+
+	estimate, err := oracle.FeeEstimate(ctx, DefaultFeeEstimateBlocks)
+	if err != nil {
+		return nil, err
+	}
+	tip := estimate.Standard.MaxPriorityFeePerGas.ToInt()
+
+	cache.lock.Lock()
+	cache.tipHead, cache.tip = head.Hash(), tip
+	cache.lock.Unlock()
+	return new(big.Int).Set(tip), nil
+}
+
+// clampTip caps a tip to the max price and, on Immutable networks, raises it
+// to the price limit.
+func (oracle *Oracle) clampTip(tip *big.Int) *big.Int {
+	if tip.Cmp(oracle.maxPrice) > 0 {
+		tip = oracle.maxPrice
+	}
+	if oracle.backend.ChainConfig().IsImmutableZKEVM() {
+		if limit := big.NewInt(settings.PriceLimit); tip.Cmp(limit) < 0 {
+			tip = limit
+		}
+	}
+	return new(big.Int).Set(tip)
+}
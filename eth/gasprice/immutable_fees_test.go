@@ -0,0 +1,178 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testPoolBackend is a test backend with a pending transaction pool.
+type testPoolBackend struct {
+	*testImmutableBackend
+	pending []*types.Transaction
+	reads   atomic.Int32 // Number of pool content retrievals
+
+	prunedBelow uint64 // Blocks below this number are not available
+}
+
+func (b *testPoolBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	b.reads.Add(1)
+	return map[common.Address][]*types.Transaction{{1}: b.pending}, nil
+}
+
+func (b *testPoolBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number >= 0 && uint64(number) < b.prunedBelow {
+		return nil, nil
+	}
+	return b.testImmutableBackend.BlockByNumber(ctx, number)
+}
+
+// newTestPoolBackend creates a backend whose 50 blocks each include a tip of
+// 20 gwei, with 10 pending transactions tipping 100 gwei.
+func newTestPoolBackend(t *testing.T) *testPoolBackend {
+	priceData := make(map[uint64]*types.DynamicFeeTx)
+	for i := 0; i <= 50; i++ {
+		priceData[uint64(i)] = &types.DynamicFeeTx{
+			GasFeeCap: big.NewInt(50 * params.GWei),
+			GasTipCap: big.NewInt(20 * params.GWei),
+		}
+	}
+	backend := &testPoolBackend{testImmutableBackend: newTestImmutableBackend(t, false, 51, 50, priceData)}
+	for i := 0; i < 10; i++ {
+		backend.pending = append(backend.pending, types.NewTx(&types.DynamicFeeTx{
+			Nonce:     uint64(i),
+			Gas:       params.TxGas,
+			GasFeeCap: big.NewInt(200 * params.GWei),
+			GasTipCap: big.NewInt(100 * params.GWei),
+		}))
+	}
+	return backend
+}
+
+func TestImmutableFeeEstimate(t *testing.T) {
+	backend := newTestPoolBackend(t)
+	defer backend.teardown()
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, Default: big.NewInt(params.GWei)})
+
+	estimate, err := oracle.FeeEstimate(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.MinedSamples != 20 || estimate.PendingSamples != 10 {
+		t.Fatalf("unexpected samples: %d mined, %d pending", estimate.MinedSamples, estimate.PendingSamples)
+	}
+	// The base fee of the next block is known, the later ones assume full blocks
+	head := backend.chain.CurrentBlock()
+	if len(estimate.BaseFees) != 4 || estimate.BaseFees[0].ToInt().Cmp(eip1559.CalcBaseFee(backend.ChainConfig(), head)) != 0 {
+		t.Fatalf("unexpected base fees %v", estimate.BaseFees)
+	}
+	denominator := new(big.Int).SetUint64(backend.ChainConfig().BaseFeeChangeDenominator())
+	for i := 1; i < len(estimate.BaseFees); i++ {
+		prev := estimate.BaseFees[i-1].ToInt()
+		want := new(big.Int).Add(prev, new(big.Int).Div(prev, denominator))
+		if want.Cmp(prev) == 0 {
+			want.Add(want, common.Big1)
+		}
+		if estimate.BaseFees[i].ToInt().Cmp(want) != 0 {
+			t.Fatalf("base fee %d: want %v, got %v", i, want, estimate.BaseFees[i])
+		}
+	}
+	// 20 mined tips of 20 gwei and 10 pending tips of 100 gwei
+	maxBaseFee := estimate.BaseFees[3].ToInt()
+	for name, tt := range map[string]struct {
+		tier *FeeTier
+		tip  int64
+	}{
+		"slow":     {estimate.Slow, 20 * params.GWei},
+		"standard": {estimate.Standard, 20 * params.GWei},
+		"fast":     {estimate.Fast, 100 * params.GWei},
+	} {
+		if tt.tier.MaxPriorityFeePerGas.ToInt().Int64() != tt.tip {
+			t.Errorf("%s: want tip %d, got %v", name, tt.tip, tt.tier.MaxPriorityFeePerGas)
+		}
+		if want := new(big.Int).Add(maxBaseFee, big.NewInt(tt.tip)); tt.tier.MaxFeePerGas.ToInt().Cmp(want) != 0 {
+			t.Errorf("%s: want max fee %v, got %v", name, want, tt.tier.MaxFeePerGas)
+		}
+	}
+	if _, err := oracle.FeeEstimate(context.Background(), 0); err == nil {
+		t.Error("expected error for zero blocks")
+	}
+}
+
+func TestImmutableFeeEstimate_Caching(t *testing.T) {
+	backend := newTestPoolBackend(t)
+	defer backend.teardown()
+	// Blocks beyond the available history don't discard the collected tips
+	backend.prunedBelow = 45
+
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, Default: big.NewInt(params.GWei)})
+	estimate, err := oracle.FeeEstimate(context.Background(), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.MinedSamples != 6 || estimate.PendingSamples != 10 {
+		t.Fatalf("unexpected samples: %d mined, %d pending", estimate.MinedSamples, estimate.PendingSamples)
+	}
+	// The pool is read once per head, however many estimates are made
+	tip, err := oracle.SuggestImmutableTipCap(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Cmp(estimate.Standard.MaxPriorityFeePerGas.ToInt()) != 0 {
+		t.Fatalf("suggested tip %v, want standard tier %v", tip, estimate.Standard.MaxPriorityFeePerGas)
+	}
+	if _, err := oracle.SuggestImmutableTipCap(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if reads := backend.reads.Load(); reads != 1 {
+		t.Fatalf("pool content read %d times, want once", reads)
+	}
+}
+
+func TestImmutableFeeHistory_PendingPool(t *testing.T) {
+	backend := newTestPoolBackend(t)
+	defer backend.teardown()
+
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, MaxHeaderHistory: 100, MaxBlockHistory: 100, Default: big.NewInt(params.GWei)})
+	oldest, rewards, baseFees, ratios, err := oracle.FeeHistory(context.Background(), 2, rpc.PendingBlockNumber, []float64{50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last mined block and the pending block estimated from the pool
+	head, _ := backend.HeaderByNumber(context.Background(), rpc.LatestBlockNumber)
+	if oldest.Uint64() != head.Number.Uint64() || len(rewards) != 2 || len(baseFees) != 3 || len(ratios) != 2 {
+		t.Fatalf("unexpected fee history from %v: %d rewards, %d base fees, %d ratios", oldest, len(rewards), len(baseFees), len(ratios))
+	}
+	if rewards[0][0].Int64() != 20*params.GWei || rewards[1][0].Int64() != 100*params.GWei {
+		t.Fatalf("unexpected rewards %v", rewards)
+	}
+	if next := eip1559.CalcBaseFee(backend.ChainConfig(), head); baseFees[1].Cmp(next) != 0 {
+		t.Fatalf("pending base fee %v, want %v", baseFees[1], next)
+	}
+	if want := float64(10*params.TxGas) / float64(head.GasLimit); ratios[1] != want {
+		t.Fatalf("pending gas used ratio %v, want %v", ratios[1], want)
+	}
+}
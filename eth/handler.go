@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
@@ -41,6 +42,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/triedb/pathdb"
 )
 
@@ -98,6 +100,13 @@ type handlerConfig struct {
 	GossipDefault bool // Whether to use default gossip configuration
 	// CHANGE(immutable): disable txpool gossip configuration
 	DisableTxPoolGossip bool
+	// CHANGE(immutable): per-role gossip policies
+	Gossip ethconfig.GossipConfig
+	// CHANGE(immutable): block fetcher catch-up mode
+	FetcherCatchUp string
+	// CHANGE(immutable): peer registry signed by a boot node
+	PeerRegistry        string        // URL of the boot node's public registry RPC
+	PeerRegistrySigners []*enode.Node // Boot nodes trusted to sign the registry
 }
 
 type handler struct {
@@ -137,6 +146,10 @@ type handler struct {
 	gossipDefault bool
 	// CHANGE(immutable): disable txpool gossip configuration
 	disableTxPoolGossip bool
+	// CHANGE(immutable): per-role gossip policies
+	gossip *gossipPolicies
+	// CHANGE(immutable): peer registry prioritizing validators, nil if not configured
+	registry *peerRegistry
 }
 
 // newHandler returns a handler for all Ethereum chain management protocol.
@@ -163,6 +176,17 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		// CHANGE(immutable): disable txpool gossip configuration
 		disableTxPoolGossip: config.DisableTxPoolGossip,
 	}
+	// CHANGE(immutable): peer registry prioritizing validators
+	var err error
+	if config.PeerRegistry != "" {
+		if h.registry, err = newPeerRegistry(config.PeerRegistry, config.PeerRegistrySigners); err != nil {
+			return nil, err
+		}
+	}
+	// CHANGE(immutable): per-role gossip policies, the registry's validators included
+	if h.gossip, err = newGossipPolicies(config.Gossip, config.GossipDefault, h.registry); err != nil {
+		return nil, err
+	}
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
 		// block is ahead, so snap sync was enabled for this node at a certain point.
@@ -283,6 +307,22 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return h.chain.InsertChain(blocks)
 	}
 	h.blockFetcher = fetcher.NewBlockFetcher(false, nil, h.chain.GetBlockByHash, validator, h.BroadcastBlock, heighter, nil, inserter, h.removePeer)
+	// CHANGE(immutable): Attribute propagated blocks to their peer for fork quarantine
+	// and let the consensus engine inspect their seals for equivocations
+	h.blockFetcher.SetOriginRecorder(func(peer string, blocks types.Blocks) {
+		h.chain.RecordBlockOrigin("fetcher", peer, blocks)
+		if observer := headerObserverOf(h.chain.Engine()); observer != nil {
+			for _, block := range blocks {
+				observer.ObserveHeader(h.chain, block.Header(), "fetcher", peer)
+			}
+		}
+	})
+	// CHANGE(immutable): Configure the catch-up mode of the block fetcher
+	if config.FetcherCatchUp != "" {
+		if err := h.blockFetcher.SetCatchUpMode(config.FetcherCatchUp); err != nil {
+			return nil, err
+		}
+	}
 
 	fetchTx := func(peer string, hashes []common.Hash) error {
 		p := h.peers.peer(peer)
@@ -376,7 +416,10 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 		}
 	}
 	// Ignore maxPeers if this is a trusted peer
-	if !peer.Peer.Info().Network.Trusted {
+	// CHANGE(immutable): or a priority peer, e.g. a static peer or a registered validator
+	network := peer.Peer.Info().Network
+	pinned := network.Trusted || network.Static
+	if !network.Trusted && !h.isPriorityPeer(peer.Peer.ID(), pinned) {
 		if reject || h.peers.len() >= h.maxPeers {
 			return p2p.DiscTooManyPeers
 		}
@@ -395,6 +438,10 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	if p == nil {
 		return errors.New("peer dropped during handling")
 	}
+	// CHANGE(immutable): remember whether the peer was configured by the operator
+	// and pick the gossip policy of the peer's role
+	p.pinned.Store(pinned)
+	p.gossip.Store(h.gossip.policyOf(peer.Peer, pinned))
 	// Register the peer in the downloader. If the downloader considers it banned, we disconnect
 	if err := h.downloader.RegisterPeer(peer.ID(), peer.Version(), peer); err != nil {
 		peer.Log().Error("Failed to register peer in eth syncer", "err", err)
@@ -520,6 +567,8 @@ func (h *handler) unregisterPeer(id string) {
 	}
 	h.downloader.UnregisterPeer(id)
 	h.txFetcher.Drop(id)
+	// CHANGE(immutable): Stop counting the peer's best number towards the catch-up mode
+	h.blockFetcher.ForgetPeer(id)
 
 	if err := h.peers.unregisterPeer(id); err != nil {
 		logger.Error("Ethereum peer removal failed", "err", err)
@@ -547,6 +596,12 @@ func (h *handler) Start(maxPeers int) {
 	// start peer handler tracker
 	h.wg.Add(1)
 	go h.protoTracker()
+
+	// CHANGE(immutable): keep the peer registry up to date
+	if h.registry != nil {
+		h.wg.Add(1)
+		go h.registryLoop()
+	}
 }
 
 func (h *handler) Stop() {
@@ -595,7 +650,10 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 			return
 		}
 		// Send the block to a subset of our peers
-		transfer := peers[:int(math.Sqrt(float64(len(peers))))]
+		// CHANGE(immutable): always include the priority peers
+		transfer := blockTransferPeers(peers, func(peer *ethPeer) bool {
+			return h.isPriorityPeer(peer.Peer.Peer.ID(), peer.pinned.Load())
+		})
 		for _, peer := range transfer {
 			peer.AsyncSendNewBlock(block, td)
 		}
@@ -613,10 +671,10 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 
 // BroadcastTransactions will propagate a batch of transactions
 // CHANGE(immutable):
-// - Add conditional logic to send transactions to all peers or the geth default which is a subset of them
-// - When gossipping to all peers, txs will be gossiped regardless of whether the node has received the transaction before
-// - No announcements should be sent, as all peers should receive the full tx
-// - When gossiping to a subset the gossiping will use geths default logic, where a subset of peers will receive the txs
+// - Send transactions according to the gossip policy of each peer's role
+// - Peers with the full policy get txs regardless of whether the node has received the transaction before
+// - Peers with the sqrt policy use geths default logic, where a subset of them will receive the txs
+// - Peers with the announce policy only get announcements, and peers with the none policy nothing
 func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	var (
 		blobTxs  int // Number of blob transactions to announce only
@@ -629,10 +687,19 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 
 		txset = make(map[*ethPeer][]common.Hash) // Set peer->hash to transfer directly
 		annos = make(map[*ethPeer][]common.Hash) // Set peer->hash to announce
+
+		peers    = h.peers.allPeers()
+		policies = make(map[*ethPeer]*gossipPolicy, len(peers))
 	)
-	// CHANGE(immutable): Broadcast transactions to all peers, regardless of whether
-	// they are known to have previously received it. This is to prevent loss of transactions
-	// in smaller networks.
+	// CHANGE(immutable): resolve the gossip policy of every peer once per batch
+	for _, peer := range peers {
+		policy := peer.gossip.Load()
+		if policy == nil {
+			// Peer registered, but its role not yet resolved
+			policy = h.gossip.policies[gossipRoleDefault]
+		}
+		policies[peer] = policy
+	}
 	for _, tx := range txs {
 		switch {
 		case tx.Type() == types.BlobTxType:
@@ -640,45 +707,53 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		case tx.Size() > txMaxBroadcastSize:
 			largeTxs++
 		}
-
-		// CHANGE(immutable): conditionally send to a subset of peers (default), otherwise send to all peers
-		if h.gossipDefault {
-			// CHANGE(immutable): Send the txs to a subset of peers. This is the default behaviour for geth.
-			peers := h.peers.peersWithoutTransaction(tx.Hash())
-			var numDirect int
-			if tx.Size() <= txMaxBroadcastSize {
-				numDirect = int(math.Sqrt(float64(len(peers))))
-			}
-
-			// Send the tx unconditionally to a subset of our peers
-			for _, peer := range peers[:numDirect] {
-				txset[peer] = append(txset[peer], tx.Hash())
-			}
-
-			// For the remaining peers, send announcement only
-			for _, peer := range peers[numDirect:] {
-				annos[peer] = append(annos[peer], tx.Hash())
-			}
-		} else {
-			// CHANGE(immutable): Send the tx unconditionally to all our peers, rather than a subset.
-			// Sending transactions to a subset of peers is only applicable for large scale networks.
-			// All peers will receive the full transaction payload instead of an announcement.
-			peers := h.peers.allPeers()
-			for _, peer := range peers[:] {
+		var sqrtPeers []*ethPeer
+		for _, peer := range peers {
+			policy := policies[peer]
+			switch policy.mode {
+			case gossipFull:
+				// CHANGE(immutable): Send the tx unconditionally, even to peers known to have it.
+				// This is to prevent loss of transactions in smaller networks.
+				if peer.KnownTransaction(tx.Hash()) {
+					policy.duplicateMeter.Mark(1)
+				}
 				txset[peer] = append(txset[peer], tx.Hash())
+				policy.bytesMeter.Mark(int64(tx.Size()))
+			case gossipSqrt:
+				if !peer.KnownTransaction(tx.Hash()) {
+					sqrtPeers = append(sqrtPeers, peer)
+				}
+			case gossipAnnounce:
+				if !peer.KnownTransaction(tx.Hash()) {
+					annos[peer] = append(annos[peer], tx.Hash())
+				}
 			}
 		}
+		// Send the tx to a subset of the sqrt policy peers. This is the default behaviour for geth.
+		var numDirect int
+		if tx.Size() <= txMaxBroadcastSize {
+			numDirect = int(math.Sqrt(float64(len(sqrtPeers))))
+		}
+		for _, peer := range sqrtPeers[:numDirect] {
+			txset[peer] = append(txset[peer], tx.Hash())
+			policies[peer].bytesMeter.Mark(int64(tx.Size()))
+		}
+		// For the remaining peers, send announcement only
+		for _, peer := range sqrtPeers[numDirect:] {
+			annos[peer] = append(annos[peer], tx.Hash())
+		}
 	}
 	for peer, hashes := range txset {
 		directPeers++
 		directCount += len(hashes)
+		policies[peer].txMeter.Mark(int64(len(hashes)))
 		peer.AsyncSendTransactions(hashes)
 	}
 	for peer, hashes := range annos {
-		// CHANGE(immutable): Add warning logs for announcements as we do not expect them to occur
-		log.Warn("Sending announcements unexpectedly", "announced hashes", len(annos))
 		annPeers++
 		annCount += len(hashes)
+		policies[peer].annMeter.Mark(int64(len(hashes)))
+		policies[peer].bytesMeter.Mark(int64(len(hashes) * announceSize))
 		peer.AsyncSendPooledTransactionHashes(hashes)
 	}
 	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
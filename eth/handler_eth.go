@@ -95,9 +95,13 @@ func (h *ethHandler) Handle(peer *eth.Peer, packet eth.Packet) error {
 				return errors.New("disallowed broadcast blob transaction")
 			}
 		}
+		// CHANGE(immutable): meter received transactions by gossip policy
+		h.meterGossipReceived(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, false)
 
 	case *eth.PooledTransactionsResponse:
+		// CHANGE(immutable): meter received transactions by gossip policy
+		h.meterGossipReceived(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, true)
 
 	default:
@@ -0,0 +1,54 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/core/txpool"
+)
+
+// ReloadAccessControl forces the transaction pool to re-read its access-control
+// lists, evicting pooled transactions that are no longer authorized. The
+// statuses of all lists are returned after the reload, alongside any error
+// encountered while loading; lists that failed keep their previous contents.
+func (api *AdminAPI) ReloadAccessControl() ([]txpool.AccessListStatus, error) {
+	return api.eth.TxPool().ReloadAccessControl()
+}
+
+// TxPoolAccessControlAPI exposes the transaction pool's access-control state
+// under the txpool namespace.
+type TxPoolAccessControlAPI struct {
+	eth *Ethereum
+}
+
+// NewTxPoolAccessControlAPI creates a new instance of TxPoolAccessControlAPI.
+func NewTxPoolAccessControlAPI(eth *Ethereum) *TxPoolAccessControlAPI {
+	return &TxPoolAccessControlAPI{eth: eth}
+}
+
+// AccessControlStatus reports the size, content hash and last load time of
+// every access-control list used by the transaction pool.
+func (api *TxPoolAccessControlAPI) AccessControlStatus() []txpool.AccessListStatus {
+	return api.eth.TxPool().AccessControlStatus()
+}
+
+// AccessControlRejections returns a page of the audit log of transactions
+// dropped by the access controls, in chronological order. Records are
+// selected by time range; the next page is retrieved by passing back the
+// returned cursor with the same range.
+func (api *TxPoolAccessControlAPI) AccessControlRejections(query txpool.AccessControlRejectionQuery) (*txpool.AccessControlRejectionPage, error) {
+	return api.eth.TxPool().AccessControlRejections(query)
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// QuarantinedForkSummary is the overview of a quarantined fork returned by
+// debug_listQuarantinedForks.
+type QuarantinedForkSummary struct {
+	ID             common.Hash         `json:"id"`
+	Time           hexutil.Uint64      `json:"quarantinedAt"`
+	CommonNumber   hexutil.Uint64      `json:"commonNumber"`
+	CommonHash     common.Hash         `json:"commonHash"`
+	Truncated      bool                `json:"truncated"`
+	CanonicalHead  common.Hash         `json:"canonicalHead"`
+	CanonicalCount int                 `json:"canonicalLength"`
+	SideCount      int                 `json:"sideLength"`
+	Origins        []rawdb.BlockOrigin `json:"origins"`
+}
+
+// ListQuarantinedForks returns the competing forks which were refused by the
+// reorg invariant, most recent first.
+func (api *DebugAPI) ListQuarantinedForks() []*QuarantinedForkSummary {
+	forks := rawdb.ReadAllQuarantinedForks(api.eth.chainDb)
+	results := make([]*QuarantinedForkSummary, 0, len(forks))
+	for _, fork := range forks {
+		summary := &QuarantinedForkSummary{
+			ID:             fork.ID(),
+			Time:           hexutil.Uint64(fork.Time),
+			CommonNumber:   hexutil.Uint64(fork.CommonNumber),
+			CommonHash:     fork.CommonHash,
+			Truncated:      fork.Truncated,
+			CanonicalCount: len(fork.Canonical),
+			SideCount:      len(fork.Side),
+			Origins:        fork.Origins,
+		}
+		if len(fork.Canonical) > 0 {
+			summary.CanonicalHead = fork.Canonical[len(fork.Canonical)-1].Hash()
+		}
+		results = append(results, summary)
+	}
+	return results
+}
+
+// GetQuarantinedFork returns the header, signer and transaction diff between
+// the canonical branch and the quarantined fork with the given id.
+func (api *DebugAPI) GetQuarantinedFork(id common.Hash) (*core.ForkDiff, error) {
+	fork := rawdb.ReadQuarantinedFork(api.eth.chainDb, id)
+	if fork == nil {
+		return nil, fmt.Errorf("quarantined fork %x not found", id)
+	}
+	return core.DiffQuarantinedFork(fork, api.eth.engine.Author), nil
+}
@@ -14,38 +14,31 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-package long
+package eth
 
 import (
-	"math"
-	"os"
+	"context"
 
-	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 )
 
-const (
-	longSyncEnvVar = "GETH_FLAG_IMMUTABLE_LONG_RANGE_SYNC"
-)
-
-var (
-	// blockFetchDistance is increased if the relevant env var is set.
-	blockFetchDistance = 256
-)
+// ImmutableAPI exposes Immutable specific methods under the immutable namespace.
+type ImmutableAPI struct {
+	eth *Ethereum
+}
 
-func init() {
-	// Enable long fetch if env var is set.
-	if isLongFetch := os.Getenv(longSyncEnvVar); isLongFetch != "" {
-		log.Info("Long range sync enabled")
-		blockFetchDistance = math.MaxInt32
-	} else {
-		log.Info("Long range sync disabled")
-	}
+// NewImmutableAPI creates a new instance of ImmutableAPI.
+func NewImmutableAPI(eth *Ethereum) *ImmutableAPI {
+	return &ImmutableAPI{eth: eth}
 }
 
-// BlockFetchDistance returns the maximum number of blocks that should be accepted
-// when fetching blocks from peers.
-// The value will be large enough for any range of blocks if the relevant env var is set.
-// Otherwise it will return a reasonable value for normal operation.
-func BlockFetchDistance() int {
-	return blockFetchDistance
+// FeeEstimate returns slow, standard and fast fees for a transaction to stay
+// includable for the given number of blocks, five by default.
+func (api *ImmutableAPI) FeeEstimate(ctx context.Context, blocks *hexutil.Uint) (*gasprice.FeeEstimate, error) {
+	n := gasprice.DefaultFeeEstimateBlocks
+	if blocks != nil {
+		n = int(*blocks)
+	}
+	return api.eth.APIBackend.gpo.FeeEstimate(ctx, n)
 }
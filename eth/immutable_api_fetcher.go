@@ -0,0 +1,35 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/eth/fetcher"
+)
+
+// FetcherCatchUp returns the catch-up state of the block fetcher.
+func (api *AdminAPI) FetcherCatchUp() fetcher.CatchUpStatus {
+	return api.eth.handler.blockFetcher.CatchUpStatus()
+}
+
+// SetFetcherCatchUp switches the catch-up mode of the block fetcher to off,
+// auto or on, returning the resulting state.
+func (api *AdminAPI) SetFetcherCatchUp(mode string) (fetcher.CatchUpStatus, error) {
+	if err := api.eth.handler.blockFetcher.SetCatchUpMode(mode); err != nil {
+		return fetcher.CatchUpStatus{}, err
+	}
+	return api.eth.handler.blockFetcher.CatchUpStatus(), nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/immutable"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	errNoSecretStore = errors.New("node does not load its signer from an Immutable secret store")
+	errNoClique      = errors.New("consensus engine is not clique")
+	errNoSignerKey   = errors.New("reloaded signer wallet has no accounts")
+)
+
+// RotateSigner reloads the sealing key from the node's secret store and
+// authorizes the clique engine to seal with it, replacing the previous signer
+// without restarting the node. It returns the address of the new signer.
+func (api *AdminAPI) RotateSigner(ctx context.Context) (common.Address, error) {
+	engine := api.eth.engine
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	c, ok := engine.(*clique.Clique)
+	if !ok {
+		return common.Address{}, errNoClique
+	}
+	backends := api.eth.accountManager.Backends(reflect.TypeOf(&immutable.Backend{}))
+	if len(backends) == 0 {
+		return common.Address{}, errNoSecretStore
+	}
+	wallet, err := backends[0].(*immutable.Backend).Reload(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	accounts := wallet.Accounts()
+	if len(accounts) == 0 {
+		return common.Address{}, errNoSignerKey
+	}
+	signer := accounts[0].Address
+
+	c.Authorize(signer, wallet.SignData)
+	api.eth.SetEtherbase(signer)
+	log.Info("Rotated clique signer", "signer", signer)
+	return signer, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headerObserver is implemented by consensus engines which inspect every sealed
+// header seen by the node, e.g. to detect equivocating signers.
+type headerObserver interface {
+	ObserveHeader(chain consensus.ChainHeaderReader, header *types.Header, source, peer string)
+}
+
+// headerObserverOf returns the header observer of the engine, looking through
+// the beacon wrapper, or nil if the engine does not observe headers.
+func headerObserverOf(engine consensus.Engine) headerObserver {
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	if observer, ok := engine.(headerObserver); ok {
+		return observer
+	}
+	return nil
+}
+
+// equivocationWatcher feeds the headers of canonical and side chain blocks to
+// the consensus engine's header observer.
+type equivocationWatcher struct {
+	observer headerObserver
+	chain    *core.BlockChain
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newEquivocationWatcher starts a watcher for the chain, or returns nil if the
+// engine does not observe headers.
+func newEquivocationWatcher(engine consensus.Engine, chain *core.BlockChain) *equivocationWatcher {
+	observer := headerObserverOf(engine)
+	if observer == nil {
+		return nil
+	}
+	w := &equivocationWatcher{
+		observer: observer,
+		chain:    chain,
+		quit:     make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.loop()
+	return w
+}
+
+func (w *equivocationWatcher) loop() {
+	defer w.wg.Done()
+
+	chainCh := make(chan core.ChainEvent, 64)
+	chainSub := w.chain.SubscribeChainEvent(chainCh)
+	defer chainSub.Unsubscribe()
+
+	sideCh := make(chan core.ChainSideEvent, 64)
+	sideSub := w.chain.SubscribeChainSideEvent(sideCh)
+	defer sideSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-chainCh:
+			w.observer.ObserveHeader(w.chain, ev.Block.Header(), "chain", "")
+		case ev := <-sideCh:
+			w.observer.ObserveHeader(w.chain, ev.Block.Header(), "side", "")
+		case <-chainSub.Err():
+			return
+		case <-sideSub.Err():
+			return
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// stop terminates the watcher and waits for it to exit.
+func (w *equivocationWatcher) stop() {
+	if w == nil {
+		return
+	}
+	close(w.quit)
+	w.wg.Wait()
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// Peer roles gossip policies are assigned to.
+const (
+	gossipRoleValidator = "validator"
+	gossipRoleBoot      = "boot"
+	gossipRoleRPC       = "rpc"
+	gossipRolePartner   = "partner"
+	gossipRoleInbound   = "inbound" // Unpinned inbound peers without an allowlisted or registered role
+	gossipRoleDefault   = "default" // Any other peer
+)
+
+// Transaction gossip modes.
+const (
+	gossipFull     = "full"     // Send all transactions, even if known to the peer
+	gossipSqrt     = "sqrt"     // Send to the square root of the peers, announce to the rest
+	gossipAnnounce = "announce" // Announce transactions unknown to the peer
+	gossipNone     = "none"     // Do not gossip transactions
+)
+
+// announceSize is the approximate wire size of a transaction announcement: its
+// hash, type and size.
+const announceSize = common.HashLength + 1 + 4
+
+var gossipRoles = []string{gossipRoleValidator, gossipRoleBoot, gossipRoleRPC, gossipRolePartner, gossipRoleInbound, gossipRoleDefault}
+
+// gossipPolicy is the gossip mode of a peer role along with its metrics.
+type gossipPolicy struct {
+	role string
+	mode string
+
+	txMeter        metrics.Meter // Transactions sent
+	annMeter       metrics.Meter // Transactions announced
+	bytesMeter     metrics.Meter // Bytes sent, transactions and announcements
+	duplicateMeter metrics.Meter // Transactions sent to peers already knowing them
+
+	inTxMeter        metrics.Meter // Transactions received
+	inBytesMeter     metrics.Meter // Bytes received
+	inDuplicateMeter metrics.Meter // Transactions received already in the pool
+}
+
+func newGossipPolicy(role, mode string) *gossipPolicy {
+	prefix := "eth/gossip/" + role + "/"
+	return &gossipPolicy{
+		role:             role,
+		mode:             mode,
+		txMeter:          metrics.GetOrRegisterMeter(prefix+"out/txs", nil),
+		annMeter:         metrics.GetOrRegisterMeter(prefix+"out/announces", nil),
+		bytesMeter:       metrics.GetOrRegisterMeter(prefix+"out/bytes", nil),
+		duplicateMeter:   metrics.GetOrRegisterMeter(prefix+"out/duplicates", nil),
+		inTxMeter:        metrics.GetOrRegisterMeter(prefix+"in/txs", nil),
+		inBytesMeter:     metrics.GetOrRegisterMeter(prefix+"in/bytes", nil),
+		inDuplicateMeter: metrics.GetOrRegisterMeter(prefix+"in/duplicates", nil),
+	}
+}
+
+// gossipPolicies resolves the gossip policy of peers.
+type gossipPolicies struct {
+	roles    map[enode.ID]string      // Roles assigned by the allowlists
+	registry *peerRegistry            // Boot node signed registry assigning the validator role, nil if not configured
+	policies map[string]*gossipPolicy // Policies by role
+}
+
+// newGossipPolicies creates the policies of the given configuration. Without
+// any configuration, all peers get the legacy behaviour: full transactions, or
+// the upstream square root fan-out if gossipDefault is set.
+func newGossipPolicies(config ethconfig.GossipConfig, gossipDefault bool, registry *peerRegistry) (*gossipPolicies, error) {
+	legacy := gossipFull
+	if gossipDefault {
+		legacy = gossipSqrt
+	}
+	modes := make(map[string]string)
+	for _, role := range gossipRoles {
+		modes[role] = legacy
+	}
+	configured := len(config.Validators)+len(config.Boot)+len(config.RPC)+len(config.Partners)+len(config.Policies) > 0
+	if configured {
+		modes[gossipRoleValidator] = gossipFull
+		modes[gossipRolePartner] = gossipAnnounce
+		modes[gossipRoleInbound] = gossipNone
+	}
+	for role, mode := range config.Policies {
+		if _, ok := modes[role]; !ok {
+			return nil, fmt.Errorf("unknown gossip role %q", role)
+		}
+		switch mode {
+		case gossipFull, gossipSqrt, gossipAnnounce, gossipNone:
+		default:
+			return nil, fmt.Errorf("invalid gossip policy %q for role %s", mode, role)
+		}
+		modes[role] = mode
+	}
+	g := &gossipPolicies{
+		roles:    make(map[enode.ID]string),
+		registry: registry,
+		policies: make(map[string]*gossipPolicy),
+	}
+	for role, mode := range modes {
+		g.policies[role] = newGossipPolicy(role, mode)
+	}
+	for role, list := range map[string][]string{
+		gossipRoleValidator: config.Validators,
+		gossipRoleBoot:      config.Boot,
+		gossipRoleRPC:       config.RPC,
+		gossipRolePartner:   config.Partners,
+	} {
+		for _, entry := range list {
+			id, err := parseGossipNodeID(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s gossip node %q: %w", role, entry, err)
+			}
+			if prev, ok := g.roles[id]; ok && prev != role {
+				return nil, fmt.Errorf("gossip node %s listed as both %s and %s", id, prev, role)
+			}
+			g.roles[id] = role
+		}
+	}
+	return g, nil
+}
+
+// parseGossipNodeID parses an enode URL or a hex node ID.
+func parseGossipNodeID(entry string) (enode.ID, error) {
+	if strings.HasPrefix(entry, "enode://") {
+		node, err := enode.ParseV4(entry)
+		if err != nil {
+			return enode.ID{}, err
+		}
+		return node.ID(), nil
+	}
+	return enode.ParseID(entry)
+}
+
+// policyOf returns the gossip policy of a connected peer, pinned if it is a
+// static or trusted peer configured by the operator.
+func (g *gossipPolicies) policyOf(peer *p2p.Peer, pinned bool) *gossipPolicy {
+	return g.policy(peer.Node(), peer.Inbound(), pinned)
+}
+
+// policy returns the gossip policy of a node. Only the allowlists, the signed
+// registry and the operator vouch for a role: any node can advertise a role in
+// its ENR, so the ENR role is only used for pinned peers. Other peers get the
+// lowest-privilege role of their direction, inbound or default.
+func (g *gossipPolicies) policy(node *enode.Node, inbound bool, pinned bool) *gossipPolicy {
+	if role, ok := g.roles[node.ID()]; ok {
+		return g.policies[role]
+	}
+	if g.registry.isValidator(node.ID()) {
+		return g.policies[gossipRoleValidator]
+	}
+	if pinned {
+		var role enr.Role
+		if node.Load(&role) == nil && role != gossipRoleInbound && role != gossipRoleDefault {
+			if policy, ok := g.policies[string(role)]; ok {
+				return policy
+			}
+		}
+		return g.policies[gossipRoleDefault]
+	}
+	if inbound {
+		return g.policies[gossipRoleInbound]
+	}
+	return g.policies[gossipRoleDefault]
+}
+
+// refreshGossipPolicies re-evaluates the gossip policy of every connected peer,
+// after the registry assigning the validator role changed.
+func (h *handler) refreshGossipPolicies() {
+	for _, peer := range h.peers.allPeers() {
+		policy := h.gossip.policyOf(peer.Peer.Peer, peer.pinned.Load())
+		if prev := peer.gossip.Swap(policy); prev != policy {
+			peer.Log().Debug("Updated transaction gossip role", "role", policy.role, "mode", policy.mode)
+		}
+	}
+}
+
+// meterGossipReceived meters the transactions received from a peer under its
+// gossip policy, counting those already in the pool as duplicates.
+func (h *ethHandler) meterGossipReceived(peer *eth.Peer, txs []*types.Transaction) {
+	p := h.peers.peer(peer.ID())
+	if p == nil {
+		return
+	}
+	policy := p.gossip.Load()
+	if policy == nil {
+		return
+	}
+	var size, duplicates int
+	for _, tx := range txs {
+		size += int(tx.Size())
+		if h.txpool.Has(tx.Hash()) {
+			duplicates++
+		}
+	}
+	policy.inTxMeter.Mark(int64(len(txs)))
+	policy.inBytesMeter.Mark(int64(size))
+	policy.inDuplicateMeter.Mark(int64(duplicates))
+}
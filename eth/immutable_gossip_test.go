@@ -0,0 +1,160 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+func TestImmutableGossipPolicies(t *testing.T) {
+	node := func(role string) *enode.Node {
+		key, _ := crypto.GenerateKey()
+		var r enr.Record
+		if role != "" {
+			r.Set(enr.Role(role))
+		}
+		return enode.SignNull(&r, enode.PubkeyToIDV4(&key.PublicKey))
+	}
+	// Without configuration all peers keep the legacy behaviour
+	for gossipDefault, mode := range map[bool]string{false: gossipFull, true: gossipSqrt} {
+		g, err := newGossipPolicies(ethconfig.GossipConfig{}, gossipDefault, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range []*enode.Node{node(""), node(gossipRoleValidator)} {
+			if policy := g.policy(n, true, false); policy.mode != mode {
+				t.Errorf("gossipDefault=%v: expected %s policy, got %s", gossipDefault, mode, policy.mode)
+			}
+		}
+	}
+	var (
+		validator     = node("")
+		registered    = node(gossipRoleRPC)
+		partnerKey, _ = crypto.GenerateKey()
+		partner       = enode.NewV4(&partnerKey.PublicKey, nil, 30303, 30303)
+		registry      = &peerRegistry{validators: map[enode.ID]struct{}{registered.ID(): {}}}
+	)
+	g, err := newGossipPolicies(ethconfig.GossipConfig{
+		Validators: []string{validator.ID().String()},
+		Partners:   []string{partner.URLv4()},
+		Policies:   map[string]string{gossipRoleRPC: gossipAnnounce},
+	}, false, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		node    *enode.Node
+		inbound bool
+		pinned  bool
+		role    string
+		mode    string
+	}{
+		{validator, true, false, gossipRoleValidator, gossipFull},
+		{partner, false, false, gossipRolePartner, gossipAnnounce},
+		{node(gossipRoleRPC), false, true, gossipRoleRPC, gossipAnnounce},
+		{node(gossipRoleValidator), true, false, gossipRoleInbound, gossipNone},  // Unpinned peers cannot claim roles
+		{node(gossipRoleValidator), false, false, gossipRoleDefault, gossipFull}, // Neither when dialed through discovery
+		{registered, true, false, gossipRoleValidator, gossipFull},               // Registry validators are trusted
+		{node(gossipRoleBoot), false, true, gossipRoleBoot, gossipFull},
+		{node(gossipRoleDefault), true, true, gossipRoleDefault, gossipFull}, // ENR cannot claim fallback roles
+		{node("unknown"), false, true, gossipRoleDefault, gossipFull},
+	}
+	for i, tt := range tests {
+		if policy := g.policy(tt.node, tt.inbound, tt.pinned); policy.role != tt.role || policy.mode != tt.mode {
+			t.Errorf("test %d: expected %s/%s, got %s/%s", i, tt.role, tt.mode, policy.role, policy.mode)
+		}
+	}
+	for i, invalid := range []ethconfig.GossipConfig{
+		{Policies: map[string]string{"sequencer": gossipFull}},
+		{Policies: map[string]string{gossipRoleRPC: "some"}},
+		{Validators: []string{"enode://invalid"}},
+		{Validators: []string{validator.ID().String()}, RPC: []string{validator.ID().String()}},
+	} {
+		if _, err := newGossipPolicies(invalid, false, nil); err == nil {
+			t.Errorf("config %d: expected error", i)
+		}
+	}
+}
+
+func TestImmutableBroadcastTransactions(t *testing.T) {
+	pool := newTestTxPool()
+	g, err := newGossipPolicies(ethconfig.GossipConfig{
+		Policies: map[string]string{gossipRoleDefault: gossipAnnounce},
+	}, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler{peers: newPeerSet(), gossip: g}
+
+	// Connect a peer of every role, the remote ends receiving the broadcasts
+	remotes := make(map[string]*p2p.MsgPipeRW)
+	for i, role := range []string{gossipRoleValidator, gossipRoleDefault, gossipRoleInbound} {
+		local, remote := p2p.MsgPipe()
+		defer local.Close()
+		defer remote.Close()
+
+		peer := eth.NewPeer(eth.ETH68, p2p.NewPeerPipe(enode.ID{byte(i)}, "", nil, local), local, pool)
+		defer peer.Close()
+		if err := h.peers.registerPeer(peer, nil); err != nil {
+			t.Fatal(err)
+		}
+		h.peers.peer(peer.ID()).gossip.Store(g.policies[role])
+		remotes[role] = remote
+	}
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Add([]*types.Transaction{tx}, false, false)
+	h.BroadcastTransactions(types.Transactions{tx})
+
+	read := func(remote *p2p.MsgPipeRW) (uint64, bool) {
+		msgs := make(chan uint64, 1)
+		go func() {
+			if msg, err := remote.ReadMsg(); err == nil {
+				msg.Discard()
+				msgs <- msg.Code
+			}
+		}()
+		select {
+		case code := <-msgs:
+			return code, true
+		case <-time.After(250 * time.Millisecond):
+			return 0, false
+		}
+	}
+	if code, ok := read(remotes[gossipRoleValidator]); !ok || code != eth.TransactionsMsg {
+		t.Errorf("validator: expected transactions, got code %d (received %v)", code, ok)
+	}
+	if code, ok := read(remotes[gossipRoleDefault]); !ok || code != eth.NewPooledTransactionHashesMsg {
+		t.Errorf("default: expected announcement, got code %d (received %v)", code, ok)
+	}
+	if code, ok := read(remotes[gossipRoleInbound]); ok {
+		t.Errorf("inbound: expected nothing, got code %d", code)
+	}
+}
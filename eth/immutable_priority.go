@@ -0,0 +1,175 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/registry"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/role"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	registryPollInterval = time.Minute      // Interval at which the peer registry is fetched
+	registryFetchTimeout = 10 * time.Second // Timeout of a peer registry fetch
+)
+
+var errNoRegistrySigners = errors.New("peer registry requires boot nodes to verify it against")
+
+// peerRegistry holds the validators of the peer registry served by a boot
+// node. Only registries signed by one of the boot nodes are accepted, as the
+// roles advertised in ENRs are asserted by the peers themselves.
+type peerRegistry struct {
+	url     string     // Endpoint of the boot node's public registry RPC
+	signers []enode.ID // Boot nodes trusted to sign the registry
+
+	lock       sync.RWMutex
+	seq        uint64
+	validators map[enode.ID]struct{}
+}
+
+// newPeerRegistry creates a peer registry fetched from the URL, which must be
+// signed by one of the boot nodes.
+func newPeerRegistry(url string, boots []*enode.Node) (*peerRegistry, error) {
+	if len(boots) == 0 {
+		return nil, errNoRegistrySigners
+	}
+	r := &peerRegistry{
+		url:        url,
+		validators: make(map[enode.ID]struct{}),
+	}
+	for _, boot := range boots {
+		r.signers = append(r.signers, boot.ID())
+	}
+	return r, nil
+}
+
+// isValidator reports whether the node is a validator of the registry.
+func (r *peerRegistry) isValidator(id enode.ID) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.validators[id]
+	return ok
+}
+
+// fetch retrieves the registry from the boot node and applies it, returning
+// whether the validators changed.
+func (r *peerRegistry) fetch(ctx context.Context) (bool, error) {
+	client, err := rpc.DialContext(ctx, r.url)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	var signed registry.Signed
+	if err := client.CallContext(ctx, &signed, "admin_registry"); err != nil {
+		return false, err
+	}
+	return r.update(&signed)
+}
+
+// update replaces the validators with those of the registry, unless it is not
+// signed by a boot node or older than the current one. It returns whether the
+// validators changed.
+func (r *peerRegistry) update(signed *registry.Signed) (bool, error) {
+	if !slices.ContainsFunc(r.signers, func(id enode.ID) bool { return signed.Verify(id) == nil }) {
+		signer, err := signed.Signer()
+		if err != nil {
+			return false, err
+		}
+		return false, fmt.Errorf("%w: signed by %s", registry.ErrInvalidSignature, signer)
+	}
+	validators := make(map[enode.ID]struct{})
+	for _, member := range signed.Members {
+		if member.Role == role.Validator.String() {
+			validators[member.ID] = struct{}{}
+		}
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if signed.Seq < r.seq {
+		return false, fmt.Errorf("stale peer registry sequence %d, have %d", signed.Seq, r.seq)
+	}
+	changed := !maps.Equal(r.validators, validators)
+	r.seq = signed.Seq
+	r.validators = validators
+	return changed, nil
+}
+
+// registryLoop fetches the peer registry periodically until the handler stops.
+func (h *handler) registryLoop() {
+	defer h.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			ctx, cancel := context.WithTimeout(context.Background(), registryFetchTimeout)
+			changed, err := h.registry.fetch(ctx)
+			if err != nil {
+				log.Warn("Failed to fetch peer registry", "url", h.registry.url, "err", err)
+			}
+			// Peers connected before the registry changed may have gained or
+			// lost the validator role
+			if changed {
+				h.refreshGossipPolicies()
+			}
+			cancel()
+			timer.Reset(registryPollInterval)
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
+// isPriorityPeer reports whether the peer is prioritized: it is accepted above
+// the peer limit and sent every propagated block. Trusted and static peers,
+// configured by the operator, are, as well as the validators of the registry
+// signed by a boot node, which seal the next blocks.
+func (h *handler) isPriorityPeer(id enode.ID, pinned bool) bool {
+	return pinned || h.registry.isValidator(id)
+}
+
+// blockTransferPeers returns the peers a propagated block is sent to: every
+// priority peer and the square root of the others.
+func blockTransferPeers(peers []*ethPeer, priority func(*ethPeer) bool) []*ethPeer {
+	var prioritized, others []*ethPeer
+	for _, peer := range peers {
+		if priority(peer) {
+			prioritized = append(prioritized, peer)
+		} else {
+			others = append(others, peer)
+		}
+	}
+	return append(prioritized, others[:int(math.Sqrt(float64(len(others))))]...)
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/registry"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestImmutableBlockTransferPeers(t *testing.T) {
+	// Two priority peers and nine other peers
+	var (
+		peers    []*ethPeer
+		priority = make(map[*ethPeer]bool)
+	)
+	for i := 0; i < 11; i++ {
+		peer := &ethPeer{Peer: eth.NewPeer(eth.ETH68, p2p.NewPeer(enode.ID{byte(i)}, "", nil), nil, nil)}
+		defer peer.Close()
+		priority[peer] = i%5 == 0 && i > 0
+		peers = append(peers, peer)
+	}
+	transfer := blockTransferPeers(peers, func(peer *ethPeer) bool { return priority[peer] })
+	if len(transfer) != 2+3 {
+		t.Fatalf("expected 5 peers, got %d", len(transfer))
+	}
+	for _, peer := range transfer[:2] {
+		if !priority[peer] {
+			t.Errorf("expected priority peers first, got %s", peer.ID())
+		}
+	}
+	// Without priority peers, blocks reach the square root of the peers as upstream
+	if transfer := blockTransferPeers(peers, func(*ethPeer) bool { return false }); len(transfer) != 3 {
+		t.Fatalf("expected 3 peers, got %d", len(transfer))
+	}
+}
+
+// Tests that only the validators of a registry signed by a boot node are
+// prioritized, along with the peers configured by the operator.
+func TestImmutablePeerRegistry(t *testing.T) {
+	bootKey, _ := crypto.GenerateKey()
+	boot := enode.NewV4(&bootKey.PublicKey, nil, 0, 0)
+
+	reg, err := registry.Open("", bootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, rpcNode := enode.ID{0x01}, enode.ID{0x02}
+	if err := reg.Add(validator, "validator"); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(rpcNode, "rpc"); err != nil {
+		t.Fatal(err)
+	}
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	if err := srv.RegisterName("admin", registry.NewAPI(reg)); err != nil {
+		t.Fatal(err)
+	}
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	if _, err := newPeerRegistry(httpSrv.URL, nil); !errors.Is(err, errNoRegistrySigners) {
+		t.Fatalf("expected %v, got %v", errNoRegistrySigners, err)
+	}
+	peers, err := newPeerRegistry(httpSrv.URL, []*enode.Node{boot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gossip, err := newGossipPolicies(ethconfig.GossipConfig{
+		Policies: map[string]string{gossipRoleDefault: gossipAnnounce},
+	}, false, peers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler{registry: peers, gossip: gossip, peers: newPeerSet()}
+
+	// A validator connected before the registry is fetched gets its role once
+	// the registry changes
+	connected := eth.NewPeer(eth.ETH68, p2p.NewPeer(validator, "", nil), nil, nil)
+	defer connected.Close()
+	if err := h.peers.registerPeer(connected, nil); err != nil {
+		t.Fatal(err)
+	}
+	peer := h.peers.peer(connected.ID())
+	peer.gossip.Store(gossip.policyOf(connected.Peer, false))
+	if role := peer.gossip.Load().role; role != gossipRoleDefault {
+		t.Fatalf("expected %s role before the registry is fetched, got %s", gossipRoleDefault, role)
+	}
+	if _, err := peers.fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	h.refreshGossipPolicies()
+	if role := peer.gossip.Load().role; role != gossipRoleValidator {
+		t.Fatalf("expected %s role once registered, got %s", gossipRoleValidator, role)
+	}
+	if !h.isPriorityPeer(validator, false) {
+		t.Error("registered validator not prioritized")
+	}
+	if h.isPriorityPeer(rpcNode, false) || h.isPriorityPeer(enode.ID{0x03}, false) {
+		t.Error("non-validator prioritized")
+	}
+	if !h.isPriorityPeer(enode.ID{0x03}, true) {
+		t.Error("trusted or static peer not prioritized")
+	}
+	// Registries signed by another node are refused
+	otherKey, _ := crypto.GenerateKey()
+	forged, err := registry.Open("", otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := forged.Add(enode.ID{0x03}, "validator"); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := forged.Signed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed.Seq = 100
+	if _, err := peers.update(signed); !errors.Is(err, registry.ErrInvalidSignature) {
+		t.Fatalf("expected %v, got %v", registry.ErrInvalidSignature, err)
+	}
+	if h.isPriorityPeer(enode.ID{0x03}, false) {
+		t.Error("validator of forged registry prioritized")
+	}
+	// Stale registries are refused, revocations apply once fetched
+	stale, err := reg.Signed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Revoke(validator); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := peers.fetch(context.Background()); err != nil || !changed {
+		t.Fatalf("fetch failed: changed %v, err %v", changed, err)
+	}
+	if h.isPriorityPeer(validator, false) {
+		t.Error("revoked validator still prioritized")
+	}
+	h.refreshGossipPolicies()
+	if role := peer.gossip.Load().role; role != gossipRoleDefault {
+		t.Errorf("expected %s role once revoked, got %s", gossipRoleDefault, role)
+	}
+	if _, err := peers.update(stale); err == nil {
+		t.Fatal("expected stale registry error")
+	}
+	// Without a registry, only configured peers are prioritized
+	h = &handler{}
+	if h.isPriorityPeer(validator, false) || !h.isPriorityPeer(validator, true) {
+		t.Error("unexpected priority without registry")
+	}
+}
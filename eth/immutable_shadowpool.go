@@ -0,0 +1,224 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// shadowTxLifetime is the maximum time a forwarded transaction is kept if it
+// does not get mined, e.g. because the upstream dropped it.
+const shadowTxLifetime = 10 * time.Minute
+
+const (
+	// shadowSenderSlots is the maximum number of forwarded transactions tracked
+	// per sender.
+	shadowSenderSlots = 64
+
+	// shadowGlobalSlots is the maximum number of forwarded transactions tracked
+	// in total. Once reached, the oldest transactions make room for new ones.
+	shadowGlobalSlots = 4096
+)
+
+// errShadowSenderFull is returned if a sender already has the maximum number
+// of forwarded transactions tracked.
+var errShadowSenderFull = errors.New("too many forwarded transactions from sender")
+
+var shadowPoolGauge = metrics.NewRegisteredGauge("txpool/shadow", nil)
+
+type shadowTx struct {
+	tx    *types.Transaction
+	from  common.Address
+	added time.Time
+}
+
+// shadowPool keeps the transactions an RPC proxy forwarded upstream, so that the
+// node serves them and accounts for their nonces before the block including
+// them arrives. Transactions are dropped once the sender's nonce at the head
+// moves past them or once they expire.
+type shadowPool struct {
+	chain  *core.BlockChain
+	signer types.Signer
+
+	lock    sync.RWMutex
+	txs     map[common.Hash]*shadowTx
+	senders map[common.Address]map[uint64]common.Hash
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newShadowPool creates a shadow pool pruned on every new chain head.
+func newShadowPool(chain *core.BlockChain) *shadowPool {
+	p := &shadowPool{
+		chain:   chain,
+		signer:  types.LatestSigner(chain.Config()),
+		txs:     make(map[common.Hash]*shadowTx),
+		senders: make(map[common.Address]map[uint64]common.Hash),
+		quit:    make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return p
+}
+
+func (p *shadowPool) loop() {
+	defer p.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 16)
+	headSub := p.chain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			statedb, err := p.chain.StateAt(ev.Block.Root())
+			if err != nil {
+				log.Debug("Failed to prune shadow pool", "number", ev.Block.Number(), "err", err)
+				continue
+			}
+			p.prune(statedb, time.Now())
+		case <-headSub.Err():
+			return
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// add tracks a transaction forwarded upstream. A transaction with the same
+// sender and nonce replaces the previous one. New nonces beyond the sender's
+// slots are not tracked, and the oldest transactions are evicted once the pool
+// is full.
+func (p *shadowPool) add(tx *types.Transaction) error {
+	if p == nil {
+		return nil
+	}
+	from, err := types.Sender(p.signer, tx)
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	nonces := p.senders[from]
+	if prev, ok := nonces[tx.Nonce()]; ok {
+		delete(p.txs, prev)
+	} else if len(nonces) >= shadowSenderSlots {
+		return errShadowSenderFull
+	} else if len(p.txs) >= shadowGlobalSlots {
+		p.evictOldest()
+	}
+	// Eviction may have dropped the sender's last transaction
+	if nonces = p.senders[from]; nonces == nil {
+		nonces = make(map[uint64]common.Hash)
+		p.senders[from] = nonces
+	}
+	nonces[tx.Nonce()] = tx.Hash()
+	p.txs[tx.Hash()] = &shadowTx{tx: tx, from: from, added: time.Now()}
+	shadowPoolGauge.Update(int64(len(p.txs)))
+	return nil
+}
+
+// evictOldest drops the transaction tracked for the longest time. The caller
+// must hold the lock.
+func (p *shadowPool) evictOldest() {
+	var oldest *shadowTx
+	for _, stx := range p.txs {
+		if oldest == nil || stx.added.Before(oldest.added) {
+			oldest = stx
+		}
+	}
+	if oldest != nil {
+		p.drop(oldest)
+	}
+}
+
+// drop untracks a transaction. The caller must hold the lock.
+func (p *shadowPool) drop(stx *shadowTx) {
+	delete(p.txs, stx.tx.Hash())
+	nonces := p.senders[stx.from]
+	delete(nonces, stx.tx.Nonce())
+	if len(nonces) == 0 {
+		delete(p.senders, stx.from)
+	}
+}
+
+// get returns a tracked transaction.
+func (p *shadowPool) get(hash common.Hash) *types.Transaction {
+	if p == nil {
+		return nil
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if stx, ok := p.txs[hash]; ok {
+		return stx.tx
+	}
+	return nil
+}
+
+// pendingNonce returns the next nonce of the sender, extending the given
+// nonce over the tracked transactions with consecutive nonces.
+func (p *shadowPool) pendingNonce(addr common.Address, nonce uint64) uint64 {
+	if p == nil {
+		return nonce
+	}
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	nonces := p.senders[addr]
+	for {
+		if _, ok := nonces[nonce]; !ok {
+			return nonce
+		}
+		nonce++
+	}
+}
+
+// prune drops the transactions whose nonce was used at the given state, and
+// those older than the shadow transaction lifetime.
+func (p *shadowPool) prune(statedb *state.StateDB, now time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, stx := range p.txs {
+		if statedb.GetNonce(stx.from) <= stx.tx.Nonce() && now.Sub(stx.added) < shadowTxLifetime {
+			continue
+		}
+		p.drop(stx)
+	}
+	shadowPoolGauge.Update(int64(len(p.txs)))
+}
+
+// stop terminates the pruning loop.
+func (p *shadowPool) stop() {
+	if p == nil {
+		return
+	}
+	close(p.quit)
+	p.wg.Wait()
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestImmutableShadowPool(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		pool   = &shadowPool{
+			signer:  signer,
+			txs:     make(map[common.Hash]*shadowTx),
+			senders: make(map[common.Address]map[uint64]common.Hash),
+		}
+	)
+	sign := func(nonce uint64, gasPrice int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(gasPrice), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	txs := []*types.Transaction{sign(0, 1), sign(1, 1), sign(3, 1)}
+	for _, tx := range txs {
+		if err := pool.add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Forwarded transactions are served and their consecutive nonces counted
+	for _, tx := range txs {
+		if pool.get(tx.Hash()) == nil {
+			t.Fatalf("transaction %x not tracked", tx.Hash())
+		}
+	}
+	if nonce := pool.pendingNonce(from, 0); nonce != 2 {
+		t.Fatalf("expected pending nonce 2, got %d", nonce)
+	}
+	// A replacement evicts the transaction with the same nonce
+	replacement := sign(1, 2)
+	if err := pool.add(replacement); err != nil {
+		t.Fatal(err)
+	}
+	if pool.get(txs[1].Hash()) != nil || pool.get(replacement.Hash()) == nil {
+		t.Fatal("replacement not tracked")
+	}
+	// Transactions whose nonce was used are dropped
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetNonce(from, 2)
+	pool.prune(statedb, time.Now())
+	if pool.get(txs[0].Hash()) != nil || pool.get(replacement.Hash()) != nil {
+		t.Fatal("mined transactions not pruned")
+	}
+	if nonce := pool.pendingNonce(from, 2); nonce != 2 {
+		t.Fatalf("expected pending nonce 2, got %d", nonce)
+	}
+	if nonce := pool.pendingNonce(from, 3); nonce != 4 {
+		t.Fatalf("expected pending nonce 4, got %d", nonce)
+	}
+	// Expired transactions are dropped
+	pool.prune(statedb, time.Now().Add(shadowTxLifetime))
+	if len(pool.txs) != 0 || len(pool.senders) != 0 {
+		t.Fatalf("expired transactions not pruned: %d left", len(pool.txs))
+	}
+	// A nil pool, i.e. no RPC proxy, is a no-op
+	var disabled *shadowPool
+	if disabled.get(txs[0].Hash()) != nil || disabled.pendingNonce(from, 5) != 5 || disabled.add(txs[0]) != nil {
+		t.Fatal("nil shadow pool not a no-op")
+	}
+}
+
+func TestImmutableShadowPoolLimits(t *testing.T) {
+	var (
+		signer = types.LatestSigner(params.TestChainConfig)
+		pool   = &shadowPool{
+			signer:  signer,
+			txs:     make(map[common.Hash]*shadowTx),
+			senders: make(map[common.Address]map[uint64]common.Hash),
+		}
+		keys = make([]*ecdsa.PrivateKey, shadowGlobalSlots/shadowSenderSlots+1)
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	sign := func(key *ecdsa.PrivateKey, nonce uint64, gasPrice int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), 21000, big.NewInt(gasPrice), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	// Fill the pool, every sender using all its slots
+	var first *types.Transaction
+	for _, key := range keys[:len(keys)-1] {
+		for nonce := uint64(0); nonce < shadowSenderSlots; nonce++ {
+			tx := sign(key, nonce, 1)
+			if err := pool.add(tx); err != nil {
+				t.Fatal(err)
+			}
+			if first == nil {
+				first = tx
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+	// New nonces beyond the sender's slots are refused, replacements aren't
+	if err := pool.add(sign(keys[0], shadowSenderSlots, 1)); err != errShadowSenderFull {
+		t.Fatalf("expected %v, got %v", errShadowSenderFull, err)
+	}
+	if err := pool.add(sign(keys[1], 1, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.txs) != shadowGlobalSlots {
+		t.Fatalf("expected %d tracked transactions, got %d", shadowGlobalSlots, len(pool.txs))
+	}
+	// A full pool evicts the oldest transaction to make room
+	tx := sign(keys[len(keys)-1], 0, 1)
+	if err := pool.add(tx); err != nil {
+		t.Fatal(err)
+	}
+	if len(pool.txs) != shadowGlobalSlots || pool.get(tx.Hash()) == nil || pool.get(first.Hash()) != nil {
+		t.Fatal("oldest transaction not evicted")
+	}
+	if nonce := pool.pendingNonce(crypto.PubkeyToAddress(keys[0].PublicKey), 0); nonce != 0 {
+		t.Fatalf("expected pending nonce 0 after eviction, got %d", nonce)
+	}
+}
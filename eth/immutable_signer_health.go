@@ -0,0 +1,110 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// signerHealthInterval is the number of blocks between metric updates.
+const signerHealthInterval = 64
+
+// signerHealthExporter periodically recomputes the clique signer health over
+// the most recent blocks and publishes it as metrics.
+type signerHealthExporter struct {
+	engine  *clique.Clique
+	chain   *core.BlockChain
+	window  uint64 // Number of blocks the metrics are computed over
+	metrics *clique.SignerHealthMetrics
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newSignerHealthExporter starts an exporter for the chain computing the health
+// over the given number of blocks, or returns nil if metrics are disabled or
+// the engine is not clique.
+func newSignerHealthExporter(engine consensus.Engine, chain *core.BlockChain, window uint64) *signerHealthExporter {
+	if !metrics.Enabled {
+		return nil
+	}
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	c, ok := engine.(*clique.Clique)
+	if !ok {
+		return nil
+	}
+	e := &signerHealthExporter{
+		engine:  c,
+		chain:   chain,
+		window:  window,
+		metrics: clique.NewSignerHealthMetrics(),
+		quit:    make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.loop()
+	return e
+}
+
+func (e *signerHealthExporter) loop() {
+	defer e.wg.Done()
+	defer e.metrics.Stop()
+
+	headCh := make(chan core.ChainHeadEvent, 16)
+	sub := e.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			head := ev.Block.NumberU64()
+			if head == 0 || head%signerHealthInterval != 0 {
+				continue
+			}
+			from := uint64(1)
+			if head > e.window {
+				from = head - e.window + 1
+			}
+			report, err := e.engine.SignerHealth(e.chain, from, head)
+			if err != nil {
+				log.Debug("Failed to compute signer health", "head", head, "err", err)
+				continue
+			}
+			e.metrics.Update(report)
+		case <-sub.Err():
+			return
+		case <-e.quit:
+			return
+		}
+	}
+}
+
+// stop terminates the exporter and waits for it to exit.
+func (e *signerHealthExporter) stop() {
+	if e == nil {
+		return
+	}
+	close(e.quit)
+	e.wg.Wait()
+}
@@ -17,6 +17,8 @@
 package eth
 
 import (
+	"sync/atomic"
+
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 )
@@ -25,19 +27,30 @@ import (
 // about a connected peer.
 type ethPeerInfo struct {
 	Version uint `json:"version"` // Ethereum protocol version negotiated
+	// CHANGE(immutable): role the transaction gossip policy was picked by
+	Role string `json:"role,omitempty"`
 }
 
 // ethPeer is a wrapper around eth.Peer to maintain a few extra metadata.
 type ethPeer struct {
 	*eth.Peer
 	snapExt *snapPeer // Satellite `snap` connection
+	// CHANGE(immutable): transaction gossip policy of the peer's role
+	gossip atomic.Pointer[gossipPolicy]
+	// CHANGE(immutable): whether the peer is trusted or static
+	pinned atomic.Bool
 }
 
 // info gathers and returns some `eth` protocol metadata known about a peer.
 func (p *ethPeer) info() *ethPeerInfo {
-	return &ethPeerInfo{
+	// CHANGE(immutable): report the gossip role
+	info := &ethPeerInfo{
 		Version: p.Version(),
 	}
+	if policy := p.gossip.Load(); policy != nil {
+		info.Role = policy.role
+	}
+	return info
 }
 
 // snapPeerInfo represents a short summary of the `snap` sub-protocol metadata known
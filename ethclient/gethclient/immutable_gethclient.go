@@ -46,3 +46,26 @@ func (ec *Client) Propose(ctx context.Context, validator common.Address, auth bo
 	)
 	return err
 }
+
+// Discard drops a currently running proposal, stopping the signer from casting
+// further votes (either for or against) the given address.
+func (ec *Client) Discard(ctx context.Context, validator common.Address) error {
+	var result interface{}
+	return ec.c.CallContext(ctx, &result, "clique_discard", validator.String())
+}
+
+// Etherbase returns the etherbase of the node, which is the signing address of
+// a clique validator.
+func (ec *Client) Etherbase(ctx context.Context) (common.Address, error) {
+	var etherbase common.Address
+	err := ec.c.CallContext(ctx, &etherbase, "eth_coinbase")
+	return etherbase, err
+}
+
+// RotateSigner makes the node reload its sealing key from its secret store and
+// seal with it from then on. It returns the address of the new signer.
+func (ec *Client) RotateSigner(ctx context.Context) (common.Address, error) {
+	var signer common.Address
+	err := ec.c.CallContext(ctx, &signer, "admin_rotateSigner")
+	return signer, err
+}
@@ -49,6 +49,8 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
 	"github.com/tyler-smith/go-bip39"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // estimateGasErrorRatio is the amount of overestimation eth_estimateGas is
@@ -1111,8 +1113,14 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 	}()
 
 	// Execute the message.
+	// CHANGE(immutable): trace the EVM execution
+	_, span := tracer().Start(ctx, "evm.execute", trace.WithAttributes(attribute.Int64("gas.limit", int64(msg.GasLimit))))
 	gp := new(core.GasPool).AddGas(math.MaxUint64)
 	result, err := core.ApplyMessage(evm, msg, gp)
+	if result != nil {
+		span.SetAttributes(attribute.Int64("gas.used", int64(result.UsedGas)))
+	}
+	endSpan(span, err)
 	if err := state.Error(); err != nil {
 		return nil, err
 	}
@@ -1130,7 +1138,8 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	// CHANGE(immutable): trace the state lookup
+	state, header, err := tracedStateAndHeader(ctx, b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -1166,7 +1175,8 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 // non-zero) and `gasCap` (if non-zero).
 func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, gasCap uint64) (hexutil.Uint64, error) {
 	// Retrieve the base state and mutate it with any overrides
-	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	// CHANGE(immutable): trace the state lookup
+	state, header, err := tracedStateAndHeader(ctx, b, blockNrOrHash)
 	if state == nil || err != nil {
 		return 0, err
 	}
@@ -31,6 +31,8 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	// CHANGE(immutable): Add the immutable namespace.
+	"immutable": ImmutableJs,
 }
 
 const CliqueJs = `
@@ -80,6 +82,17 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getEquivocations',
+			call: 'clique_getEquivocations',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getSignerHealth',
+			call: 'clique_getSignerHealth',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -158,6 +171,23 @@ web3._extend({
 			call: 'admin_sleepBlocks',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'reloadAccessControl',
+			call: 'admin_reloadAccessControl',
+		}),
+		new web3._extend.Method({
+			name: 'fetcherCatchUp',
+			call: 'admin_fetcherCatchUp',
+		}),
+		new web3._extend.Method({
+			name: 'setFetcherCatchUp',
+			call: 'admin_setFetcherCatchUp',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'rotateSigner',
+			call: 'admin_rotateSigner',
+		}),
 		new web3._extend.Method({
 			name: 'startHTTP',
 			call: 'admin_startHTTP',
@@ -448,6 +478,16 @@ web3._extend({
 			call: 'debug_getBadBlocks',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'listQuarantinedForks',
+			call: 'debug_listQuarantinedForks',
+			params: 0,
+		}),
+		new web3._extend.Method({
+			name: 'getQuarantinedFork',
+			call: 'debug_getQuarantinedFork',
+			params: 1,
+		}),
 		new web3._extend.Method({
 			name: 'storageRangeAt',
 			call: 'debug_storageRangeAt',
@@ -611,6 +651,11 @@ web3._extend({
 			call: 'eth_getLogs',
 			params: 1,
 		}),
+		new web3._extend.Method({
+			name: 'getLogsPaged',
+			call: 'eth_getLogsPaged',
+			params: 3,
+		}),
 		new web3._extend.Method({
 			name: 'call',
 			call: 'eth_call',
@@ -804,6 +849,15 @@ web3._extend({
 			call: 'txpool_contentFrom',
 			params: 1,
 		}),
+		new web3._extend.Property({
+			name: 'accessControlStatus',
+			getter: 'txpool_accessControlStatus'
+		}),
+		new web3._extend.Method({
+			name: 'accessControlRejections',
+			call: 'txpool_accessControlRejections',
+			params: 1
+		}),
 	]
 });
 `
@@ -911,3 +965,19 @@ web3._extend({
 	],
 });
 `
+
+// CHANGE(immutable): Console methods of the immutable namespace.
+const ImmutableJs = `
+web3._extend({
+	property: 'immutable',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'feeEstimate',
+			call: 'immutable_feeEstimate',
+			params: 1,
+			inputFormatter: [null]
+		}),
+	],
+});
+`
@@ -0,0 +1,38 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BuildBlock assembles a block on top of the given parent, filled with the
+// pending transactions, and returns it without sealing it. Callers are
+// expected to seal the block themselves, which lets tests produce blocks
+// deterministically rather than waiting on the sealing loop.
+func (miner *Miner) BuildBlock(parent common.Hash, timestamp uint64, coinbase common.Address) (*types.Block, error) {
+	r := miner.worker.getSealingBlock(&generateParams{
+		timestamp:  timestamp,
+		parentHash: parent,
+		coinbase:   coinbase,
+	})
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.block, nil
+}
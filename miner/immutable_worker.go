@@ -0,0 +1,63 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/immutable/accesscontrol"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// accessControlSkipMeter counts the transactions left out of sealed blocks
+// because their execution reached a blocked account.
+var accessControlSkipMeter = metrics.NewRegisteredMeter("miner/accesscontrol/skipped", nil)
+
+// applyTransactionWithAccessControl applies the transaction like applyTransaction,
+// tracing every account it reaches. If any of them is forbidden by the txpool's
+// access controllers, all state changes are reverted and the access violation
+// is returned, causing the sender's remaining transactions to be skipped.
+func (w *worker) applyTransactionWithAccessControl(env *environment, tx *types.Transaction) (*types.Receipt, error) {
+	var (
+		snap    = env.state.Snapshot()
+		gp      = env.gasPool.Gas()
+		gasUsed = env.header.GasUsed
+	)
+	from, _ := types.Sender(env.signer, tx)
+	tracer := accesscontrol.NewTargetTracer(func(target txpool.CallTarget) error {
+		return w.eth.TxPool().CheckCallTarget(from, target)
+	})
+	vmConfig := *w.chain.GetVMConfig()
+	vmConfig.Tracer = tracer
+
+	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, vmConfig)
+	if err != nil {
+		// The tracer vetoes access violations before the state is finalised,
+		// so the snapshot can still be reverted to.
+		env.state.RevertToSnapshot(snap)
+		env.gasPool.SetGas(gp)
+		env.header.GasUsed = gasUsed
+		if tracer.Err() != nil {
+			accessControlSkipMeter.Mark(1)
+			log.Warn("Skipping transaction reaching blocked account", "hash", tx.Hash(), "from", from, "err", err)
+		}
+		return nil, err
+	}
+	return receipt, nil
+}
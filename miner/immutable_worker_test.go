@@ -0,0 +1,112 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestImmutableWorker_ExecutionAccessControl checks that the worker leaves out
+// of sealed blocks the pending transactions whose execution reaches a blocked
+// account, while still including the others.
+func TestImmutableWorker_ExecutionAccessControl(t *testing.T) {
+	var (
+		blocked   = common.HexToAddress("0xb10c")
+		forwarder = common.HexToAddress("0xf0")
+		// CALL(gas, blocked, 0, 0, 0, 0, 0); STOP
+		forwarderCode = append(append([]byte{0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x73}, blocked.Bytes()...), 0x5a, 0xf1, 0x00)
+	)
+	blocklist := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(blocklist, []byte(blocked.Hex()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	gspec := &core.Genesis{
+		Config: ethashChainConfig,
+		Alloc: types.GenesisAlloc{
+			testBankAddress: {Balance: testBankFunds},
+			testUserAddress: {Balance: testBankFunds},
+			forwarder:       {Code: forwarderCode},
+		},
+	}
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), &core.CacheConfig{TrieDirtyDisabled: true}, gspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("core.NewBlockChain failed: %v", err)
+	}
+	defer chain.Stop()
+
+	// The pool only screens senders and recipients, so the transaction calling
+	// the blocked account through the forwarder is admitted and must be caught
+	// by the worker.
+	poolConfig := testTxPoolConfig
+	poolConfig.BlockListFilePaths = []string{blocklist}
+	pool, err := txpool.New(poolConfig.PriceLimit, chain, []txpool.SubPool{legacypool.New(poolConfig, chain)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	var (
+		signer   = types.LatestSigner(ethashChainConfig)
+		gasPrice = big.NewInt(10 * params.InitialBaseFee)
+		denied   = types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{Nonce: 0, To: &forwarder, Gas: 100000, GasPrice: gasPrice})
+		allowed  = types.MustSignNewTx(testUserKey, signer, &types.LegacyTx{Nonce: 0, To: &testBankAddress, Value: big.NewInt(1000), Gas: params.TxGas, GasPrice: gasPrice})
+	)
+	for i, err := range pool.Add([]*types.Transaction{denied, allowed}, true, true) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+
+	config := *testConfig
+	config.ExecutionAccessControl = true
+	backend := &testWorkerBackend{chain: chain, txPool: pool, genesis: gspec}
+	w := newWorker(&config, ethashChainConfig, engine, backend, new(event.TypeMux), nil, false)
+	defer w.close()
+
+	r := w.getSealingBlock(&generateParams{
+		parentHash: chain.CurrentBlock().Hash(),
+		timestamp:  chain.CurrentBlock().Time + 1,
+		coinbase:   testBankAddress,
+	})
+	if r.err != nil {
+		t.Fatalf("failed to build block: %v", r.err)
+	}
+	txs := r.block.Transactions()
+	if len(txs) != 1 || txs[0].Hash() != allowed.Hash() {
+		t.Fatalf("block transactions mismatch: have %d, want only %x", len(txs), allowed.Hash())
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Errorf("pending transactions mismatch: have %d, want 2", pending)
+	}
+}
@@ -53,6 +53,9 @@ type Config struct {
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+
+	// CHANGE(immutable): Trace sealed transactions and skip the ones reaching accounts blocked by the txpool access controllers
+	ExecutionAccessControl bool `toml:",omitempty"`
 }
 
 // DefaultConfig contains default settings for miner.
@@ -90,11 +90,6 @@ var (
 	prepareWorkTimer       = metrics.NewRegisteredTimer("worker/block/preparework", nil)
 	taskLoopTimer          = metrics.NewRegisteredTimer("worker/block/taskloop", nil)
 	resultLoopTimer        = metrics.NewRegisteredTimer("worker/block/resultloop", nil)
-
-	// CHANGE(immutable): define start time for block construction as process is across multiple functions
-	blockConstructionStartTime time.Time
-	taskLoopStartTime          time.Time
-	blockConstructionTrigger   bool
 )
 
 // environment is the worker's current environment and holds all
@@ -238,6 +233,12 @@ type worker struct {
 	newTxs  atomic.Int32 // New arrival transaction count since last sealing work submitting.
 	syncing atomic.Bool  // The indicator whether the node is still syncing.
 
+	// CHANGE(immutable): start times of block construction and of the task loop, as
+	// the processes span multiple goroutines. They are kept per worker so that several
+	// nodes can run in one process.
+	blockConstructionStart atomic.Pointer[time.Time]
+	taskLoopStart          atomic.Pointer[time.Time]
+
 	// newpayloadTimeout is the maximum timeout allowance for creating payload.
 	// The default value is 2 seconds but node operator can set it to arbitrary
 	// large value. A large timeout allowance may cause Geth to fail creating
@@ -482,8 +483,8 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 
 		case head := <-w.chainHeadCh:
 			// CHANGE(immutable): capture start time for block construction
-			blockConstructionStartTime = time.Now()
-			blockConstructionTrigger = true
+			now := time.Now()
+			w.blockConstructionStart.Store(&now)
 			clearPending(head.Block.NumberU64())
 			timestamp = time.Now().Unix()
 			commit(commitInterruptNewHead)
@@ -638,7 +639,8 @@ func (w *worker) taskLoop() {
 		select {
 		case task := <-w.taskCh:
 			// CHANGE(immutable): capture time we receive a new task
-			taskLoopStartTime = time.Now()
+			now := time.Now()
+			w.taskLoopStart.Store(&now)
 			if w.newTaskHook != nil {
 				w.newTaskHook(task)
 			}
@@ -681,7 +683,9 @@ func (w *worker) resultLoop() {
 		select {
 		case block := <-w.resultCh:
 			// CHANGE(immutable): capture taskloop processing time
-			taskLoopTimer.UpdateSince(taskLoopStartTime)
+			if start := w.taskLoopStart.Load(); start != nil {
+				taskLoopTimer.UpdateSince(*start)
+			}
 			// CHANGE(immutable): capture result loop start time
 			start := time.Now()
 
@@ -743,9 +747,8 @@ func (w *worker) resultLoop() {
 			w.mux.Post(core.NewMinedBlockEvent{Block: block})
 
 			// CHANGE(immutable): capture block construction time
-			if blockConstructionTrigger {
-				blockConstructionTimer.UpdateSince(blockConstructionStartTime)
-				blockConstructionTrigger = false
+			if start := w.blockConstructionStart.Swap(nil); start != nil {
+				blockConstructionTimer.UpdateSince(*start)
 			}
 
 			// CHANGE(immutable): update tx count in block based on receipts
@@ -854,6 +857,10 @@ func (w *worker) applyTransaction(env *environment, tx *types.Transaction) (*typ
 		snap = env.state.Snapshot()
 		gp   = env.gasPool.Gas()
 	)
+	// CHANGE(immutable): Trace the accounts reached by the transaction if execution access control is enabled
+	if w.config.ExecutionAccessControl {
+		return w.applyTransactionWithAccessControl(env, tx)
+	}
 	receipt, err := core.ApplyTransaction(w.chainConfig, w.chain, &env.coinbase, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, *w.chain.GetVMConfig())
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
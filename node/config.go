@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -220,6 +221,18 @@ type Config struct {
 	DisableMiner    bool `toml:"-"`
 	DisablePersonal bool `toml:"-"`
 
+	// CHANGE(immutable): Tracing configures the export of OpenTelemetry traces of the RPC stack.
+	Tracing TracingConfig `toml:",omitempty"`
+
+	// CHANGE(immutable): RPCForwarding routes the calls received over public HTTP and
+	// WebSocket endpoints either to local handling or to named upstream RPCs.
+	RPCForwarding rpc.ForwardingConfig `toml:",omitempty"`
+
+	// CHANGE(immutable): MemoryDatabase, if set, supplies the database opened under
+	// a name while DataDir is empty instead of a new in-memory one. It lets the
+	// databases of ephemeral nodes outlive them, e.g. to restart nodes in tests.
+	MemoryDatabase func(name string) ethdb.Database `toml:"-"`
+
 	DBEngine string `toml:",omitempty"`
 }
 
@@ -0,0 +1,152 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// tracingShutdownTimeout bounds the flush of pending spans on shutdown.
+const tracingShutdownTimeout = 5 * time.Second
+
+// TracingConfig configures the export of OpenTelemetry traces of the RPC stack.
+// Tracing is enabled if an endpoint or a file is set.
+type TracingConfig struct {
+	// Endpoint is the OTLP/HTTP collector to export spans to, either as
+	// host:port (TLS) or as a URL, e.g. http://localhost:4318/v1/traces.
+	Endpoint string `toml:",omitempty"`
+
+	// File is a file spans are appended to as JSON, one span per line.
+	File string `toml:",omitempty"`
+
+	// SampleRatio is the fraction of traces sampled when the caller did not
+	// make a sampling decision through the traceparent header. Defaults to 1.
+	SampleRatio float64 `toml:",omitempty"`
+}
+
+func (c *TracingConfig) enabled() bool {
+	return c.Endpoint != "" || c.File != ""
+}
+
+// tracing holds the tracer provider installed as the global OpenTelemetry
+// provider for the lifetime of the node.
+type tracing struct {
+	provider *sdktrace.TracerProvider
+	file     *os.File
+}
+
+// newTracing creates the span exporters and installs the tracer provider and
+// the W3C trace context propagator globally.
+func newTracing(conf TracingConfig, service string) (*tracing, error) {
+	var (
+		t    = new(tracing)
+		opts []sdktrace.TracerProviderOption
+	)
+	if conf.Endpoint != "" {
+		exporter, err := newOTLPExporter(conf.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if conf.File != "" {
+		file, err := os.OpenFile(conf.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open tracing file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		t.file = file
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	ratio := conf.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	opts = append(opts,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+	)
+	t.provider = sdktrace.NewTracerProvider(opts...)
+
+	otel.SetTracerProvider(t.provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return t, nil
+}
+
+// newOTLPExporter creates an OTLP/HTTP span exporter for the endpoint.
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	var opts []otlptracehttp.Option
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tracing endpoint: %w", err)
+		}
+		switch u.Scheme {
+		case "http":
+			opts = append(opts, otlptracehttp.WithInsecure())
+		case "https":
+		default:
+			return nil, fmt.Errorf("invalid tracing endpoint scheme %q", u.Scheme)
+		}
+		if u.Path != "" && u.Path != "/" {
+			opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+		}
+		endpoint = u.Host
+	}
+	opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// close flushes pending spans and shuts the exporters down.
+func (t *tracing) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+
+	err := t.provider.Shutdown(ctx)
+	if t.file != nil {
+		err = errors.Join(err, t.file.Close())
+	}
+	return err
+}
+
+// newTracingMiddleware extracts the W3C trace context of the request into the
+// request context, so the spans of the served calls join the caller's trace.
+func newTracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
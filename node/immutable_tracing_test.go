@@ -0,0 +1,148 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+}
+
+func TestImmutableTracing_BatchSpans(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	file := filepath.Join(t.TempDir(), "traces.json")
+	node, err := New(&Config{
+		HTTPHost:     "127.0.0.1",
+		HTTPTimeouts: rpc.DefaultHTTPTimeouts,
+		Tracing:      TracingConfig{File: file},
+	})
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs(apis())
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	resp := batchRpcRequest(t, node.HTTPEndpoint(), []string{"test_greet", "test_unknown"},
+		"traceparent", "00-"+traceID+"-"+parentID+"-01")
+	io.Copy(io.Discard, resp.Body)
+	// Closing the node flushes the spans
+	if err := node.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := readSpans(t, file)
+	batch, ok := spans["jsonrpc.batch"]
+	if !ok {
+		t.Fatalf("batch span not exported, got %v", spans)
+	}
+	if batch.SpanContext.TraceID != traceID || batch.Parent.SpanID != parentID {
+		t.Fatalf("batch span not part of the caller's trace: %+v", batch)
+	}
+	for _, method := range []string{"test_greet", "test_unknown"} {
+		call, ok := spans[method]
+		if !ok {
+			t.Fatalf("span for %s not exported", method)
+		}
+		if call.SpanContext.TraceID != traceID || call.Parent.SpanID != batch.SpanContext.SpanID {
+			t.Fatalf("span for %s is not a child of the batch span: %+v", method, call)
+		}
+	}
+}
+
+func TestImmutableTracing_WebSocket(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	file := filepath.Join(t.TempDir(), "traces.json")
+	node, err := New(&Config{
+		WSHost:  "127.0.0.1",
+		Tracing: TracingConfig{File: file},
+	})
+	if err != nil {
+		t.Fatalf("could not create a new node: %v", err)
+	}
+	node.RegisterAPIs(apis())
+	if err := node.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	client, err := rpc.DialOptions(context.Background(), node.WSEndpoint(),
+		rpc.WithHeader("traceparent", "00-"+traceID+"-"+parentID+"-01"))
+	if err != nil {
+		t.Fatalf("could not dial websocket endpoint: %v", err)
+	}
+	var greeting string
+	if err := client.Call(&greeting, "test_greet"); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+	// Closing the node flushes the spans
+	if err := node.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	call, ok := readSpans(t, file)["test_greet"]
+	if !ok {
+		t.Fatal("span for test_greet not exported")
+	}
+	if call.SpanContext.TraceID != traceID || call.Parent.SpanID != parentID {
+		t.Fatalf("span for test_greet not part of the caller's trace: %+v", call)
+	}
+}
+
+// readSpans decodes the spans exported to the file, by name.
+func readSpans(t *testing.T, file string) map[string]exportedSpan {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var (
+		spans   = make(map[string]exportedSpan)
+		scanner = bufio.NewScanner(f)
+	)
+	for scanner.Scan() {
+		var span exportedSpan
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatal(err)
+		}
+		spans[span.Name] = span
+	}
+	return spans
+}
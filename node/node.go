@@ -17,6 +17,7 @@
 package node
 
 import (
+	"context"
 	crand "crypto/rand"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -56,6 +58,8 @@ type Node struct {
 	startStopLock sync.Mutex    // Start/Stop are protected by an additional lock
 	state         int           // Tracks state of node lifecycle
 	NewRelic      *newrelic.Application
+	tracing       *tracing    // CHANGE(immutable): OpenTelemetry tracing of the RPC stack
+	router        *rpc.Router // CHANGE(immutable): forwarding of public RPC calls upstream
 	lock          sync.Mutex
 	lifecycles    []Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
 	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
@@ -181,6 +185,31 @@ func New(conf *Config) (*Node, error) {
 	node.wsAuth = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
 	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
 
+	// CHANGE(immutable): export OpenTelemetry traces of the RPC stack
+	if conf.Tracing.enabled() {
+		if node.tracing, err = newTracing(conf.Tracing, conf.Name); err != nil {
+			node.closeDataDir()
+			return nil, err
+		}
+		node.log.Info("Enabled RPC tracing", "endpoint", conf.Tracing.Endpoint, "file", conf.Tracing.File)
+	}
+	// CHANGE(immutable): forward public RPC calls according to the routing table
+	if len(conf.RPCForwarding.Routes) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if node.router, err = rpc.NewRouter(ctx, conf.RPCForwarding); err != nil {
+			if node.tracing != nil {
+				node.tracing.close()
+			}
+			node.closeDataDir()
+			return nil, err
+		}
+		node.http.router = node.router
+		node.ws.router = node.router
+		for _, route := range conf.RPCForwarding.Routes {
+			node.log.Info("Forwarding RPC route", "match", route.Match, "blocktag", route.BlockTag, "upstream", route.Upstream)
+		}
+	}
 	return node, nil
 }
 
@@ -279,6 +308,16 @@ func (n *Node) doClose(errs []error) error {
 			errs = append(errs, err)
 		}
 	}
+	// CHANGE(immutable): disconnect from the forwarding upstreams
+	if n.router != nil {
+		n.router.Close()
+	}
+	// CHANGE(immutable): flush pending RPC traces
+	if n.tracing != nil {
+		if err := n.tracing.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
 	// Release instance directory lock.
 	n.closeDataDir()
@@ -784,7 +823,8 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 	var db ethdb.Database
 	var err error
 	if n.config.DataDir == "" {
-		db = rawdb.NewMemoryDatabase()
+		// CHANGE(immutable): Use the supplied in-memory database
+		db = n.memoryDatabase(name)
 	} else {
 		db, err = rawdb.Open(rawdb.OpenOptions{
 			Type:      n.config.DBEngine,
@@ -816,7 +856,8 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	var db ethdb.Database
 	var err error
 	if n.config.DataDir == "" {
-		db = rawdb.NewMemoryDatabase()
+		// CHANGE(immutable): Use the supplied in-memory database
+		db = n.memoryDatabase(name)
 	} else {
 		db, err = rawdb.Open(rawdb.OpenOptions{
 			Type:              n.config.DBEngine,
@@ -873,6 +914,15 @@ func (n *Node) wrapDatabase(db ethdb.Database) ethdb.Database {
 	return wrapper
 }
 
+// CHANGE(immutable): memoryDatabase returns the database supplied for the name
+// by the config, or a new in-memory database.
+func (n *Node) memoryDatabase(name string) ethdb.Database {
+	if n.config.MemoryDatabase != nil {
+		return n.config.MemoryDatabase(name)
+	}
+	return rawdb.NewMemoryDatabase()
+}
+
 // closeDatabases closes all open databases.
 func (n *Node) closeDatabases() (errors []error) {
 	for db := range n.databases {
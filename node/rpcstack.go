@@ -89,6 +89,9 @@ type httpServer struct {
 	port     int
 
 	handlerNames map[string]string
+
+	// CHANGE(immutable): routing table applied to the RPC servers, if any
+	router *rpc.Router
 }
 
 const (
@@ -314,10 +317,14 @@ func (h *httpServer) enableRPC(nrApp *newrelic.Application, apis []rpc.API, conf
 		return err
 	}
 	h.httpConfig = config
-	// CHANGE(immutable): wrap the RPC handler with newrelic middleware
+	// CHANGE(immutable): forward calls according to the routing table
+	if h.router != nil {
+		srv.SetRouter(h.router)
+	}
+	// CHANGE(immutable): wrap the RPC handler with newrelic and tracing middleware
 
 	rpcHandler := &rpcHandler{
-		Handler: NewHTTPHandlerStack(newRelicMiddleware(nrApp, srv), config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(newRelicMiddleware(nrApp, newTracingMiddleware(srv)), config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	}
 	h.httpHandler.Store(rpcHandler)
@@ -353,9 +360,13 @@ func (h *httpServer) enableWS(nrApp *newrelic.Application, apis []rpc.API, confi
 		return err
 	}
 	h.wsConfig = config
+	// CHANGE(immutable): forward calls according to the routing table
+	if h.router != nil {
+		srv.SetRouter(h.router)
+	}
 	// CHANGE(immutable): wrap the websocket handler with newrelic middleware
 	wsHandler := &rpcHandler{
-		Handler: NewWSHandlerStack(newRelicMiddleware(nrApp, srv.WebsocketHandler(config.Origins)), config.jwtSecret),
+		Handler: NewWSHandlerStack(newRelicMiddleware(nrApp, newTracingMiddleware(srv.WebsocketHandler(config.Origins))), config.jwtSecret),
 		server:  srv,
 	}
 	h.wsHandler.Store(wsHandler)
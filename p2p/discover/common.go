@@ -51,6 +51,9 @@ type Config struct {
 	Bootnodes       []*enode.Node // list of bootstrap nodes
 	PingInterval    time.Duration // speed of node liveness check
 	RefreshInterval time.Duration // used in bucket refresh
+	// CHANGE(immutable): restrict the table and findnode requests to nodes
+	// accepted by the filter, e.g. members of a registry.
+	Filter func(*enode.Node) bool
 
 	// The options below are useful in very specific cases, like in unit tests.
 	V5ProtocolID *[6]byte
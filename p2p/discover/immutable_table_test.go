@@ -0,0 +1,70 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestTable_filter(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		allowed = make(map[enode.ID]bool)
+	)
+	cfg := Config{
+		Filter: func(n *enode.Node) bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return allowed[n.ID()]
+		},
+	}
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+	tab, _ := newTable(newPingRecorder(), db, cfg)
+	go tab.loop()
+	defer tab.close()
+	<-tab.initDone
+
+	n1 := nodeAtDistance(tab.self().ID(), 256, net.IP{88, 77, 66, 1})
+	n2 := nodeAtDistance(tab.self().ID(), 256, net.IP{88, 77, 66, 2})
+	allowed[n1.ID()] = true
+
+	// Only accepted nodes are added
+	tab.addSeenNode(n1)
+	tab.addSeenNode(n2)
+	tab.addVerifiedNode(n2)
+	if tab.getNode(n1.ID()) == nil {
+		t.Fatal("accepted node not added")
+	}
+	if tab.getNode(n2.ID()) != nil {
+		t.Fatal("rejected node added")
+	}
+
+	// Nodes no longer accepted are evicted on revalidation
+	lock.Lock()
+	delete(allowed, n1.ID())
+	lock.Unlock()
+	tab.doRevalidate(make(chan struct{}, 1))
+	if tab.getNode(n1.ID()) != nil {
+		t.Fatal("revoked node not evicted")
+	}
+	checkIPLimitInvariant(t, tab)
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/discover/v4wire"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestUDPv4_findnodeFilter(t *testing.T) {
+	test := &udpTest{
+		t:          t,
+		pipe:       newpipe(),
+		localkey:   newkey(),
+		remotekey:  newkey(),
+		remoteaddr: &net.UDPAddr{IP: net.IP{10, 0, 1, 99}, Port: 30303},
+	}
+	member := v4wire.EncodePubkey(&test.remotekey.PublicKey).ID()
+	test.db, _ = enode.OpenDB("")
+	ln := enode.NewLocalNode(test.db, test.localkey)
+	test.udp, _ = ListenV4(test.pipe, ln, Config{
+		PrivateKey: test.localkey,
+		Log:        testlog.Logger(t, log.LvlTrace),
+		Filter:     func(n *enode.Node) bool { return n.ID() == member },
+	})
+	test.table = test.udp.tab
+	<-test.table.initDone
+	defer test.close()
+
+	// Bonded nodes accepted by the filter are answered
+	test.table.db.UpdateLastPongReceived(member, test.remoteaddr.IP, time.Now())
+	test.packetIn(nil, &v4wire.Findnode{Target: testTarget, Expiration: futureExp})
+	test.waitPacketOut(func(p *v4wire.Neighbors, to *net.UDPAddr, hash []byte) {})
+
+	// Bonded nodes rejected by the filter are not
+	other := newkey()
+	otherAddr := &net.UDPAddr{IP: net.IP{10, 0, 1, 100}, Port: 30303}
+	test.table.db.UpdateLastPongReceived(v4wire.EncodePubkey(&other.PublicKey).ID(), otherAddr.IP, time.Now())
+	test.packetInFrom(errFilteredNode, other, otherAddr, &v4wire.Findnode{Target: testTarget, Expiration: futureExp})
+}
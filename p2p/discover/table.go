@@ -360,6 +360,13 @@ func (tab *Table) doRevalidate(done chan<- struct{}) {
 	tab.mutex.Lock()
 	defer tab.mutex.Unlock()
 	b := tab.buckets[bi]
+	// CHANGE(immutable): evict nodes the filter no longer accepts, e.g. revoked
+	// registry members or nodes whose updated record changed their role
+	if !tab.accepts(last) {
+		tab.log.Debug("Removed filtered node", "b", bi, "id", last.ID(), "ip", last.IP())
+		tab.deleteInBucket(b, last)
+		return
+	}
 	if err == nil {
 		// The node responded, move it to the front.
 		last.livenessChecks++
@@ -514,6 +521,10 @@ func (tab *Table) addSeenNode(n *node) {
 	if n.ID() == tab.self().ID() {
 		return
 	}
+	// CHANGE(immutable): do not add nodes rejected by the filter
+	if !tab.accepts(n) {
+		return
+	}
 
 	tab.mutex.Lock()
 	defer tab.mutex.Unlock()
@@ -558,6 +569,10 @@ func (tab *Table) addVerifiedNode(n *node) {
 	if n.ID() == tab.self().ID() {
 		return
 	}
+	// CHANGE(immutable): do not add nodes rejected by the filter
+	if !tab.accepts(n) {
+		return
+	}
 
 	tab.mutex.Lock()
 	defer tab.mutex.Unlock()
@@ -586,6 +601,11 @@ func (tab *Table) addVerifiedNode(n *node) {
 	}
 }
 
+// CHANGE(immutable): accepts reports whether the node passes the configured filter.
+func (tab *Table) accepts(n *node) bool {
+	return tab.cfg.Filter == nil || tab.cfg.Filter(&n.Node)
+}
+
 // delete removes an entry from the node table. It is used to evacuate dead nodes.
 func (tab *Table) delete(node *node) {
 	tab.mutex.Lock()
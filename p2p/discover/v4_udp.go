@@ -45,6 +45,8 @@ var (
 	errClockWarp        = errors.New("reply deadline too far in the future")
 	errClosed           = errors.New("socket closed")
 	errLowPort          = errors.New("low port")
+	// CHANGE(immutable): findnode requests from nodes rejected by the filter
+	errFilteredNode = errors.New("filtered node")
 )
 
 const (
@@ -719,6 +721,17 @@ func (t *UDPv4) verifyFindnode(h *packetHandlerV4, from *net.UDPAddr, fromID eno
 		// findnode) to the victim.
 		return errUnknownNode
 	}
+	// CHANGE(immutable): only answer nodes accepted by the filter, so that the
+	// table is not disclosed to others
+	if t.tab.cfg.Filter != nil {
+		key, err := v4wire.DecodePubkey(crypto.S256(), fromKey)
+		if err != nil {
+			return err
+		}
+		if !t.tab.cfg.Filter(enode.NewV4(key, from.IP, 0, from.Port)) {
+			return errFilteredNode
+		}
+	}
 	return nil
 }
 
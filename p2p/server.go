@@ -125,6 +125,10 @@ type Config struct {
 	// IP networks contained in the list are considered.
 	NetRestrict *netutil.Netlist `toml:",omitempty"`
 
+	// CHANGE(immutable): Role is the role of the node in an Immutable network,
+	// advertised in the "imx-role" entry of its ENR if set.
+	Role string `toml:",omitempty"`
+
 	// NodeDatabase is the path to the database containing the previously seen
 	// live nodes in the network.
 	NodeDatabase string `toml:",omitempty"`
@@ -526,6 +530,10 @@ func (srv *Server) setupLocalNode() error {
 			srv.localnode.Set(e)
 		}
 	}
+	// CHANGE(immutable): advertise the role of the node
+	if srv.Role != "" {
+		srv.localnode.Set(enr.Role(srv.Role))
+	}
 	return nil
 }
 
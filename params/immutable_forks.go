@@ -0,0 +1,58 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// forkTimeField returns the timestamp field of the named fork, i.e. the
+// *uint64 field whose JSON name is the fork name followed by "Time".
+func (c *ChainConfig) forkTimeField(name string) (reflect.Value, error) {
+	config := reflect.ValueOf(c).Elem()
+	for i := 0; i < config.NumField(); i++ {
+		field := config.Type().Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == name+"Time" && field.Type == reflect.TypeOf((*uint64)(nil)) {
+			return config.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown timestamp based fork %q", name)
+}
+
+// ForkTime returns the activation time of the named timestamp based fork,
+// e.g. CancunTime for "cancun".
+func (c *ChainConfig) ForkTime(name string) (*uint64, error) {
+	field, err := c.forkTimeField(name)
+	if err != nil {
+		return nil, err
+	}
+	return field.Interface().(*uint64), nil
+}
+
+// SetForkTime sets the activation time of the named timestamp based fork,
+// e.g. CancunTime for "cancun".
+func (c *ChainConfig) SetForkTime(name string, time uint64) error {
+	field, err := c.forkTimeField(name)
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(&time))
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/settings"
+)
+
+func TestImmutableForkTime(t *testing.T) {
+	config := &ChainConfig{}
+	for _, name := range []string{"mainnet", "testnet", "devnet"} {
+		network, err := settings.NewNetwork(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every scheduled fork must map onto a chain config field
+		for _, fork := range network.Forks() {
+			if err := config.SetForkTime(fork.Name, uint64(fork.Time.Unix())); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if time, err := config.ForkTime(fork.Name); err != nil || time == nil || *time != uint64(fork.Time.Unix()) {
+				t.Fatalf("%s: fork %s time mismatch: %v, %v", name, fork.Name, time, err)
+			}
+		}
+	}
+	if config.CancunTime == nil || config.ShanghaiTime == nil || config.PrevrandaoTime == nil {
+		t.Fatal("expected fork times to be set")
+	}
+	if time, err := config.ForkTime("prague"); err != nil || time != nil {
+		t.Fatalf("expected unset prague time, got %v, %v", time, err)
+	}
+	for _, name := range []string{"london", "terminalTotalDifficulty", "unknown", ""} {
+		if err := config.SetForkTime(name, 1); err == nil {
+			t.Errorf("expected error for fork %q", name)
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // handler handles JSON-RPC messages. There is one handler per connection. Note that
@@ -203,6 +204,11 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		cp.ctx, cancel = context.WithCancel(cp.ctx)
 		defer cancel()
 
+		// CHANGE(immutable): trace the batch, its calls are traced as child spans
+		var batchSpan trace.Span
+		cp.ctx, batchSpan = startBatchSpan(cp.ctx, len(calls))
+		defer batchSpan.End()
+
 		// Cancel the request context after timeout and send an error response. Since the
 		// currently-running method might not return immediately on timeout, we must wait
 		// for the timeout concurrently with processing the request.
@@ -224,7 +230,8 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 			if msg == nil {
 				break
 			}
-			resp := h.handleCallMsg(cp, msg)
+			// CHANGE(immutable): trace every call as a child of the batch span
+			resp := h.handleTracedCallMsg(cp, msg)
 			callBuffer.pushResponse(resp)
 			if resp != nil && h.batchResponseMaxSize != 0 {
 				responseBytes += len(resp.Result)
@@ -293,7 +300,8 @@ func (h *handler) handleNonBatchCall(cp *callProc, msg *jsonrpcMessage) {
 		})
 	}
 
-	answer := h.handleCallMsg(cp, msg)
+	// CHANGE(immutable): trace every served call
+	answer := h.handleTracedCallMsg(cp, msg)
 	if timer != nil {
 		timer.Stop()
 	}
@@ -498,6 +506,12 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if msg.isUnsubscribe() {
 		callb = h.unsubscribeCb
 	} else {
+		// CHANGE(immutable): forward calls matching a forwarding route upstream
+		if router := h.reg.forwarder(); router != nil {
+			if answer, forwarded := router.handle(cp.ctx, msg); forwarded {
+				return answer
+			}
+		}
 		callb = h.reg.callback(msg.Method)
 	}
 	if callb == nil {
@@ -0,0 +1,510 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	defaultProxyCallTimeout      = 5 * time.Second
+	defaultProxyHealthInterval   = 10 * time.Second
+	defaultProxyHealthMethod     = "eth_blockNumber"
+	defaultProxyFailureThreshold = 3
+	defaultProxyOpenTimeout      = 30 * time.Second
+)
+
+// ErrNoProxyUpstream is returned when no upstream is available to forward a
+// call to, either because all are unhealthy or because their circuit breakers
+// are open.
+var ErrNoProxyUpstream = errors.New("no available proxy upstream")
+
+// proxyResubmissionErrors lists, for the methods that must not be applied
+// twice, the errors with which upstreams refuse a repeated call. A transport
+// failure such as a timeout doesn't tell whether the failed upstream applied
+// the call, so when a retry is refused with one of these errors the call was
+// already applied by a previous attempt.
+//
+// Only errors naming the very same call qualify: a "nonce too low" refusal of
+// a transaction may just as well come from a different transaction having
+// used the nonce, and is returned to the caller.
+var proxyResubmissionErrors = map[string][]string{
+	// txpool.ErrAlreadyKnown
+	"eth_sendRawTransaction": {"already known"},
+}
+
+// ProxyUpstream is an RPC endpoint calls are forwarded to.
+type ProxyUpstream struct {
+	Name   string // Name used in metrics, logs and forwarding rules
+	URL    string
+	Weight int // Relative share of the calls, defaults to 1
+}
+
+// ParseProxyUpstream parses an upstream given as "URL[;weight=N][;name=NAME]".
+func ParseProxyUpstream(s string) (ProxyUpstream, error) {
+	parts := strings.Split(s, ";")
+	upstream := ProxyUpstream{URL: strings.TrimSpace(parts[0]), Weight: 1}
+	if upstream.URL == "" {
+		return upstream, fmt.Errorf("missing upstream URL in %q", s)
+	}
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return upstream, fmt.Errorf("invalid upstream option %q", part)
+		}
+		switch key {
+		case "weight":
+			weight, err := strconv.Atoi(value)
+			if err != nil || weight <= 0 {
+				return upstream, fmt.Errorf("invalid upstream weight %q", value)
+			}
+			upstream.Weight = weight
+		case "name":
+			upstream.Name = value
+		default:
+			return upstream, fmt.Errorf("unknown upstream option %q", key)
+		}
+	}
+	return upstream, nil
+}
+
+// ProxyConfig configures the forwarding of calls to a pool of upstreams. Zero
+// values other than Retries are replaced by defaults.
+type ProxyConfig struct {
+	Enabled          bool // Whether transactions are forwarded to the upstreams instead of pooled locally
+	Upstreams        []ProxyUpstream
+	Retries          int           // Additional attempts on other upstreams after a transport error
+	CallTimeout      time.Duration // Timeout of a single attempt
+	HealthInterval   time.Duration // Interval between health checks of every upstream
+	HealthMethod     string        // Method called to check the health of an upstream
+	FailureThreshold int           // Consecutive failures opening the circuit breaker of an upstream
+	OpenTimeout      time.Duration // Time an open circuit breaker waits before letting a trial call through
+}
+
+func (c ProxyConfig) withDefaults() ProxyConfig {
+	if c.Retries < 0 {
+		c.Retries = 0
+	}
+	if c.CallTimeout <= 0 {
+		c.CallTimeout = defaultProxyCallTimeout
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = defaultProxyHealthInterval
+	}
+	if c.HealthMethod == "" {
+		c.HealthMethod = defaultProxyHealthMethod
+	}
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaultProxyFailureThreshold
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = defaultProxyOpenTimeout
+	}
+	return c
+}
+
+// breakerState is the state of the circuit breaker of an upstream.
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Calls flow
+	breakerOpen                         // Calls are rejected until the open timeout elapses
+	breakerHalfOpen                     // A single trial call is in flight
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerOpen:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// proxyUpstream is the runtime state of an upstream.
+type proxyUpstream struct {
+	ProxyUpstream
+
+	lock     sync.Mutex
+	client   *Client
+	healthy  bool
+	state    breakerState
+	failures int
+	openedAt time.Time
+
+	requestMeter metrics.Meter
+	failureMeter metrics.Meter
+	healthGauge  metrics.Gauge
+}
+
+// ProxyUpstreamStatus is the health of an upstream.
+type ProxyUpstreamStatus struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Weight  int    `json:"weight"`
+	Healthy bool   `json:"healthy"`
+	Breaker string `json:"breaker"`
+}
+
+// ProxyPool forwards calls to a set of upstreams. Every call goes to a healthy
+// upstream picked at random in proportion to its weight. Transport failures
+// are retried on the other upstreams, while JSON-RPC error responses are
+// returned as is. Upstreams failing repeatedly are taken out of rotation by a
+// circuit breaker until a trial call or a health check succeeds.
+type ProxyPool struct {
+	config    ProxyConfig
+	upstreams []*proxyUpstream
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProxyPool dials all upstreams and starts health checking them. Upstreams
+// which cannot be dialed are redialed on every health check; an error is only
+// returned if none can be dialed.
+func NewProxyPool(ctx context.Context, config ProxyConfig) (*ProxyPool, error) {
+	if len(config.Upstreams) == 0 {
+		return nil, errors.New("no proxy upstreams configured")
+	}
+	p := &ProxyPool{
+		config: config.withDefaults(),
+		quit:   make(chan struct{}),
+	}
+	var dialed int
+	for i, cfg := range p.config.Upstreams {
+		if cfg.Name == "" {
+			cfg.Name = fmt.Sprintf("upstream%d", i)
+		}
+		if cfg.Weight <= 0 {
+			cfg.Weight = 1
+		}
+		for _, u := range p.upstreams {
+			if u.Name == cfg.Name {
+				p.closeClients()
+				return nil, fmt.Errorf("duplicate proxy upstream name %q", cfg.Name)
+			}
+		}
+		prefix := "rpc/proxy/" + cfg.Name + "/"
+		u := &proxyUpstream{
+			ProxyUpstream: cfg,
+			requestMeter:  metrics.GetOrRegisterMeter(prefix+"requests", nil),
+			failureMeter:  metrics.GetOrRegisterMeter(prefix+"failures", nil),
+			healthGauge:   metrics.GetOrRegisterGauge(prefix+"healthy", nil),
+		}
+		client, err := DialContext(ctx, cfg.URL)
+		if err != nil {
+			log.Warn("Failed to dial proxy upstream", "name", cfg.Name, "url", cfg.URL, "err", err)
+		} else {
+			u.client, u.healthy = client, true
+			u.healthGauge.Update(1)
+			dialed++
+		}
+		p.upstreams = append(p.upstreams, u)
+	}
+	if dialed == 0 {
+		p.closeClients()
+		return nil, fmt.Errorf("failed to dial any of %d proxy upstreams", len(p.upstreams))
+	}
+	p.wg.Add(1)
+	go p.healthLoop()
+	return p, nil
+}
+
+// CallContext forwards a call to the pool, retrying transport failures on the
+// other upstreams. If a retry of a call that must not be applied twice, such
+// as eth_sendRawTransaction, is refused because a previous attempt applied it,
+// the call succeeds without decoding any result.
+func (p *ProxyPool) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return p.call(ctx, p.upstreams, result, method, args...)
+}
+
+// callUpstream forwards a call to the named upstream only.
+func (p *ProxyPool) callUpstream(ctx context.Context, name string, result interface{}, method string, args ...interface{}) error {
+	for _, u := range p.upstreams {
+		if u.Name == name {
+			return p.call(ctx, []*proxyUpstream{u}, result, method, args...)
+		}
+	}
+	return fmt.Errorf("unknown proxy upstream %q", name)
+}
+
+// hasUpstream reports whether the pool contains the named upstream.
+func (p *ProxyPool) hasUpstream(name string) bool {
+	for _, u := range p.upstreams {
+		if u.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ProxyPool) call(ctx context.Context, candidates []*proxyUpstream, result interface{}, method string, args ...interface{}) error {
+	var (
+		tried   = make(map[*proxyUpstream]bool)
+		lastErr error
+	)
+	for attempt := 0; attempt <= p.config.Retries; attempt++ {
+		u, client := p.pick(candidates, tried)
+		if u == nil {
+			break
+		}
+		tried[u] = true
+
+		callCtx, cancel := context.WithTimeout(ctx, p.config.CallTimeout)
+		err := client.CallContext(callCtx, result, method, args...)
+		cancel()
+
+		u.requestMeter.Mark(1)
+		if err == nil || !isTransportError(err) {
+			u.succeeded()
+			if lastErr != nil && isResubmissionError(method, err) {
+				log.Debug("Proxy upstream call already applied by a previous attempt", "name", u.Name, "method", method, "err", err)
+				return nil
+			}
+			return err
+		}
+		u.failureMeter.Mark(1)
+		u.failed(p.config.FailureThreshold)
+		log.Debug("Proxy upstream call failed", "name", u.Name, "method", method, "attempt", attempt, "err", err)
+
+		// Do not retry once the caller gave up
+		if ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return ErrNoProxyUpstream
+}
+
+// pick selects an untried upstream at random, in proportion to the weights of
+// the available upstreams, and returns it along with its client.
+func (p *ProxyPool) pick(candidates []*proxyUpstream, tried map[*proxyUpstream]bool) (*proxyUpstream, *Client) {
+	var (
+		available []*proxyUpstream
+		total     int
+	)
+	for _, u := range candidates {
+		if !tried[u] && u.available(p.config.OpenTimeout) {
+			available = append(available, u)
+			total += u.Weight
+		}
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+	n := rand.Intn(total)
+	for _, u := range available {
+		if n < u.Weight {
+			return u, u.acquire(p.config.OpenTimeout)
+		}
+		n -= u.Weight
+	}
+	return nil, nil
+}
+
+// available reports whether the upstream may be called.
+func (u *proxyUpstream) available(openTimeout time.Duration) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.client == nil || !u.healthy {
+		return false
+	}
+	switch u.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		return time.Since(u.openedAt) >= openTimeout
+	default:
+		return false
+	}
+}
+
+// acquire moves an open circuit breaker whose timeout elapsed to half-open,
+// letting the caller run the trial call, and returns the upstream's client.
+func (u *proxyUpstream) acquire(openTimeout time.Duration) *Client {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.state == breakerOpen && time.Since(u.openedAt) >= openTimeout {
+		u.state = breakerHalfOpen
+	}
+	return u.client
+}
+
+// succeeded closes the circuit breaker of the upstream.
+func (u *proxyUpstream) succeeded() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if u.state != breakerClosed {
+		log.Info("Proxy upstream recovered", "name", u.Name)
+	}
+	u.state, u.failures = breakerClosed, 0
+}
+
+// failed records a transport failure, opening the circuit breaker once the
+// threshold is reached or if the trial call of a half-open breaker failed.
+func (u *proxyUpstream) failed(threshold int) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	u.failures++
+	if u.state == breakerHalfOpen || u.failures >= threshold {
+		if u.state != breakerOpen {
+			log.Warn("Proxy upstream circuit breaker opened", "name", u.Name, "failures", u.failures)
+		}
+		u.state, u.openedAt = breakerOpen, time.Now()
+	}
+}
+
+// isTransportError reports whether the call failed before the upstream could
+// answer it, in which case it is safe to retry it on another upstream.
+func isTransportError(err error) bool {
+	var rpcErr Error
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// isResubmissionError reports whether the error is the refusal of a repeated
+// call to a method that must not be applied twice.
+func isResubmissionError(method string, err error) bool {
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	for _, msg := range proxyResubmissionErrors[method] {
+		if strings.HasPrefix(rpcErr.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// healthLoop periodically checks every upstream.
+func (p *ProxyPool) healthLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.HealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, u := range p.upstreams {
+				p.checkHealth(u)
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// checkHealth calls the health method of an upstream, redialing it first if
+// needed. A healthy upstream has its circuit breaker closed.
+func (p *ProxyPool) checkHealth(u *proxyUpstream) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.CallTimeout)
+	defer cancel()
+
+	u.lock.Lock()
+	client := u.client
+	u.lock.Unlock()
+
+	var err error
+	if client == nil {
+		if client, err = DialContext(ctx, u.URL); err == nil {
+			u.lock.Lock()
+			u.client = client
+			u.lock.Unlock()
+		}
+	}
+	if err == nil {
+		var result interface{}
+		err = client.CallContext(ctx, &result, p.config.HealthMethod)
+		if err != nil && !isTransportError(err) {
+			err = nil // The upstream answered
+		}
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+
+	if healthy := err == nil; healthy != u.healthy {
+		log.Info("Proxy upstream health changed", "name", u.Name, "healthy", healthy, "err", err)
+		u.healthy = healthy
+	}
+	if u.healthy {
+		u.state, u.failures = breakerClosed, 0
+		u.healthGauge.Update(1)
+	} else {
+		u.healthGauge.Update(0)
+	}
+}
+
+// Status returns the health of every upstream.
+func (p *ProxyPool) Status() []ProxyUpstreamStatus {
+	status := make([]ProxyUpstreamStatus, 0, len(p.upstreams))
+	for _, u := range p.upstreams {
+		u.lock.Lock()
+		status = append(status, ProxyUpstreamStatus{
+			Name:    u.Name,
+			URL:     u.URL,
+			Weight:  u.Weight,
+			Healthy: u.healthy,
+			Breaker: u.state.String(),
+		})
+		u.lock.Unlock()
+	}
+	return status
+}
+
+// Close stops the health checks and disconnects from all upstreams.
+func (p *ProxyPool) Close() {
+	close(p.quit)
+	p.wg.Wait()
+	p.closeClients()
+}
+
+func (p *ProxyPool) closeClients() {
+	for _, u := range p.upstreams {
+		u.lock.Lock()
+		if u.client != nil {
+			u.client.Close()
+		}
+		u.lock.Unlock()
+	}
+}
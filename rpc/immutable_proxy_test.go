@@ -0,0 +1,228 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyUpstream serves the test service, answering 503 while down.
+type flakyUpstream struct {
+	*httptest.Server
+	down  atomic.Bool
+	calls atomic.Int64
+}
+
+func newFlakyUpstream(t *testing.T) *flakyUpstream {
+	u := new(flakyUpstream)
+	server := newTestServer()
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.calls.Add(1)
+		if u.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		u.Close()
+		server.Stop()
+	})
+	return u
+}
+
+func TestImmutableParseProxyUpstream(t *testing.T) {
+	u, err := ParseProxyUpstream("https://rpc.example.com;weight=3;name=primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.URL != "https://rpc.example.com" || u.Weight != 3 || u.Name != "primary" {
+		t.Fatalf("unexpected upstream %+v", u)
+	}
+	u, err = ParseProxyUpstream("http://localhost:8545")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Weight != 1 || u.Name != "" {
+		t.Fatalf("unexpected upstream %+v", u)
+	}
+	for _, invalid := range []string{"", ";weight=1", "http://a;weight=0", "http://a;weight", "http://a;foo=bar"} {
+		if _, err := ParseProxyUpstream(invalid); err == nil {
+			t.Errorf("expected error parsing %q", invalid)
+		}
+	}
+}
+
+func TestImmutableProxyPool_Failover(t *testing.T) {
+	var (
+		dead  = newFlakyUpstream(t)
+		alive = newFlakyUpstream(t)
+	)
+	dead.down.Store(true)
+
+	pool, err := NewProxyPool(context.Background(), ProxyConfig{
+		Upstreams: []ProxyUpstream{
+			{Name: "dead", URL: dead.URL, Weight: 1 << 20}, // Practically always picked first
+			{Name: "alive", URL: alive.URL, Weight: 1},
+		},
+		Retries:          1,
+		HealthInterval:   time.Hour,
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	for i := 0; i < 10; i++ {
+		var result string
+		if err := pool.CallContext(context.Background(), &result, "test_repeat", "x", 2); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+		if result != "xx" {
+			t.Fatalf("unexpected result %q", result)
+		}
+	}
+	// The dead upstream is skipped once its circuit breaker opened
+	if calls := dead.calls.Load(); calls != 2 {
+		t.Fatalf("expected 2 calls to the dead upstream, got %d", calls)
+	}
+	status := pool.Status()
+	if status[0].Breaker != "open" || status[1].Breaker != "closed" {
+		t.Fatalf("unexpected breaker states %+v", status)
+	}
+	// JSON-RPC errors are answers and must not be retried
+	before := alive.calls.Load()
+	if err := pool.CallContext(context.Background(), nil, "test_returnError"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls := alive.calls.Load() - before; calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestImmutableProxyPool_HealthCheckRecovery(t *testing.T) {
+	upstream := newFlakyUpstream(t)
+
+	pool, err := NewProxyPool(context.Background(), ProxyConfig{
+		Upstreams:        []ProxyUpstream{{URL: upstream.URL}},
+		HealthInterval:   20 * time.Millisecond,
+		HealthMethod:     "test_null",
+		FailureThreshold: 1,
+		OpenTimeout:      time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	upstream.down.Store(true)
+	if err := pool.CallContext(context.Background(), nil, "test_null"); err == nil {
+		t.Fatal("expected error while the upstream is down")
+	}
+	if err := pool.CallContext(context.Background(), nil, "test_null"); err != ErrNoProxyUpstream {
+		t.Fatalf("expected %v, got %v", ErrNoProxyUpstream, err)
+	}
+	// The health check closes the breaker once the upstream is back
+	upstream.down.Store(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if err := pool.CallContext(context.Background(), nil, "test_null"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("upstream did not recover")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if status := pool.Status(); !status[0].Healthy || status[0].Breaker != "closed" || status[0].Name != "upstream0" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestImmutableProxyPool_ResubmittedTransaction(t *testing.T) {
+	// The slow upstream accepts the transaction but answers after the call
+	// timeout, the other one then refuses the retry as already known.
+	var slowCalls atomic.Int64
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowCalls.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x"}`))
+	}))
+	defer slow.Close()
+	known := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"already known"}}`))
+	}))
+	defer known.Close()
+
+	newPool := func(upstreams ...ProxyUpstream) *ProxyPool {
+		pool, err := NewProxyPool(context.Background(), ProxyConfig{
+			Upstreams:      upstreams,
+			Retries:        1,
+			CallTimeout:    50 * time.Millisecond,
+			HealthInterval: time.Hour,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pool
+	}
+	pool := newPool(
+		ProxyUpstream{Name: "slow", URL: slow.URL, Weight: 1 << 20}, // Practically always picked first
+		ProxyUpstream{Name: "known", URL: known.URL, Weight: 1},
+	)
+	defer pool.Close()
+
+	if err := pool.CallContext(context.Background(), nil, "eth_sendRawTransaction", "0x00"); err != nil {
+		t.Fatalf("resubmitted transaction failed: %v", err)
+	}
+	if calls := slowCalls.Load(); calls != 1 {
+		t.Fatalf("expected 1 call to the slow upstream, got %d", calls)
+	}
+	// Without a previous attempt, or for other methods, the error is an answer
+	if err := pool.CallContext(context.Background(), nil, "eth_call", "0x00"); err == nil {
+		t.Fatal("expected error for a retried call to another method")
+	}
+	single := newPool(ProxyUpstream{Name: "known", URL: known.URL})
+	defer single.Close()
+	if err := single.CallContext(context.Background(), nil, "eth_sendRawTransaction", "0x00"); err == nil {
+		t.Fatal("expected error for a transaction known on the first attempt")
+	}
+
+	// A retry refused for its nonce may have lost against another transaction
+	nonceTooLow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"nonce too low: address 0x01, tx: 0 state: 1"}}`))
+	}))
+	defer nonceTooLow.Close()
+	replaced := newPool(
+		ProxyUpstream{Name: "slow", URL: slow.URL, Weight: 1 << 20},
+		ProxyUpstream{Name: "nonce", URL: nonceTooLow.URL, Weight: 1},
+	)
+	defer replaced.Close()
+	if err := replaced.CallContext(context.Background(), nil, "eth_sendRawTransaction", "0x00"); err == nil {
+		t.Fatal("expected error for a retry refused with nonce too low")
+	}
+}
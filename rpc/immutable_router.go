@@ -0,0 +1,224 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// LocalUpstream is the upstream of routes whose calls are handled locally.
+const LocalUpstream = "local"
+
+// localOnlyMethods are the methods using the node's own accounts. They are
+// never forwarded: an upstream would receive the call unsigned, whereas the
+// local handler signs the transaction and submits it as a raw transaction.
+var localOnlyMethods = map[string]bool{
+	"eth_sendTransaction": true,
+	"eth_signTransaction": true,
+	"eth_sign":            true,
+}
+
+// ForwardingRoute maps calls to the upstream answering them. Match is either a
+// method name (eth_call) or a namespace wildcard (txpool_*). If BlockTag is set,
+// only calls with that block tag among their parameters match, e.g. "pending".
+type ForwardingRoute struct {
+	Match    string
+	BlockTag string `toml:",omitempty"`
+	Upstream string // Name of a proxy upstream, or "local"
+}
+
+// ForwardingConfig is the routing table of an RPC server. Calls are matched
+// against the routes in order and the first match wins, so local exceptions can
+// precede a namespace wildcard. Unmatched calls are handled locally, and so are
+// the methods signing with the node's accounts, such as eth_sendTransaction.
+type ForwardingConfig struct {
+	Proxy  ProxyConfig
+	Routes []ForwardingRoute
+}
+
+// forwardingRoute is a route along with its metrics.
+type forwardingRoute struct {
+	ForwardingRoute
+
+	namespace string       // Set for namespace wildcards
+	blockTag  *BlockNumber // Decoded BlockTag, nil if calls match regardless of their block
+
+	requestMeter metrics.Meter
+	failureMeter metrics.Meter
+	timer        metrics.Timer
+}
+
+func (r *forwardingRoute) matches(msg *jsonrpcMessage) bool {
+	if r.namespace != "" {
+		if msg.namespace() != r.namespace || (r.Upstream != LocalUpstream && localOnlyMethods[msg.Method]) {
+			return false
+		}
+	} else if msg.Method != r.Match {
+		return false
+	}
+	if r.blockTag == nil {
+		return true
+	}
+	var params []json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return false
+	}
+	for _, param := range params {
+		var block BlockNumberOrHash
+		if err := json.Unmarshal(param, &block); err != nil {
+			continue
+		}
+		if number, ok := block.Number(); ok && number == *r.blockTag {
+			return true
+		}
+	}
+	return false
+}
+
+// Router forwards the calls matching its routes to the proxy upstreams.
+type Router struct {
+	pool   *ProxyPool
+	routes []*forwardingRoute
+}
+
+// NewRouter validates the routing table and connects to the upstreams.
+func NewRouter(ctx context.Context, config ForwardingConfig) (*Router, error) {
+	if len(config.Routes) == 0 {
+		return nil, errors.New("no forwarding routes configured")
+	}
+	r := new(Router)
+	for _, cfg := range config.Routes {
+		route := &forwardingRoute{ForwardingRoute: cfg}
+		switch {
+		case cfg.Match == "":
+			return nil, errors.New("forwarding route without match")
+		case strings.HasSuffix(cfg.Match, serviceMethodSeparator+"*"):
+			route.namespace = strings.TrimSuffix(cfg.Match, serviceMethodSeparator+"*")
+		case strings.Contains(cfg.Match, "*"):
+			return nil, fmt.Errorf("invalid forwarding route match %q", cfg.Match)
+		}
+		if cfg.BlockTag != "" {
+			tag, _ := json.Marshal(cfg.BlockTag)
+			route.blockTag = new(BlockNumber)
+			if err := route.blockTag.UnmarshalJSON(tag); err != nil {
+				return nil, fmt.Errorf("invalid forwarding route block tag %q: %v", cfg.BlockTag, err)
+			}
+		}
+		if cfg.Upstream == "" {
+			return nil, fmt.Errorf("forwarding route %q without upstream", cfg.Match)
+		}
+		if localOnlyMethods[cfg.Match] && cfg.Upstream != LocalUpstream {
+			return nil, fmt.Errorf("forwarding route %q must be handled locally to sign with the node's accounts", cfg.Match)
+		}
+		name := strings.ReplaceAll(cfg.Match, "*", "all")
+		if cfg.BlockTag != "" {
+			name += "_" + cfg.BlockTag
+		}
+		prefix := "rpc/route/" + name + "/"
+		route.requestMeter = metrics.GetOrRegisterMeter(prefix+"requests", nil)
+		route.failureMeter = metrics.GetOrRegisterMeter(prefix+"failures", nil)
+		route.timer = metrics.GetOrRegisterTimer(prefix+"duration", nil)
+		r.routes = append(r.routes, route)
+	}
+	// Only connect to the upstreams if any call is forwarded
+	for _, route := range r.routes {
+		if route.Upstream == LocalUpstream {
+			continue
+		}
+		if r.pool == nil {
+			pool, err := NewProxyPool(ctx, config.Proxy)
+			if err != nil {
+				return nil, err
+			}
+			r.pool = pool
+		}
+		if !r.pool.hasUpstream(route.Upstream) {
+			r.pool.Close()
+			return nil, fmt.Errorf("forwarding route %q refers to unknown upstream %q", route.Match, route.Upstream)
+		}
+	}
+	return r, nil
+}
+
+// handle forwards the call if it matches a route to an upstream. It returns
+// false if the call is to be handled locally.
+func (r *Router) handle(ctx context.Context, msg *jsonrpcMessage) (*jsonrpcMessage, bool) {
+	var route *forwardingRoute
+	for _, candidate := range r.routes {
+		if candidate.matches(msg) {
+			route = candidate
+			break
+		}
+	}
+	if route == nil {
+		return nil, false
+	}
+	route.requestMeter.Mark(1)
+	if route.Upstream == LocalUpstream {
+		return nil, false
+	}
+	var params []json.RawMessage
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return msg.errorResponse(&invalidParamsError{err.Error()}), true
+		}
+	}
+	args := make([]interface{}, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+	start := time.Now()
+	var result json.RawMessage
+	err := r.pool.callUpstream(ctx, route.Upstream, &result, msg.Method, args...)
+	route.timer.UpdateSince(start)
+	if err != nil {
+		route.failureMeter.Mark(1)
+		return msg.errorResponse(err), true
+	}
+	return msg.response(result), true
+}
+
+// Close disconnects from the upstreams.
+func (r *Router) Close() {
+	if r.pool != nil {
+		r.pool.Close()
+	}
+}
+
+// SetRouter installs the routing table applied to the calls served by the
+// server. It must be called before serving any requests.
+func (s *Server) SetRouter(router *Router) {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	s.services.router = router
+}
+
+// forwarder returns the router of the registry, if any.
+func (r *serviceRegistry) forwarder() *Router {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.router
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestImmutableRouter(t *testing.T) {
+	upstream := newFlakyUpstream(t)
+
+	router, err := NewRouter(context.Background(), ForwardingConfig{
+		Proxy: ProxyConfig{
+			Upstreams:      []ProxyUpstream{{Name: "sequencer", URL: upstream.URL}},
+			HealthInterval: time.Hour,
+		},
+		Routes: []ForwardingRoute{
+			{Match: "test_repeat", BlockTag: "pending", Upstream: "sequencer"},
+			{Match: "test_repeat", Upstream: LocalUpstream},
+			{Match: "nftest_*", Upstream: "sequencer"},
+			{Match: "test_returnError", Upstream: "sequencer"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer router.Close()
+
+	// The local server does not serve the nftest namespace
+	local := NewServer()
+	if err := local.RegisterName("test", new(testService)); err != nil {
+		t.Fatal(err)
+	}
+	local.SetRouter(router)
+	httpsrv := httptest.NewServer(local)
+	defer httpsrv.Close()
+
+	client, err := DialHTTP(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	call := func(result interface{}, method string, args ...interface{}) (int64, error) {
+		before := upstream.calls.Load()
+		err := client.Call(result, method, args...)
+		return upstream.calls.Load() - before, err
+	}
+	// Block tag routes only match calls with the tag
+	var result string
+	if forwarded, err := call(&result, "test_repeat", "pending", 2); err != nil || forwarded != 1 || result != "pendingpending" {
+		t.Fatalf("expected forwarded call, got forwarded=%d result=%q err=%v", forwarded, result, err)
+	}
+	if forwarded, err := call(&result, "test_repeat", "x", 2); err != nil || forwarded != 0 || result != "xx" {
+		t.Fatalf("expected local call, got forwarded=%d result=%q err=%v", forwarded, result, err)
+	}
+	if forwarded, err := call(&result, "test_repeat", "latest", 2); err != nil || forwarded != 0 || result != "latestlatest" {
+		t.Fatalf("expected local call, got forwarded=%d result=%q err=%v", forwarded, result, err)
+	}
+	// Namespace routes forward methods unknown locally
+	var n int
+	if forwarded, err := call(&n, "nftest_echo", 7); err != nil || forwarded != 1 || n != 7 {
+		t.Fatalf("expected forwarded call, got forwarded=%d result=%d err=%v", forwarded, n, err)
+	}
+	// Upstream errors are relayed with their code and data
+	forwarded, err := call(nil, "test_returnError")
+	var rpcErr Error
+	if forwarded != 1 || !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != 444 {
+		t.Fatalf("expected forwarded error 444, got forwarded=%d err=%v", forwarded, err)
+	}
+	// Unmatched calls are handled locally
+	if forwarded, err := call(nil, "test_null"); err != nil || forwarded != 0 {
+		t.Fatalf("expected local call, got forwarded=%d err=%v", forwarded, err)
+	}
+}
+
+func TestImmutableRouter_UnknownUpstream(t *testing.T) {
+	upstream := newFlakyUpstream(t)
+
+	_, err := NewRouter(context.Background(), ForwardingConfig{
+		Proxy:  ProxyConfig{Upstreams: []ProxyUpstream{{Name: "sequencer", URL: upstream.URL}}},
+		Routes: []ForwardingRoute{{Match: "eth_call", Upstream: "archive"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown upstream")
+	}
+	for _, invalid := range []ForwardingRoute{{Upstream: LocalUpstream}, {Match: "eth_*call", Upstream: LocalUpstream}, {Match: "eth_call"}, {Match: "eth_sendTransaction", Upstream: "sequencer"}, {Match: "eth_call", BlockTag: "soon", Upstream: LocalUpstream}} {
+		if _, err := NewRouter(context.Background(), ForwardingConfig{Routes: []ForwardingRoute{invalid}}); err == nil {
+			t.Errorf("expected error for route %+v", invalid)
+		}
+	}
+}
+
+func TestImmutableRouter_BlockTagEncodings(t *testing.T) {
+	pending := PendingBlockNumber
+	route := &forwardingRoute{ForwardingRoute: ForwardingRoute{Match: "eth_call", BlockTag: "pending", Upstream: "sequencer"}, blockTag: &pending}
+	for _, params := range []string{
+		`[{}, "pending"]`,
+		`[{}, {"blockNumber": "pending"}]`,
+		`[{}, { "blockNumber" : "pending" }]`,
+	} {
+		if !route.matches(&jsonrpcMessage{Method: "eth_call", Params: json.RawMessage(params)}) {
+			t.Errorf("expected %s to match the pending block tag", params)
+		}
+	}
+	for _, params := range []string{
+		`[{}, "latest"]`,
+		`[{}, {"blockNumber": "0x10"}]`,
+		`[{}, {"blockHash": "0x8b1d8bd7f7b2f9f6f1e7b2f3b8a4e2d3c9b1a0f7e6d5c4b3a29180706050403a"}]`,
+	} {
+		if route.matches(&jsonrpcMessage{Method: "eth_call", Params: json.RawMessage(params)}) {
+			t.Errorf("expected %s not to match the pending block tag", params)
+		}
+	}
+
+	number := BlockNumber(0x10)
+	route.blockTag = &number
+	if !route.matches(&jsonrpcMessage{Method: "eth_call", Params: json.RawMessage(`[{}, "0x10"]`)}) {
+		t.Error("expected block number to match regardless of its encoding")
+	}
+}
+
+func TestImmutableRouter_LocalOnlyMethods(t *testing.T) {
+	wildcard := &forwardingRoute{ForwardingRoute: ForwardingRoute{Match: "eth_*", Upstream: "sequencer"}, namespace: "eth"}
+	if !wildcard.matches(&jsonrpcMessage{Method: "eth_sendRawTransaction"}) {
+		t.Fatal("expected eth_sendRawTransaction to be forwarded")
+	}
+	for method := range localOnlyMethods {
+		if wildcard.matches(&jsonrpcMessage{Method: method}) {
+			t.Errorf("%s forwarded by namespace route", method)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer returns the tracer creating the spans of served calls. It uses the
+// global tracer provider, which is a no-op unless the node configures an
+// exporter, and is looked up on every use as the provider is replaced by every
+// node started with tracing.
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/ethereum/go-ethereum/rpc")
+}
+
+// connContext returns the root context of the calls served on a connection,
+// carrying the remote trace context of the websocket upgrade request, if any.
+func connContext(conn ServerCodec) context.Context {
+	if wc, ok := conn.(*websocketCodec); ok && wc.spanContext.IsValid() {
+		return trace.ContextWithRemoteSpanContext(context.Background(), wc.spanContext)
+	}
+	return context.Background()
+}
+
+// startBatchSpan starts the span covering all calls of a batch request.
+func startBatchSpan(ctx context.Context, calls int) (context.Context, trace.Span) {
+	return tracer().Start(ctx, "jsonrpc.batch",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.Int("rpc.jsonrpc.batch_size", calls),
+		),
+	)
+}
+
+// handleTracedCallMsg executes a call message within its own span. Calls that
+// are part of a batch become children of the batch span.
+func (h *handler) handleTracedCallMsg(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if !msg.isCall() && !msg.isNotification() {
+		return h.handleCallMsg(cp, msg)
+	}
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.service", msg.namespace()),
+		attribute.String("rpc.method", msg.Method),
+		attribute.String("rpc.jsonrpc.version", vsn),
+	}
+	if msg.isCall() {
+		attrs = append(attrs, attribute.String("rpc.jsonrpc.request_id", string(msg.ID)))
+	}
+	ctx, span := tracer().Start(cp.ctx, msg.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	// The call runs on its own callProc so that the timeout handlers, which
+	// read cp.ctx concurrently, are unaffected. Subscriptions are handed back.
+	traced := &callProc{ctx: ctx}
+	resp := h.handleCallMsg(traced, msg)
+	cp.notifiers = append(cp.notifiers, traced.notifiers...)
+
+	if resp != nil && resp.Error != nil {
+		span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", resp.Error.Code))
+		span.SetAttributes(attribute.String("rpc.jsonrpc.error_message", resp.Error.Message))
+		span.SetStatus(codes.Error, resp.Error.Message)
+	}
+	return resp
+}
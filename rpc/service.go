@@ -38,6 +38,7 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	router   *Router // CHANGE(immutable): forwards matching calls upstream
 }
 
 // service represents a registered object.
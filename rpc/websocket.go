@@ -30,6 +30,7 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -61,6 +62,9 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
+		// CHANGE(immutable): serve the calls of the connection within the trace
+		// of the upgrade request
+		codec.(*websocketCodec).spanContext = trace.SpanContextFromContext(r.Context())
 		s.ServeCodec(codec, 0)
 	})
 }
@@ -282,6 +286,9 @@ type websocketCodec struct {
 	conn *websocket.Conn
 	info PeerInfo
 
+	// CHANGE(immutable): remote trace context of the upgrade request
+	spanContext trace.SpanContext
+
 	wg           sync.WaitGroup
 	pingReset    chan struct{}
 	pongReceived chan struct{}
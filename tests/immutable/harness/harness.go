@@ -0,0 +1,733 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package harness runs a local Immutable network of boot, validator and RPC
+// nodes inside a single process. Nodes use in-memory databases and are linked
+// with in-memory pipes, so tests need neither ports nor binaries. Blocks are
+// only sealed when the test asks for them, which keeps the chain deterministic.
+package harness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/immutable"
+	"github.com/ethereum/go-ethereum/cmd/geth/immutable/genesis"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// DefaultChainID is used when no chain ID is configured. It deliberately
+	// differs from the Immutable networks' IDs.
+	DefaultChainID = 1337
+
+	// DefaultGasLimit is used when no genesis gas limit is configured.
+	DefaultGasLimit = 30_000_000
+
+	// DefaultTimeout is used when no timeout is configured.
+	DefaultTimeout = 10 * time.Second
+)
+
+var (
+	errNoProducer = errors.New("no validator is allowed to seal the next block")
+	errNotRunning = errors.New("node is not running")
+	errRunning    = errors.New("node is already running")
+)
+
+// Role is the role of a node in the network
+type Role int
+
+const (
+	BootRole Role = iota
+	ValidatorRole
+	RPCRole
+)
+
+func (r Role) String() string {
+	switch r {
+	case BootRole:
+		return "boot"
+	case ValidatorRole:
+		return "validator"
+	case RPCRole:
+		return "rpc"
+	default:
+		return fmt.Sprintf("role(%d)", int(r))
+	}
+}
+
+// Options configures the network
+type Options struct {
+	Boot       int
+	Validators int
+	RPC        int
+
+	ChainID  int
+	GasLimit uint64
+	// SecondsPerBlock is the clique period. Clique stamps blocks no earlier than
+	// the period after their parent and rejects blocks from the future, so with
+	// a period, sealing waits for it to pass since the parent block.
+	SecondsPerBlock uint64
+	Premines        []genesis.Premine
+
+	// Timeout bounds how long helpers wait for the network to converge.
+	Timeout time.Duration
+}
+
+// Node is a single geth node of the network. Its key is the node key, which
+// validators also seal with until their key in the secret store is rotated.
+type Node struct {
+	Name    string
+	Role    Role
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+
+	index int
+	self  *enode.Node
+	dbs   map[string]ethdb.Database
+
+	stack   *node.Node
+	backend *eth.Ethereum
+}
+
+// Running returns whether the node is running
+func (n *Node) Running() bool {
+	return n.stack != nil
+}
+
+// Stack returns the node's stack, or nil if it is not running
+func (n *Node) Stack() *node.Node {
+	return n.stack
+}
+
+// Eth returns the node's eth backend, or nil if it is not running
+func (n *Node) Eth() *eth.Ethereum {
+	return n.backend
+}
+
+// Signer returns the address the node currently seals with
+func (n *Node) Signer() (common.Address, error) {
+	if !n.Running() {
+		return common.Address{}, errNotRunning
+	}
+	return n.backend.Etherbase()
+}
+
+// Head returns the header of the node's current block
+func (n *Node) Head() *types.Header {
+	return n.backend.BlockChain().CurrentBlock()
+}
+
+// Client returns a client attached to the node's in-process RPC server
+func (n *Node) Client() (*ethclient.Client, error) {
+	if !n.Running() {
+		return nil, errNotRunning
+	}
+	return ethclient.NewClient(n.stack.Attach()), nil
+}
+
+// Propose adds a clique vote to authorize or deauthorize the address. The vote
+// is cast in the blocks the node seals from then on.
+func (n *Node) Propose(address common.Address, auth bool) error {
+	api, err := n.cliqueAPI()
+	if err != nil {
+		return err
+	}
+	api.Propose(address, auth)
+	return nil
+}
+
+// Snapshot returns the clique voting snapshot at the node's current block
+func (n *Node) Snapshot() (*clique.Snapshot, error) {
+	api, err := n.cliqueAPI()
+	if err != nil {
+		return nil, err
+	}
+	return api.GetSnapshot(nil)
+}
+
+func (n *Node) clique() (*clique.Clique, error) {
+	if !n.Running() {
+		return nil, errNotRunning
+	}
+	engine := n.backend.Engine()
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	c, ok := engine.(*clique.Clique)
+	if !ok {
+		return nil, fmt.Errorf("unexpected consensus engine %T", engine)
+	}
+	return c, nil
+}
+
+func (n *Node) cliqueAPI() (*clique.API, error) {
+	c, err := n.clique()
+	if err != nil {
+		return nil, err
+	}
+	for _, api := range c.APIs(n.backend.BlockChain()) {
+		if service, ok := api.Service.(*clique.API); ok {
+			return service, nil
+		}
+	}
+	return nil, errors.New("clique API not found")
+}
+
+// database returns the node's database of the given name. It is kept across
+// restarts so that a restarted node resumes from its previous chain.
+func (n *Node) database(name string) ethdb.Database {
+	db, ok := n.dbs[name]
+	if !ok {
+		db = persistentDB{rawdb.NewMemoryDatabase()}
+		n.dbs[name] = db
+	}
+	return db
+}
+
+// persistentDB ignores closing so the database outlives its node
+type persistentDB struct {
+	ethdb.Database
+}
+
+func (persistentDB) Close() error { return nil }
+
+// link is an undirected connection between two nodes, by index
+type link struct {
+	a, b int
+}
+
+func newLink(a, b int) link {
+	if a > b {
+		a, b = b, a
+	}
+	return link{a, b}
+}
+
+// Network is a set of in-process nodes sharing a clique genesis
+type Network struct {
+	Genesis *core.Genesis
+	Nodes   []*Node
+
+	// Secrets holds the validators' sealing keys under their node names
+	Secrets *SecretStore
+
+	opts    Options
+	lock    sync.Mutex
+	blocked map[link]struct{}
+}
+
+// New creates and starts a network with the given number of nodes per role.
+// Every node is linked to the boot nodes, and validators are linked to each
+// other. Without boot nodes, every node is linked to the validators instead.
+func New(opts Options) (*Network, error) {
+	if opts.Validators == 0 {
+		return nil, errors.New("at least one validator is required")
+	}
+	if opts.ChainID == 0 {
+		opts.ChainID = DefaultChainID
+	}
+	if opts.GasLimit == 0 {
+		opts.GasLimit = DefaultGasLimit
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	network := &Network{
+		Secrets: newSecretStore(),
+		opts:    opts,
+		blocked: make(map[link]struct{}),
+	}
+	for _, role := range []struct {
+		role  Role
+		count int
+	}{{BootRole, opts.Boot}, {ValidatorRole, opts.Validators}, {RPCRole, opts.RPC}} {
+		for i := 0; i < role.count; i++ {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				return nil, err
+			}
+			network.Nodes = append(network.Nodes, &Node{
+				Name:    fmt.Sprintf("%s-%d", role.role, i),
+				Role:    role.role,
+				Key:     key,
+				Address: crypto.PubkeyToAddress(key.PublicKey),
+				index:   len(network.Nodes),
+				self:    enode.NewV4(&key.PublicKey, net.IP{127, 0, 0, 1}, 0, 0),
+				dbs:     make(map[string]ethdb.Database),
+			})
+		}
+	}
+	var validators []common.Address
+	for _, v := range network.Validators() {
+		if err := network.Secrets.putKey(v.Name, v.Key); err != nil {
+			return nil, err
+		}
+		validators = append(validators, v.Address)
+	}
+	network.Genesis = genesis.Clique(genesis.CliqueOptions{
+		ChainID:         opts.ChainID,
+		GasLimit:        opts.GasLimit,
+		SecondsPerBlock: opts.SecondsPerBlock,
+		Validators:      validators,
+		Premines:        opts.Premines,
+	})
+	for _, n := range network.Nodes {
+		if err := network.Restart(n); err != nil {
+			network.Close()
+			return nil, fmt.Errorf("failed to start %s: %w", n.Name, err)
+		}
+	}
+	return network, nil
+}
+
+// Boot returns the boot nodes
+func (nw *Network) Boot() []*Node {
+	return nw.byRole(BootRole)
+}
+
+// Validators returns the validator nodes
+func (nw *Network) Validators() []*Node {
+	return nw.byRole(ValidatorRole)
+}
+
+// RPC returns the RPC nodes
+func (nw *Network) RPC() []*Node {
+	return nw.byRole(RPCRole)
+}
+
+func (nw *Network) byRole(role Role) []*Node {
+	var nodes []*Node
+	for _, n := range nw.Nodes {
+		if n.Role == role {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Close stops all running nodes
+func (nw *Network) Close() {
+	for _, n := range nw.Nodes {
+		if n.Running() {
+			nw.Kill(n)
+		}
+	}
+}
+
+// linked returns whether the topology links the two nodes
+func (nw *Network) linked(a, b *Node) bool {
+	switch {
+	case a == b:
+		return false
+	case a.Role == BootRole || b.Role == BootRole:
+		return true
+	case a.Role == ValidatorRole && b.Role == ValidatorRole:
+		return true
+	case nw.opts.Boot == 0:
+		return a.Role == ValidatorRole || b.Role == ValidatorRole
+	default:
+		return false
+	}
+}
+
+// neighbours returns the running nodes that n is currently connected to, or
+// would be once running.
+func (nw *Network) neighbours(n *Node) []*Node {
+	nw.lock.Lock()
+	defer nw.lock.Unlock()
+
+	var nodes []*Node
+	for _, m := range nw.Nodes {
+		if _, blocked := nw.blocked[newLink(n.index, m.index)]; !blocked && m.Running() && nw.linked(n, m) {
+			nodes = append(nodes, m)
+		}
+	}
+	return nodes
+}
+
+// degree returns how many nodes the topology links n to. It bounds the
+// node's peer count, which also lets it sync as soon as all its peers are
+// connected.
+func (nw *Network) degree(n *Node) int {
+	var degree int
+	for _, m := range nw.Nodes {
+		if nw.linked(n, m) {
+			degree++
+		}
+	}
+	if degree == 0 {
+		degree = 1
+	}
+	return degree
+}
+
+// Kill stops the node, keeping its databases for a later restart
+func (nw *Network) Kill(n *Node) error {
+	if !n.Running() {
+		return errNotRunning
+	}
+	err := n.stack.Close()
+	n.stack, n.backend = nil, nil
+	return err
+}
+
+// Restart starts a stopped node with its previous key and databases, and
+// links it to its running neighbours. Validators load their sealing key from
+// the secret store, like they do in a deployment.
+func (nw *Network) Restart(n *Node) error {
+	if n.Running() {
+		return errRunning
+	}
+	stack, err := node.New(&node.Config{
+		Name: "geth",
+		P2P: p2p.Config{
+			PrivateKey:  n.Key,
+			MaxPeers:    nw.degree(n),
+			NoDiscovery: true,
+			NoDial:      true,
+		},
+		MemoryDatabase: n.database,
+	})
+	if err != nil {
+		return err
+	}
+	config := ethconfig.Defaults
+	config.Genesis = nw.Genesis
+	config.NetworkId = uint64(nw.opts.ChainID)
+	config.SyncMode = downloader.FullSync
+	config.NoPruning = true
+	config.StateScheme = rawdb.HashScheme
+
+	var wallet accounts.Wallet
+	if n.Role == ValidatorRole {
+		accountsBackend, err := immutable.NewBackend(context.Background(), secretKey{nw.Secrets, n.Name})
+		if err != nil {
+			stack.Close()
+			return err
+		}
+		stack.AccountManager().AddBackend(accountsBackend)
+		wallet = accountsBackend.Wallets()[0]
+		config.Miner.Etherbase = wallet.Accounts()[0].Address
+	}
+	backend, err := eth.New(stack, &config)
+	if err != nil {
+		stack.Close()
+		return err
+	}
+	if err := stack.Start(); err != nil {
+		stack.Close()
+		return err
+	}
+	n.stack, n.backend = stack, backend
+
+	// Nodes are only linked to each other once the network is up, so there
+	// is no initial sync to wait for before accepting propagated blocks
+	backend.SetSynced()
+	if n.Role == ValidatorRole {
+		c, err := n.clique()
+		if err != nil {
+			nw.Kill(n)
+			return err
+		}
+		c.Authorize(wallet.Accounts()[0].Address, wallet.SignData)
+	}
+	for _, m := range nw.neighbours(n) {
+		if err := nw.connect(n, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connect links two running nodes with a pipe and waits for their eth
+// handshake to complete.
+func (nw *Network) connect(a, b *Node) error {
+	c1, c2 := net.Pipe()
+	errc := make(chan error, 1)
+	go func() { errc <- a.stack.Server().SetupConn(c1, 0, b.self) }()
+	if err := b.stack.Server().SetupConn(c2, 0, nil); err != nil {
+		return fmt.Errorf("failed to link %s and %s: %w", a.Name, b.Name, err)
+	}
+	if err := <-errc; err != nil {
+		return fmt.Errorf("failed to link %s and %s: %w", a.Name, b.Name, err)
+	}
+	return nw.wait(func() bool {
+		return peered(a, b, true) && peered(b, a, true)
+	}, "%s and %s to complete their handshake", a.Name, b.Name)
+}
+
+// peered returns whether a is connected to b, and if requested, has completed
+// the eth handshake with it.
+func peered(a, b *Node, handshake bool) bool {
+	if !a.Running() {
+		return false
+	}
+	for _, info := range a.stack.Server().PeersInfo() {
+		if info.ID != b.self.ID().String() {
+			continue
+		}
+		if !handshake {
+			return true
+		}
+		status, ok := info.Protocols["eth"]
+		if !ok {
+			return false
+		}
+		_, pending := status.(string)
+		return !pending
+	}
+	return false
+}
+
+// Partition disconnects every node of the first group from every node of the
+// second one, and keeps them apart until the network is healed.
+func (nw *Network) Partition(a, b []*Node) error {
+	var pairs [][2]*Node
+	nw.lock.Lock()
+	for _, x := range a {
+		for _, y := range b {
+			if nw.linked(x, y) {
+				nw.blocked[newLink(x.index, y.index)] = struct{}{}
+				pairs = append(pairs, [2]*Node{x, y})
+			}
+		}
+	}
+	nw.lock.Unlock()
+
+	for _, pair := range pairs {
+		x, y := pair[0], pair[1]
+		if !x.Running() || !y.Running() {
+			continue
+		}
+		x.stack.Server().RemovePeer(y.self)
+		if err := nw.wait(func() bool {
+			return !peered(x, y, false) && !peered(y, x, false)
+		}, "%s and %s to disconnect", x.Name, y.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Heal removes all partitions and relinks the running nodes
+func (nw *Network) Heal() error {
+	nw.lock.Lock()
+	blocked := nw.blocked
+	nw.blocked = make(map[link]struct{})
+	nw.lock.Unlock()
+
+	for l := range blocked {
+		a, b := nw.Nodes[l.a], nw.Nodes[l.b]
+		if a.Running() && b.Running() && !peered(a, b, false) {
+			if err := nw.connect(a, b); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Seal makes the validator seal a block on top of its current block and
+// broadcast it to its peers, waiting for the clique period to pass since the
+// parent block if one is configured. It does not wait for the block to
+// propagate.
+func (nw *Network) Seal(v *Node) (*types.Block, error) {
+	if !v.Running() {
+		return nil, errNotRunning
+	}
+	signer, err := v.Signer()
+	if err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: signer}
+	wallet, err := v.stack.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	parent := v.Head()
+	if period := nw.opts.SecondsPerBlock; period > 0 {
+		time.Sleep(time.Until(time.Unix(int64(parent.Time+period), 0)))
+	}
+	block, err := v.backend.Miner().BuildBlock(parent.Hash(), uint64(time.Now().Unix()), signer)
+	if err != nil {
+		return nil, err
+	}
+	header := block.Header()
+	sig, err := wallet.SignData(account, accounts.MimetypeClique, clique.CliqueRLP(header))
+	if err != nil {
+		return nil, err
+	}
+	copy(header.Extra[len(header.Extra)-crypto.SignatureLength:], sig)
+	block = block.WithSeal(header)
+
+	if _, err := v.backend.BlockChain().InsertChain(types.Blocks{block}); err != nil {
+		return nil, err
+	}
+	if err := v.backend.EventMux().Post(core.NewMinedBlockEvent{Block: block}); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// AdvanceBlocks seals the given number of blocks, waiting for each to reach
+// every node connected to its sealer before sealing the next one. Each block
+// is sealed on the longest chain known to the running validators, or to the
+// given ones if any. The in-turn validator is preferred, falling back to the
+// first one allowed to seal.
+func (nw *Network) AdvanceBlocks(count int, validators ...*Node) ([]*types.Block, error) {
+	if len(validators) == 0 {
+		validators = nw.Validators()
+	}
+	var blocks []*types.Block
+	for i := 0; i < count; i++ {
+		v, err := producer(validators)
+		if err != nil {
+			return blocks, err
+		}
+		block, err := nw.Seal(v)
+		if err != nil {
+			return blocks, fmt.Errorf("%s failed to seal block: %w", v.Name, err)
+		}
+		blocks = append(blocks, block)
+
+		for _, n := range nw.reachable(v) {
+			if err := nw.WaitForBlock(n, block.Hash()); err != nil {
+				return blocks, err
+			}
+		}
+	}
+	return blocks, nil
+}
+
+// producer picks the validator to seal the next block
+func producer(validators []*Node) (*Node, error) {
+	var head *types.Header
+	for _, v := range validators {
+		if v.Running() && (head == nil || v.Head().Number.Cmp(head.Number) > 0) {
+			head = v.Head()
+		}
+	}
+	if head == nil {
+		return nil, errNoProducer
+	}
+	var fallback *Node
+	for _, v := range validators {
+		if !v.Running() || v.Head().Hash() != head.Hash() {
+			continue
+		}
+		signer, err := v.Signer()
+		if err != nil {
+			return nil, err
+		}
+		snap, err := v.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+		allowed, inturn := snap.CanSign(signer)
+		if inturn {
+			return v, nil
+		}
+		if allowed && fallback == nil {
+			fallback = v
+		}
+	}
+	if fallback == nil {
+		return nil, errNoProducer
+	}
+	return fallback, nil
+}
+
+// reachable returns the running nodes connected to n, directly or through
+// other nodes, including n itself.
+func (nw *Network) reachable(n *Node) []*Node {
+	seen := map[*Node]bool{n: true}
+	queue := []*Node{n}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, m := range nw.neighbours(next) {
+			if !seen[m] {
+				seen[m] = true
+				queue = append(queue, m)
+			}
+		}
+	}
+	var nodes []*Node
+	for _, m := range nw.Nodes {
+		if seen[m] {
+			nodes = append(nodes, m)
+		}
+	}
+	return nodes
+}
+
+// WaitForBlock waits until the node has imported the block
+func (nw *Network) WaitForBlock(n *Node, hash common.Hash) error {
+	return nw.wait(func() bool {
+		return n.Running() && n.backend.BlockChain().GetBlockByHash(hash) != nil
+	}, "%s to import block %s", n.Name, hash.TerminalString())
+}
+
+// Sync waits until all running nodes share the same current block
+func (nw *Network) Sync() error {
+	return nw.wait(func() bool {
+		var head common.Hash
+		for _, n := range nw.Nodes {
+			if !n.Running() {
+				continue
+			}
+			if hash := n.Head().Hash(); head == (common.Hash{}) {
+				head = hash
+			} else if hash != head {
+				return false
+			}
+		}
+		return true
+	}, "nodes to sync")
+}
+
+// wait polls the condition until it holds or the network's timeout elapses
+func (nw *Network) wait(cond func() bool, format string, args ...interface{}) error {
+	deadline := time.Now().Add(nw.opts.Timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for "+format, args...)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package harness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+func newNetwork(t *testing.T, opts Options) *Network {
+	t.Helper()
+	log.SetDefault(log.NewLogger(log.DiscardHandler()))
+	network, err := New(opts)
+	require.NoError(t, err)
+	t.Cleanup(network.Close)
+	return network
+}
+
+func TestNetwork_AdvanceBlocks(t *testing.T) {
+	network := newNetwork(t, Options{Boot: 1, Validators: 3, RPC: 1})
+
+	blocks, err := network.AdvanceBlocks(6)
+	require.NoError(t, err)
+	require.Len(t, blocks, 6)
+	require.NoError(t, network.Sync())
+
+	// Every block is sealed in-turn
+	for _, block := range blocks {
+		require.Equal(t, uint64(2), block.Difficulty().Uint64())
+	}
+	for _, n := range network.Nodes {
+		require.Equal(t, blocks[5].Hash(), n.Head().Hash(), n.Name)
+	}
+}
+
+func TestNetwork_AdvanceBlocksPeriod(t *testing.T) {
+	// Blocks sealed before the period passed would be queued as future blocks
+	// and miss the timeout
+	network := newNetwork(t, Options{Validators: 1, SecondsPerBlock: 1, Timeout: 3 * time.Second})
+
+	blocks, err := network.AdvanceBlocks(3)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+
+	// Blocks are stamped at least one period apart
+	for i := 1; i < len(blocks); i++ {
+		require.GreaterOrEqual(t, blocks[i].Time(), blocks[i-1].Time()+1)
+	}
+}
+
+func TestNetwork_PartitionHeal(t *testing.T) {
+	network := newNetwork(t, Options{Validators: 3, RPC: 1})
+	v := network.Validators()
+
+	_, err := network.AdvanceBlocks(2)
+	require.NoError(t, err)
+
+	require.NoError(t, network.Partition(v[:1], network.Nodes[1:]))
+	blocks, err := network.AdvanceBlocks(3, v[1:]...)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), v[0].Head().Number.Uint64())
+
+	require.NoError(t, network.Heal())
+	require.NoError(t, network.Sync())
+	require.Equal(t, blocks[2].Hash(), v[0].Head().Hash())
+}
+
+func TestNetwork_KillRestart(t *testing.T) {
+	network := newNetwork(t, Options{Validators: 3, RPC: 1})
+	v := network.Validators()
+
+	require.NoError(t, network.Kill(v[2]))
+	_, err := network.AdvanceBlocks(3)
+	require.NoError(t, err)
+
+	require.NoError(t, network.Restart(v[2]))
+	require.NoError(t, network.Sync())
+	require.Equal(t, uint64(3), v[2].Head().Number.Uint64())
+
+	_, err = network.AdvanceBlocks(3)
+	require.NoError(t, err)
+	require.NoError(t, network.Sync())
+}
+
+func TestNetwork_Vote(t *testing.T) {
+	network := newNetwork(t, Options{Validators: 2, RPC: 1})
+	candidate := common.Address{0x01}
+
+	// A majority of the validators have to vote for the candidate
+	for _, v := range network.Validators() {
+		require.NoError(t, v.Propose(candidate, true))
+	}
+	_, err := network.AdvanceBlocks(2)
+	require.NoError(t, err)
+	require.NoError(t, network.Sync())
+
+	snap, err := network.RPC()[0].Snapshot()
+	require.NoError(t, err)
+	require.Contains(t, snap.Signers, candidate)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The Immutable go-ethereum Authors
+// This file is part of the Immutable go-ethereum library.
+//
+// The Immutable go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Immutable go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Immutable go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package harness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SecretStore is an in-memory secret store holding the validators' sealing
+// keys as hex, keyed by node name. It implements keys.Store so that tooling
+// can rotate the keys the validators load on start and on reload.
+type SecretStore struct {
+	secrets map[string]string
+	lock    sync.Mutex
+}
+
+func newSecretStore() *SecretStore {
+	return &SecretStore{secrets: make(map[string]string)}
+}
+
+// PutSecretString stores the secret under the ID
+func (s *SecretStore) PutSecretString(id, content string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.secrets[id] = content
+	return nil
+}
+
+// GetSecret returns the secret stored under the ID
+func (s *SecretStore) GetSecret(ctx context.Context, id string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	secret, ok := s.secrets[id]
+	if !ok {
+		return "", fmt.Errorf("secret %s not found", id)
+	}
+	return secret, nil
+}
+
+func (s *SecretStore) putKey(id string, key *ecdsa.PrivateKey) error {
+	return s.PutSecretString(id, hex.EncodeToString(crypto.FromECDSA(key)))
+}
+
+// secretKey exposes a single key of the store as an accounts secret store
+type secretKey struct {
+	store *SecretStore
+	id    string
+}
+
+func (k secretKey) GetPrivateKey(ctx context.Context) (*ecdsa.PrivateKey, error) {
+	secret, err := k.store.GetSecret(ctx, k.id)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.HexToECDSA(secret)
+}